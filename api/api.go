@@ -1,10 +1,12 @@
 package api
 
-//go:generate moq -out mocks.go -pkg api . ElasticSearcher DpElasticSearcher QueryParamValidator QueryBuilder ReleaseQueryBuilder ResponseTransformer AuthHandler ReleaseResponseTransformer
+//go:generate moq -out mocks.go -pkg api . ElasticSearcher DpElasticSearcher QueryParamValidator QueryBuilder ReleaseQueryBuilder SuggestQueryBuilder ResponseTransformer AuthHandler ReleaseResponseTransformer SuggestResponseTransformer ResponseCache
 
 import (
 	"context"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/ONSdigital/dp-api-clients-go/v2/nlp/berlin"
 	"github.com/ONSdigital/dp-api-clients-go/v2/nlp/category"
@@ -12,20 +14,27 @@ import (
 	"github.com/ONSdigital/dp-elasticsearch/v3/client"
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
 	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/feed"
+	"github.com/ONSdigital/dp-search-api/feedback"
 	"github.com/ONSdigital/dp-search-api/query"
 	scrubber "github.com/ONSdigital/dp-search-scrubber-api/sdk"
 	"github.com/gorilla/mux"
 )
 
 var (
+	read   = auth.Permissions{Read: true}
 	update = auth.Permissions{Update: true}
 )
 
 // SearchAPI provides an API around elasticseach
 type SearchAPI struct {
-	clList      *ClientList
-	Router      *mux.Router
-	permissions AuthHandler
+	clList           *ClientList
+	Router           *mux.Router
+	permissions      AuthHandler
+	experiments      query.Experiments
+	experimentHeader string
+	// synonymsUpdate is held while the synonyms of the search indexes are updated
+	synonymsUpdate *sync.Mutex
 }
 
 // ClientList is a struct obj of all the clients the service is dependent on
@@ -36,6 +45,12 @@ type ClientList struct {
 	ScrubberClient scrubber.Clienter
 	// Remove deprecatedESClient once the legacy handler is removed
 	DeprecatedESClient ElasticSearcher
+	// FeedbackSink stores search impressions and clicks, which are not recorded if it is nil
+	FeedbackSink feedback.Sink
+	// ResponseCache stores transformed search responses, which are not cached if it is nil
+	ResponseCache ResponseCache
+	// Metrics records the requests made to the API, which are not recorded if it is nil
+	Metrics *Metrics
 }
 
 // AuthHandler provides authorisation checks on requests
@@ -43,6 +58,13 @@ type AuthHandler interface {
 	Require(required auth.Permissions, handler http.HandlerFunc) http.HandlerFunc
 }
 
+// ResponseCache stores transformed search and release calendar responses, keyed by their normalised search request
+type ResponseCache interface {
+	Get(ctx context.Context, key string) (*CachedResponse, bool)
+	Set(ctx context.Context, key string, body []byte) *CachedResponse
+	Purge(ctx context.Context) error
+}
+
 // ElasticSearcher provides client methods for the elasticsearch package - now deprecated, due to be replaced
 // with the methods in dp-elasticsearch
 type ElasticSearcher interface {
@@ -53,11 +75,26 @@ type ElasticSearcher interface {
 // DpElasticSearcher provides an interface for the dp-elasticsearch functionality
 type DpElasticSearcher interface {
 	CreateIndex(ctx context.Context, indexName string, indexSettings []byte) error
+	GetIndices(ctx context.Context, indexPatterns []string) ([]byte, error)
+	GetAlias(ctx context.Context) ([]byte, error)
+	UpdateAliases(ctx context.Context, alias string, removeIndices, addIndices []string) error
+	DeleteIndex(ctx context.Context, indexName string) error
 	MultiSearch(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error)
 	Count(ctx context.Context, count client.Count) ([]byte, error)
+	OpenPointInTime(ctx context.Context, indices []string, keepAlive string) (string, error)
+	ClosePointInTime(ctx context.Context, id string) error
+	PointInTimeSearch(ctx context.Context, query []byte) ([]byte, error)
+	GetIndexSettings(ctx context.Context, index string) ([]byte, error)
+	UpdateAnalysisSettings(ctx context.Context, index string, settings []byte) error
 	Checker(ctx context.Context, state *health.CheckState) error
 }
 
+// CircuitBreaker is implemented by a DpElasticSearcher that fails fast while elasticsearch is failing
+type CircuitBreaker interface {
+	Tripped() (retryAfter time.Duration, tripped bool)
+	CircuitBreakerChecker(ctx context.Context, state *health.CheckState) error
+}
+
 // QueryParamValidator provides an interface to validate api query parameters (used for /search/releases)
 type QueryParamValidator interface {
 	Validate(ctx context.Context, name, value string) (interface{}, error)
@@ -74,6 +111,12 @@ type QueryBuilder interface {
 // ReleaseQueryBuilder provides an interface to build a search query for the Release content type
 type ReleaseQueryBuilder interface {
 	BuildSearchQuery(ctx context.Context, request interface{}) ([]client.Search, error)
+	BuildCalendarQuery(ctx context.Context, req query.ReleaseCalendarRequest) ([]client.Search, error)
+}
+
+// SuggestQueryBuilder provides an interface to build a title completion query
+type SuggestQueryBuilder interface {
+	BuildSearchQuery(ctx context.Context, req query.SuggestRequest) ([]client.Search, error)
 }
 
 // ResponseTransformer provides methods for the transform package
@@ -84,6 +127,14 @@ type ResponseTransformer interface {
 
 type ReleaseResponseTransformer interface {
 	TransformSearchResponse(ctx context.Context, responseData []byte, req query.ReleaseSearchRequest, highlight bool) ([]byte, error)
+	TransformCalendarResponse(ctx context.Context, responseData []byte, websiteURL string) ([]byte, error)
+	TransformFeedEntries(ctx context.Context, responseData []byte, websiteURL string) ([]feed.Entry, error)
+	TransformCalendarCountsResponse(ctx context.Context, responseData []byte, req query.ReleaseCalendarRequest) ([]byte, error)
+}
+
+// SuggestResponseTransformer provides an interface to transform a title completion response
+type SuggestResponseTransformer interface {
+	TransformSuggestResponse(ctx context.Context, responseData []byte) ([]byte, error)
 }
 
 // NewClientList returns a new ClientList obj with all available clients
@@ -100,9 +151,10 @@ func NewClientList(brl berlin.Clienter, cat category.Clienter, dpEsClient DpElas
 // NewSearchAPI returns a new Search API struct after registering the routes
 func NewSearchAPI(router *mux.Router, clientList *ClientList, permissions AuthHandler) *SearchAPI {
 	return &SearchAPI{
-		Router:      router,
-		clList:      clientList,
-		permissions: permissions,
+		Router:         router,
+		clList:         clientList,
+		permissions:    permissions,
+		synonymsUpdate: &sync.Mutex{},
 	}
 }
 
@@ -112,13 +164,13 @@ func NewSearchAPI(router *mux.Router, clientList *ClientList, permissions AuthHa
 func (a *SearchAPI) RegisterGetSearch(validator QueryParamValidator, builder QueryBuilder, settingsNLP *config.Config, transformer ResponseTransformer) *SearchAPI {
 	a.Router.HandleFunc(
 		"/search",
-		SearchHandlerFunc(
+		a.assignVariant(SearchHandlerFunc(
 			validator,
 			builder,
 			settingsNLP,
 			a.clList,
 			transformer,
-		),
+		)),
 	).Methods(http.MethodGet)
 	return a
 }
@@ -136,6 +188,79 @@ func (a *SearchAPI) RegisterPostSearch() *SearchAPI {
 	return a
 }
 
+// RegisterPostSearchFeedback registers the handler for POST /search/feedback endpoint,
+// used to record clicks on search results
+func (a *SearchAPI) RegisterPostSearchFeedback() *SearchAPI {
+	a.Router.HandleFunc(
+		"/search/feedback",
+		a.CreateSearchFeedbackHandlerFunc,
+	).Methods(http.MethodPost)
+	return a
+}
+
+// RegisterSearchIndexes registers the handlers for the /search/indexes endpoints,
+// used by reindex jobs to list, alias and delete search indexes,
+// enforcing required update permissions
+func (a *SearchAPI) RegisterSearchIndexes() *SearchAPI {
+	a.Router.HandleFunc(
+		"/search/indexes",
+		a.permissions.Require(
+			update,
+			a.GetSearchIndexesHandlerFunc,
+		),
+	).Methods(http.MethodGet)
+	a.Router.HandleFunc(
+		"/search/indexes/{name}/alias",
+		a.permissions.Require(
+			update,
+			a.UpdateSearchIndexAliasHandlerFunc,
+		),
+	).Methods(http.MethodPut)
+	a.Router.HandleFunc(
+		"/search/indexes/{name}",
+		a.permissions.Require(
+			update,
+			a.DeleteSearchIndexHandlerFunc,
+		),
+	).Methods(http.MethodDelete)
+	return a
+}
+
+// RegisterSearchSynonyms registers the handlers for the /search/synonyms endpoints,
+// used to read and replace the synonyms applied to search queries,
+// enforcing required read and update permissions
+func (a *SearchAPI) RegisterSearchSynonyms() *SearchAPI {
+	a.Router.HandleFunc(
+		"/search/synonyms",
+		a.permissions.Require(
+			read,
+			a.GetSearchSynonymsHandlerFunc,
+		),
+	).Methods(http.MethodGet)
+	a.Router.HandleFunc(
+		"/search/synonyms",
+		a.permissions.Require(
+			update,
+			a.UpdateSearchSynonymsHandlerFunc,
+		),
+	).Methods(http.MethodPut)
+	return a
+}
+
+// RegisterDeleteSearchCache registers the handler for DELETE /search/cache endpoint,
+// used by the publishing pipeline to purge cached responses,
+// enforcing required update permissions
+func (a *SearchAPI) RegisterDeleteSearchCache() *SearchAPI {
+	a.Router.HandleFunc(
+		"/search/cache",
+		a.permissions.Require(
+			update,
+			a.PurgeCacheHandlerFunc,
+		),
+	).Methods(http.MethodDelete)
+	return a
+}
+
 // RegisterPostSearchURIs registers the handler for POST /search/uris endpoint
 // enforcing required update permissions
 func (a *SearchAPI) RegisterPostSearchURIs(validator QueryParamValidator, builder QueryBuilder, cfg *config.Config, transformer ResponseTransformer) *SearchAPI {
@@ -153,11 +278,74 @@ func (a *SearchAPI) RegisterPostSearchURIs(validator QueryParamValidator, builde
 }
 
 // RegisterGetSearchRelease registers the handler for GET /search/releases endpoint
-// with the provided validator, query builder, searcher and validator
-func (a *SearchAPI) RegisterGetSearchReleases(validator QueryParamValidator, builder ReleaseQueryBuilder, transformer ReleaseResponseTransformer) *SearchAPI {
+// with the provided validator, query builder and response transformer
+func (a *SearchAPI) RegisterGetSearchReleases(validator QueryParamValidator, builder ReleaseQueryBuilder, cfg *config.Config, transformer ReleaseResponseTransformer) *SearchAPI {
 	a.Router.HandleFunc(
 		"/search/releases",
 		SearchReleasesHandlerFunc(
+			validator,
+			builder,
+			cfg,
+			a.clList,
+			transformer,
+		),
+	).Methods(http.MethodGet)
+	return a
+}
+
+// RegisterGetSearchFeed registers the handler for GET /search/feed endpoint,
+// which returns the newest search results as an Atom or RSS feed
+func (a *SearchAPI) RegisterGetSearchFeed(validator QueryParamValidator, builder QueryBuilder, cfg *config.Config, transformer ResponseTransformer) *SearchAPI {
+	a.Router.HandleFunc(
+		"/search/feed",
+		SearchFeedHandlerFunc(
+			validator,
+			builder,
+			cfg,
+			a.clList,
+			transformer,
+		),
+	).Methods(http.MethodGet)
+	return a
+}
+
+// RegisterGetSearchReleasesFeed registers the handler for GET /search/releases/feed endpoint,
+// which returns release calendar search results as an Atom or RSS feed
+func (a *SearchAPI) RegisterGetSearchReleasesFeed(validator QueryParamValidator, builder ReleaseQueryBuilder, cfg *config.Config, transformer ReleaseResponseTransformer) *SearchAPI {
+	a.Router.HandleFunc(
+		"/search/releases/feed",
+		SearchReleasesFeedHandlerFunc(
+			validator,
+			builder,
+			cfg,
+			a.clList,
+			transformer,
+		),
+	).Methods(http.MethodGet)
+	return a
+}
+
+// RegisterGetSearchReleasesCalendar registers the handler for GET /search/releases/calendar endpoint,
+// which returns the number of releases of each type and subtype released on each day or week of a calendar
+func (a *SearchAPI) RegisterGetSearchReleasesCalendar(validator QueryParamValidator, builder ReleaseQueryBuilder, transformer ReleaseResponseTransformer) *SearchAPI {
+	a.Router.HandleFunc(
+		"/search/releases/calendar",
+		SearchReleasesCalendarHandlerFunc(
+			validator,
+			builder,
+			a.clList,
+			transformer,
+		),
+	).Methods(http.MethodGet)
+	return a
+}
+
+// RegisterGetSearchSuggest registers the handler for GET /search/suggest endpoint
+// with the provided validator, query builder and transformer
+func (a *SearchAPI) RegisterGetSearchSuggest(validator QueryParamValidator, builder SuggestQueryBuilder, transformer SuggestResponseTransformer) *SearchAPI {
+	a.Router.HandleFunc(
+		"/search/suggest",
+		SearchSuggestHandlerFunc(
 			validator,
 			builder,
 			a.clList.DpESClient,
@@ -166,3 +354,19 @@ func (a *SearchAPI) RegisterGetSearchReleases(validator QueryParamValidator, bui
 	).Methods(http.MethodGet)
 	return a
 }
+
+// RegisterGetSearchExport registers the handler for GET /search/export endpoint
+// with the provided validator, query builder and response transformer
+func (a *SearchAPI) RegisterGetSearchExport(validator QueryParamValidator, builder QueryBuilder, cfg *config.Config, transformer ResponseTransformer) *SearchAPI {
+	a.Router.HandleFunc(
+		"/search/export",
+		SearchExportHandlerFunc(
+			validator,
+			builder,
+			cfg,
+			a.clList,
+			transformer,
+		),
+	).Methods(http.MethodGet)
+	return a
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/dp-search-api/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// autoCorrect re-runs a search that found no results using the top spelling suggestion for its term,
+// provided the suggestion scores at least the configured minimum. The corrected term is returned along with
+// the new search and count responses, or an empty term if the search was not re-run
+func autoCorrect(ctx context.Context, cfg *config.Config, clList *ClientList, queryBuilder QueryBuilder, searchReq *query.SearchRequest,
+	countReq *query.CountRequest, responseSearchData []byte) (correctedSearchData, correctedCountData []byte, correction string) {
+	correction = topSuggestion(responseSearchData, cfg.AutoCorrectMinScore)
+	if correction == "" {
+		return nil, nil, ""
+	}
+
+	correctedSearchReq := *searchReq
+	correctedSearchReq.Term = sanitiseDoubleQuotes(correction)
+	correctedCountReq := *countReq
+	correctedCountReq.Term = correctedSearchReq.Term
+
+	log.Info(ctx, "re-running search with no results using corrected query", log.Data{"original_query": searchReq.Term, "corrected_query": correction})
+
+	correctedSearchData, correctedCountData, _ = runSearch(ctx, cfg, clList, queryBuilder, &correctedSearchReq, &correctedCountReq)
+	if correctedSearchData == nil || correctedCountData == nil {
+		log.Warn(ctx, "search with corrected query failed, returning the original results", log.Data{"corrected_query": correction})
+		return nil, nil, ""
+	}
+
+	return correctedSearchData, correctedCountData, correction
+}
+
+// topSuggestion returns the highest scoring phrase suggestion for the term of a content search that found no results,
+// or an empty string if the search found results or no suggestion scores at least minScore
+func topSuggestion(responseSearchData []byte, minScore float64) string {
+	if responseSearchData == nil {
+		return ""
+	}
+
+	var esResponse models.EsResponses
+	if err := json.Unmarshal(responseSearchData, &esResponse); err != nil || len(esResponse.Responses) == 0 || esResponse.Responses[0] == nil {
+		return ""
+	}
+
+	contentResponse := esResponse.Responses[0]
+	if contentResponse.Hits.Total > 0 || len(contentResponse.Hits.Hits) > 0 {
+		return ""
+	}
+
+	var top models.Option
+	for _, suggestion := range contentResponse.Suggest.SearchSuggest {
+		for _, option := range suggestion.Options {
+			if option.Score > top.Score {
+				top = option
+			}
+		}
+	}
+
+	if top.Text == "" || top.Score < minScore {
+		return ""
+	}
+	return top.Text
+}
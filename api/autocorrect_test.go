@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/dp-search-api/query"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testNoHitsResponse = `{"responses":[{"took":1,"hits":{"total":0,"hits":[]},"suggest":{"search_suggest":[{"text":"inflaton","options":[
+		{"text":"inflation","score":0.02},{"text":"inflatable","score":0.005}]}]}}]}`
+	testHitsResponse = `{"responses":[{"took":1,"hits":{"total":1,"hits":[{"_source":{"uri":"/a"}}]}}]}`
+)
+
+func TestTopSuggestion(t *testing.T) {
+	c.Convey("Given a search that found no results, with suggestions", t, func() {
+		c.Convey("Then the highest scoring suggestion is returned", func() {
+			c.So(topSuggestion([]byte(testNoHitsResponse), 0.001), c.ShouldEqual, "inflation")
+		})
+
+		c.Convey("Then no suggestion is returned if none scores highly enough", func() {
+			c.So(topSuggestion([]byte(testNoHitsResponse), 0.1), c.ShouldBeEmpty)
+		})
+	})
+
+	c.Convey("Given a search that found results", t, func() {
+		c.Convey("Then no suggestion is returned", func() {
+			c.So(topSuggestion([]byte(testHitsResponse), 0.001), c.ShouldBeEmpty)
+		})
+	})
+
+	c.Convey("Given a failed search", t, func() {
+		c.Convey("Then no suggestion is returned", func() {
+			c.So(topSuggestion(nil, 0.001), c.ShouldBeEmpty)
+		})
+	})
+}
+
+func TestSearchHandlerFuncAutoCorrect(t *testing.T) {
+	validator := query.NewSearchQueryParamValidator()
+	cfg := &config.Config{
+		AutoCorrectMinScore: 0.001,
+		DefaultSort:         "relevance",
+	}
+
+	c.Convey("Given a search term that is misspelt", t, func() {
+		qbMock := newQueryBuilderMock([]byte(`[]`), nil)
+		esMock := newDpElasticSearcherMock([]byte(`{"count":1}`), nil)
+		esMock.MultiSearchFunc = func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
+			if len(esMock.MultiSearchCalls()) == 1 {
+				return []byte(testNoHitsResponse), nil
+			}
+			return []byte(testHitsResponse), nil
+		}
+		trMock := newResponseTransformerMock([]byte(`{"count":1,"items":[{"uri":"/a"}]}`), nil)
+
+		searchHandler := SearchHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		c.Convey("When the search is run with auto correct", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search?q=inflaton&auto_correct=true", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then the search is re-run with the top suggestion and the corrected results are returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 2)
+				c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 2)
+				c.So(qbMock.BuildSearchQueryCalls()[1].Req.Term, c.ShouldEqual, "inflation")
+				c.So(qbMock.BuildCountQueryCalls()[1].Req.Term, c.ShouldEqual, "inflation")
+				c.So(string(trMock.TransformSearchResponseCalls()[0].ResponseData), c.ShouldEqual, testHitsResponse)
+
+				var searchResp models.SearchResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &searchResp), c.ShouldBeNil)
+				c.So(searchResp.CorrectedQuery, c.ShouldEqual, "inflation")
+				c.So(searchResp.OriginalQuery, c.ShouldEqual, "inflaton")
+			})
+		})
+
+		c.Convey("When the search is run without auto correct", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search?q=inflaton", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then the search is not re-run", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 1)
+
+				var searchResp models.SearchResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &searchResp), c.ShouldBeNil)
+				c.So(searchResp.CorrectedQuery, c.ShouldBeEmpty)
+				c.So(searchResp.OriginalQuery, c.ShouldBeEmpty)
+			})
+		})
+	})
+}
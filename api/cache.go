@@ -0,0 +1,266 @@
+package api
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// CachedResponse is a transformed search response held by a ResponseCache
+type CachedResponse struct {
+	Body    []byte
+	ETag    string
+	Expires time.Time
+}
+
+// MaxAge returns the number of whole seconds until the response expires
+func (cr *CachedResponse) MaxAge(now time.Time) int {
+	return int(math.Max(0, math.Ceil(cr.Expires.Sub(now).Seconds())))
+}
+
+// LRUCache is an in-process ResponseCache, holding up to a maximum number of responses for a fixed time.
+// The least recently used response is evicted when the cache is full.
+type LRUCache struct {
+	mutex   sync.Mutex
+	size    int
+	ttl     time.Duration
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+}
+
+type lruEntry struct {
+	key      string
+	response *CachedResponse
+}
+
+// NewLRUCache returns an empty LRUCache holding up to size responses for the ttl
+func NewLRUCache(size int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// Get returns the response cached with the key, if it has not expired
+func (lc *LRUCache) Get(ctx context.Context, key string) (*CachedResponse, bool) {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	element, ok := lc.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !lc.now().Before(entry.response.Expires) {
+		lc.order.Remove(element)
+		delete(lc.entries, key)
+		return nil, false
+	}
+
+	lc.order.MoveToFront(element)
+	return entry.response, true
+}
+
+// Set caches the response body with the key, evicting the least recently used response if the cache is full
+func (lc *LRUCache) Set(ctx context.Context, key string, body []byte) *CachedResponse {
+	response := &CachedResponse{
+		Body:    body,
+		ETag:    newETag(body),
+		Expires: lc.now().Add(lc.ttl),
+	}
+
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	if element, ok := lc.entries[key]; ok {
+		element.Value.(*lruEntry).response = response
+		lc.order.MoveToFront(element)
+		return response
+	}
+
+	lc.entries[key] = lc.order.PushFront(&lruEntry{key: key, response: response})
+	for lc.order.Len() > lc.size {
+		oldest := lc.order.Back()
+		lc.order.Remove(oldest)
+		delete(lc.entries, oldest.Value.(*lruEntry).key)
+	}
+
+	return response
+}
+
+// Purge removes every cached response
+func (lc *LRUCache) Purge(ctx context.Context) error {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	lc.entries = make(map[string]*list.Element, lc.size)
+	lc.order.Init()
+	return nil
+}
+
+// Len returns the number of cached responses, including any that have expired but not yet been removed
+func (lc *LRUCache) Len() int {
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	return lc.order.Len()
+}
+
+// newETag returns a strong entity tag for the response body
+func newETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return strconv.Quote(hex.EncodeToString(sum[:16]))
+}
+
+// searchCacheKey returns the cache key of a search request. The time the request was made is left out, as the
+// search templates do not use it, and the variant is included as it is returned with the results.
+func searchCacheKey(searchReq *query.SearchRequest, autoCorrect bool, variant *query.Variant) (string, error) {
+	normalised := *searchReq
+	normalised.Now = ""
+
+	variantName := ""
+	if variant != nil {
+		variantName = variant.Name
+	}
+
+	return cacheKey("search", struct {
+		Request     query.SearchRequest
+		AutoCorrect bool
+		Variant     string
+	}{normalised, autoCorrect, variantName})
+}
+
+// releaseCacheKey returns the cache key of a release calendar search request, including today's date
+// so that upcoming releases are not returned from the cache once they have been released
+func releaseCacheKey(searchReq *query.ReleaseSearchRequest) (string, error) {
+	return cacheKey("releases", struct {
+		Request query.ReleaseSearchRequest
+		Now     string
+	}{*searchReq, searchReq.Now()})
+}
+
+// releaseCalendarCacheKey returns the cache key of a release calendar counts request, including today's date as the
+// releases counted as upcoming change with it
+func releaseCalendarCacheKey(calendarReq *query.ReleaseCalendarRequest) (string, error) {
+	return cacheKey("release-calendar", struct {
+		Request query.ReleaseCalendarRequest
+		Now     string
+	}{*calendarReq, calendarReq.Now()})
+}
+
+func cacheKey(prefix string, request interface{}) (string, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s cache key: %w", prefix, err)
+	}
+	sum := sha256.Sum256(data)
+	return prefix + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// getCachedResponse returns the response cached with the key, if caching is enabled
+func getCachedResponse(ctx context.Context, cache ResponseCache, key string) (*CachedResponse, bool) {
+	if cache == nil || key == "" {
+		return nil, false
+	}
+	return cache.Get(ctx, key)
+}
+
+// setCachedResponse caches the response body with the key, if caching is enabled
+func setCachedResponse(ctx context.Context, cache ResponseCache, key string, body []byte) *CachedResponse {
+	if cache == nil || key == "" {
+		return nil
+	}
+	return cache.Set(ctx, key, body)
+}
+
+// purgeCache removes every cached response, e.g. after content has been published or the search index changed.
+// Failures are logged rather than returned, as cached responses expire regardless.
+func purgeCache(ctx context.Context, cache ResponseCache, reason string) {
+	if cache == nil {
+		return
+	}
+	if err := cache.Purge(ctx); err != nil {
+		log.Error(ctx, "purging response cache failed", err, log.Data{"reason": reason})
+		return
+	}
+	log.Info(ctx, "response cache purged", log.Data{"reason": reason})
+}
+
+// responseETag returns the entity tag of a response body, which is already known if the response has been cached
+func responseETag(body []byte, cached *CachedResponse) string {
+	if cached != nil {
+		return cached.ETag
+	}
+	return newETag(body)
+}
+
+// weakETag returns the weak form of an entity tag, for responses that are only semantically equivalent, e.g. search
+// responses that each hold the ID of their own request
+func weakETag(etag string) string {
+	return "W/" + etag
+}
+
+// writeNotModified sets the entity tag of a response, along with its freshness if it has been cached, and writes
+// 304 Not Modified if the request's If-None-Match header matches the entity tag, returning whether it has done so
+func writeNotModified(w http.ResponseWriter, req *http.Request, etag string, cached *CachedResponse) bool {
+	w.Header().Set("ETag", etag)
+	if cached != nil {
+		w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", cached.MaxAge(time.Now())))
+	}
+
+	if !etagMatches(req.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches returns whether any of the entity tags in an If-None-Match header match the entity tag.
+// If-None-Match uses the weak comparison, so weak and strong tags of the same value match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || (tag != "" && strings.TrimPrefix(tag, "W/") == etag) {
+			return true
+		}
+	}
+	return false
+}
+
+// PurgeCacheHandlerFunc removes every cached search response, e.g. once the publishing pipeline has published content
+func (a SearchAPI) PurgeCacheHandlerFunc(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	if a.clList.ResponseCache == nil {
+		writeError(w, http.StatusNotImplemented, apierrors.CodeNotImplemented, "response caching is not enabled")
+		return
+	}
+
+	if err := a.clList.ResponseCache.Purge(ctx); err != nil {
+		log.Error(ctx, "purging response cache failed", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+	log.Info(ctx, "response cache purged", log.Data{"reason": "purge requested"})
+
+	w.WriteHeader(http.StatusNoContent)
+}
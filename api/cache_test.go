@@ -0,0 +1,300 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/query"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+
+	c.Convey("Given an LRU cache holding two responses for a minute", t, func() {
+		now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+		cache := NewLRUCache(2, time.Minute)
+		cache.now = func() time.Time { return now }
+
+		cached := cache.Set(ctx, "a", []byte(`{"count":1}`))
+
+		c.Convey("When a response is cached", func() {
+			c.Convey("Then it is returned with an entity tag and expiry", func() {
+				response, ok := cache.Get(ctx, "a")
+				c.So(ok, c.ShouldBeTrue)
+				c.So(response, c.ShouldEqual, cached)
+				c.So(string(response.Body), c.ShouldEqual, `{"count":1}`)
+				c.So(response.ETag, c.ShouldEqual, newETag([]byte(`{"count":1}`)))
+				c.So(response.MaxAge(now.Add(20*time.Second)), c.ShouldEqual, 40)
+			})
+		})
+
+		c.Convey("When the response has expired", func() {
+			now = now.Add(time.Minute)
+
+			c.Convey("Then it is not returned and is removed", func() {
+				_, ok := cache.Get(ctx, "a")
+				c.So(ok, c.ShouldBeFalse)
+				c.So(cache.Len(), c.ShouldEqual, 0)
+			})
+		})
+
+		c.Convey("When more responses are cached than it can hold", func() {
+			cache.Set(ctx, "b", []byte(`{"count":2}`))
+			_, _ = cache.Get(ctx, "a")
+			cache.Set(ctx, "c", []byte(`{"count":3}`))
+
+			c.Convey("Then the least recently used response is evicted", func() {
+				c.So(cache.Len(), c.ShouldEqual, 2)
+				_, ok := cache.Get(ctx, "b")
+				c.So(ok, c.ShouldBeFalse)
+				_, ok = cache.Get(ctx, "a")
+				c.So(ok, c.ShouldBeTrue)
+				_, ok = cache.Get(ctx, "c")
+				c.So(ok, c.ShouldBeTrue)
+			})
+		})
+
+		c.Convey("When it is purged", func() {
+			c.So(cache.Purge(ctx), c.ShouldBeNil)
+
+			c.Convey("Then no responses are returned", func() {
+				_, ok := cache.Get(ctx, "a")
+				c.So(ok, c.ShouldBeFalse)
+				c.So(cache.Len(), c.ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestCacheKeys(t *testing.T) {
+	c.Convey("Given two search requests made at different times", t, func() {
+		first := &query.SearchRequest{Term: "cpi", Size: 10, Now: "2024-03-01T09:30:00Z"}
+		second := &query.SearchRequest{Term: "cpi", Size: 10, Now: "2024-03-01T09:31:00Z"}
+
+		c.Convey("Then they have the same cache key", func() {
+			firstKey, err := searchCacheKey(first, false, nil)
+			c.So(err, c.ShouldBeNil)
+			secondKey, err := searchCacheKey(second, false, nil)
+			c.So(err, c.ShouldBeNil)
+			c.So(firstKey, c.ShouldEqual, secondKey)
+			c.So(first.Now, c.ShouldEqual, "2024-03-01T09:30:00Z")
+		})
+
+		c.Convey("And the key differs when the request is auto corrected or assigned to a variant", func() {
+			key, _ := searchCacheKey(first, false, nil)
+			autoCorrectKey, _ := searchCacheKey(first, true, nil)
+			variantKey, _ := searchCacheKey(first, false, &query.Variant{Name: "recent"})
+			c.So(autoCorrectKey, c.ShouldNotEqual, key)
+			c.So(variantKey, c.ShouldNotEqual, key)
+		})
+	})
+
+	c.Convey("Given a release calendar search request", t, func() {
+		releaseReq := &query.ReleaseSearchRequest{Term: "cpi", Type: query.Upcoming}
+
+		c.Convey("Then its cache key is distinct from a search cache key", func() {
+			key, err := releaseCacheKey(releaseReq)
+			c.So(err, c.ShouldBeNil)
+			c.So(key, c.ShouldStartWith, "releases:")
+		})
+	})
+}
+
+func TestSearchHandlerFuncCache(t *testing.T) {
+	cfg := &config.Config{DefaultSort: "relevance"}
+	validQueryDocBytes, _ := json.Marshal([]client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"query":{}}`)}})
+
+	c.Convey("Given a search API caching responses", t, func() {
+		qbMock := newQueryBuilderMock(validQueryDocBytes, nil)
+		esMock := newDpElasticSearcherMock([]byte(validESResponse), nil)
+		trMock := newResponseTransformerMock([]byte(validTransformedResponse), nil)
+		searchAPI := newIndexesSearchAPI(esMock).
+			RegisterGetSearch(query.NewSearchQueryParamValidator(), qbMock, cfg, trMock).
+			RegisterDeleteSearchCache()
+		searchAPI.clList.ResponseCache = NewLRUCache(10, time.Minute)
+
+		search := func(url string) *httptest.ResponseRecorder {
+			resp := httptest.NewRecorder()
+			searchAPI.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, url, http.NoBody))
+			return resp
+		}
+
+		c.Convey("When the same search is made twice", func() {
+			first := search("http://localhost:23900/search?q=census")
+			second := search("http://localhost:23900/search?q=census")
+
+			c.Convey("Then elasticsearch is only queried once", func() {
+				c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 1)
+				c.So(esMock.CountCalls(), c.ShouldHaveLength, 1)
+			})
+
+			c.Convey("And both responses are the same, with cache headers", func() {
+				c.So(second.Code, c.ShouldEqual, http.StatusOK)
+				c.So(second.Body.String(), c.ShouldEqual, first.Body.String())
+				c.So(second.Header().Get("ETag"), c.ShouldNotBeEmpty)
+				c.So(second.Header().Get("ETag"), c.ShouldEqual, first.Header().Get("ETag"))
+				c.So(second.Header().Get("Cache-Control"), c.ShouldEqual, "max-age=60")
+			})
+		})
+
+		c.Convey("When a search is repeated with the entity tag of its response", func() {
+			first := search("http://localhost:23900/search?q=census")
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=census", http.NoBody)
+			req.Header.Set("If-None-Match", first.Header().Get("ETag"))
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then not modified is returned without a body", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusNotModified)
+				c.So(resp.Body.Len(), c.ShouldEqual, 0)
+				c.So(resp.Header().Get("ETag"), c.ShouldEqual, first.Header().Get("ETag"))
+			})
+		})
+
+		c.Convey("When a raw search is made twice", func() {
+			search("http://localhost:23900/search?q=census&raw=true")
+			resp := search("http://localhost:23900/search?q=census&raw=true")
+
+			c.Convey("Then it is not cached", func() {
+				c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 2)
+				c.So(resp.Header().Get("ETag"), c.ShouldBeEmpty)
+			})
+		})
+
+		c.Convey("When the cache is purged between two searches", func() {
+			search("http://localhost:23900/search?q=census")
+			purgeResp := httptest.NewRecorder()
+			searchAPI.Router.ServeHTTP(purgeResp, httptest.NewRequest(http.MethodDelete, "http://localhost:23900/search/cache", http.NoBody))
+			search("http://localhost:23900/search?q=census")
+
+			c.Convey("Then the second search queries elasticsearch again", func() {
+				c.So(purgeResp.Code, c.ShouldEqual, http.StatusNoContent)
+				c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 2)
+			})
+		})
+	})
+
+	c.Convey("Given a search API that does not cache responses", t, func() {
+		searchAPI := newIndexesSearchAPI(newDpElasticSearcherMock(nil, nil)).RegisterDeleteSearchCache()
+
+		c.Convey("When the cache is purged", func() {
+			resp := httptest.NewRecorder()
+			searchAPI.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, "http://localhost:23900/search/cache", http.NoBody))
+
+			c.Convey("Then a not implemented error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusNotImplemented)
+			})
+		})
+	})
+}
+
+func TestSearchReleasesHandlerFuncCache(t *testing.T) {
+	c.Convey("Given a release calendar search handler caching responses", t, func() {
+		builder := &ReleaseQueryBuilderMock{
+			BuildSearchQueryFunc: func(ctx context.Context, request interface{}) ([]client.Search, error) {
+				return []client.Search{{Query: []byte(`{"query": "test"}`)}}, nil
+			},
+		}
+		searcher := &DpElasticSearcherMock{
+			MultiSearchFunc: func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
+				return []byte(`{"responses":[]}`), nil
+			},
+		}
+		transformer := &ReleaseResponseTransformerMock{
+			TransformSearchResponseFunc: func(ctx context.Context, responseData []byte, req query.ReleaseSearchRequest, highlight bool) ([]byte, error) {
+				return []byte(`{"took":1,"releases":[]}`), nil
+			},
+		}
+		searchHandler := SearchReleasesHandlerFunc(query.NewReleaseQueryParamValidator(), builder, &config.Config{}, &ClientList{DpESClient: searcher, ResponseCache: NewLRUCache(10, time.Minute)}, transformer)
+
+		c.Convey("When upcoming releases are requested twice", func() {
+			for i := 0; i < 2; i++ {
+				searchHandler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases?release-type=type-upcoming", http.NoBody))
+			}
+			resp := httptest.NewRecorder()
+			searchHandler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases?release-type=type-upcoming", http.NoBody))
+
+			c.Convey("Then the cached response is returned with its entity tag", func() {
+				c.So(searcher.MultiSearchCalls(), c.ShouldHaveLength, 1)
+				c.So(resp.Body.String(), c.ShouldEqual, `{"took":1,"releases":[]}`)
+				c.So(resp.Header().Get("ETag"), c.ShouldEqual, newETag([]byte(`{"took":1,"releases":[]}`)))
+			})
+		})
+	})
+}
+
+func TestSearchReleasesHandlerFuncNotModified(t *testing.T) {
+	c.Convey("Given a release calendar search handler that does not cache responses", t, func() {
+		builder := &ReleaseQueryBuilderMock{
+			BuildSearchQueryFunc: func(ctx context.Context, request interface{}) ([]client.Search, error) {
+				return []client.Search{{Query: []byte(`{"query": "test"}`)}}, nil
+			},
+		}
+		searcher := &DpElasticSearcherMock{
+			MultiSearchFunc: func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
+				return []byte(`{"responses":[]}`), nil
+			},
+		}
+		transformer := &ReleaseResponseTransformerMock{
+			TransformSearchResponseFunc: func(ctx context.Context, responseData []byte, req query.ReleaseSearchRequest, highlight bool) ([]byte, error) {
+				return []byte(`{"took":1,"releases":[]}`), nil
+			},
+		}
+		searchHandler := SearchReleasesHandlerFunc(query.NewReleaseQueryParamValidator(), builder, &config.Config{}, &ClientList{DpESClient: searcher}, transformer)
+		etag := newETag([]byte(`{"took":1,"releases":[]}`))
+
+		c.Convey("When releases are requested", func() {
+			resp := httptest.NewRecorder()
+			searchHandler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases", http.NoBody))
+
+			c.Convey("Then the response is returned with a strong entity tag and no freshness", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("ETag"), c.ShouldEqual, etag)
+				c.So(resp.Header().Get("Cache-Control"), c.ShouldBeEmpty)
+			})
+		})
+
+		c.Convey("When releases are requested with a matching If-None-Match header", func() {
+			for _, ifNoneMatch := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases", http.NoBody)
+				req.Header.Set("If-None-Match", ifNoneMatch)
+				resp := httptest.NewRecorder()
+
+				searchHandler.ServeHTTP(resp, req)
+
+				c.So(resp.Code, c.ShouldEqual, http.StatusNotModified)
+				c.So(resp.Body.Len(), c.ShouldEqual, 0)
+			}
+		})
+
+		c.Convey("When releases are requested with a different entity tag", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases", http.NoBody)
+			req.Header.Set("If-None-Match", `"other"`)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then the response is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Body.String(), c.ShouldEqual, `{"took":1,"releases":[]}`)
+			})
+		})
+
+		c.Convey("When raw releases are requested", func() {
+			resp := httptest.NewRecorder()
+			searchHandler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases?raw=true", http.NoBody))
+
+			c.Convey("Then no entity tag is returned", func() {
+				c.So(resp.Header().Get("ETag"), c.ShouldBeEmpty)
+			})
+		})
+	})
+}
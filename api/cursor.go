@@ -0,0 +1,279 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// startCursor is the cursor value that requests the first page of a cursor-paged search
+const startCursor = "*"
+
+// pointInTimeID matches the base64 IDs that elasticsearch generates for a point in time
+var pointInTimeID = regexp.MustCompile(`^[A-Za-z0-9+/=_-]+$`)
+
+// searchCursor is the content of the opaque cursor handed to clients: the point in time the search runs against,
+// the sort values of the last hit that has been returned, and the sort order and fingerprint of the search they
+// belong to, as sort values cannot be used to page through a search with a different sort order or query
+type searchCursor struct {
+	PointInTime string          `json:"pit"`
+	SearchAfter json.RawMessage `json:"after"`
+	Sort        string          `json:"sort"`
+	Query       string          `json:"query"`
+}
+
+// pointInTimeResponse is the subset of a point in time search response needed to build the next cursor
+type pointInTimeResponse struct {
+	Responses []struct {
+		PointInTimeID string `json:"pit_id"`
+		Hits          struct {
+			Hits []struct {
+				Sort json.RawMessage `json:"sort"`
+			} `json:"hits"`
+		} `json:"hits"`
+	} `json:"responses"`
+}
+
+// parseCursor returns the point in time described by the cursor parameter, or nil if the search is not cursor-paged
+func parseCursor(ctx context.Context, params url.Values, cfg *config.Config) (*query.PointInTime, error) {
+	cursorParam := params.Get(ParamCursor)
+	if cursorParam == "" {
+		return nil, nil
+	}
+
+	pit := &query.PointInTime{
+		KeepAlive: fmt.Sprintf("%ds", int(cfg.CursorKeepAlive.Seconds())),
+	}
+	if cursorParam == startCursor {
+		return pit, nil
+	}
+
+	cursor, err := decodeCursor(cursorParam)
+	if err != nil {
+		log.Warn(ctx, err.Error(), log.Data{"param": ParamCursor, "value": cursorParam})
+		return nil, errors.New("invalid cursor parameter")
+	}
+
+	pit.ID = cursor.PointInTime
+	pit.SearchAfter = string(cursor.SearchAfter)
+	return pit, nil
+}
+
+// checkCursor returns an error if the cursor parameter was returned by a search with a different sort order or query
+// than the search it is used with. The cursor has already been validated by parseCursor.
+func checkCursor(ctx context.Context, params url.Values, searchReq *query.SearchRequest) error {
+	cursorParam := params.Get(ParamCursor)
+	if cursorParam == "" || cursorParam == startCursor {
+		return nil
+	}
+
+	cursor, err := decodeCursor(cursorParam)
+	if err != nil {
+		return errors.New("invalid cursor parameter")
+	}
+
+	if cursor.Sort != searchReq.SortBy {
+		log.Warn(ctx, "cursor used with a different sort", log.Data{"param": ParamCursor, "cursor_sort": cursor.Sort, "sort": searchReq.SortBy})
+		return errors.New("cursor was returned for a different sort, start again with cursor=*")
+	}
+
+	fingerprint, err := cursorQuery(searchReq)
+	if err != nil {
+		return err
+	}
+	if cursor.Query != fingerprint {
+		log.Warn(ctx, "cursor used with a different search", log.Data{"param": ParamCursor})
+		return errors.New("cursor was returned for a different search, start again with cursor=*")
+	}
+
+	return nil
+}
+
+// cursorQuery returns the fingerprint of the search that a cursor pages through, leaving out the page size
+// and position, and the time that the search is run at
+func cursorQuery(searchReq *query.SearchRequest) (string, error) {
+	normalised := *searchReq
+	normalised.From = 0
+	normalised.Size = 0
+	normalised.Now = ""
+	normalised.PointInTime = nil
+
+	return cacheKey("cursor", normalised)
+}
+
+// cursorExpired returns whether a search failed because the point in time of its cursor has expired or never
+// existed, which elasticsearch reports as 404 Not Found. The first page opens its own point in time, so cannot expire.
+func cursorExpired(params url.Values, searchErr error) bool {
+	cursorParam := params.Get(ParamCursor)
+	return cursorParam != "" && cursorParam != startCursor && esError.ErrorStatus(searchErr) == http.StatusNotFound
+}
+
+// decodeCursor decodes and validates a cursor. The search_after values are re-encoded,
+// as they are written into the elasticsearch query as they are
+func decodeCursor(cursorParam string) (*searchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursorParam)
+	if err != nil {
+		return nil, fmt.Errorf("cursor is not valid base64: %w", err)
+	}
+
+	var cursor searchCursor
+	if err = json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("cursor is not valid json: %w", err)
+	}
+
+	if !pointInTimeID.MatchString(cursor.PointInTime) {
+		return nil, errors.New("cursor contains an invalid point in time")
+	}
+
+	var searchAfter []interface{}
+	decoder := json.NewDecoder(bytes.NewReader(cursor.SearchAfter))
+	decoder.UseNumber()
+	if err = decoder.Decode(&searchAfter); err != nil || len(searchAfter) == 0 {
+		return nil, errors.New("cursor contains invalid sort values")
+	}
+	for _, value := range searchAfter {
+		switch value.(type) {
+		case json.Number, string, nil:
+		default:
+			return nil, errors.New("cursor contains invalid sort values")
+		}
+	}
+
+	if cursor.SearchAfter, err = json.Marshal(searchAfter); err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+func encodeCursor(cursor searchCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// nextCursor returns the cursor for the page following the provided point in time search response,
+// or an empty cursor if it was the last page, along with the ID of the point in time to use from now on
+func nextCursor(responseData []byte, searchReq *query.SearchRequest) (cursor, pitID string, err error) {
+	searchAfter, pitID, err := nextSearchAfter(responseData, searchReq.Size)
+	if err != nil || searchAfter == nil {
+		return "", pitID, err
+	}
+
+	fingerprint, err := cursorQuery(searchReq)
+	if err != nil {
+		return "", pitID, err
+	}
+
+	cursor, err = encodeCursor(searchCursor{
+		PointInTime: pitID,
+		SearchAfter: searchAfter,
+		Sort:        searchReq.SortBy,
+		Query:       fingerprint,
+	})
+	return cursor, pitID, err
+}
+
+// nextSearchAfter returns the sort values to search after for the page following the provided point in time search
+// response, or nil if it was the last page, along with the ID of the point in time to use from now on
+func nextSearchAfter(responseData []byte, size int) (searchAfter json.RawMessage, pitID string, err error) {
+	var response pointInTimeResponse
+	if err = json.Unmarshal(responseData, &response); err != nil {
+		return nil, "", fmt.Errorf("failed to decode point in time search response: %w", err)
+	}
+
+	if len(response.Responses) != 1 {
+		return nil, "", errors.New("unexpected number of point in time search responses")
+	}
+
+	pitID = response.Responses[0].PointInTimeID
+	hits := response.Responses[0].Hits.Hits
+	if len(hits) == 0 || len(hits) < size {
+		return nil, pitID, nil
+	}
+
+	return hits[len(hits)-1].Sort, pitID, nil
+}
+
+// pageCursor returns the cursor for the page following the provided point in time search response. The point in time
+// is closed once the last page has been returned, rather than left for elasticsearch to expire
+func pageCursor(ctx context.Context, elasticSearchClient DpElasticSearcher, responseData []byte, searchReq *query.SearchRequest) (string, error) {
+	cursor, pitID, err := nextCursor(responseData, searchReq)
+	if err != nil {
+		return "", err
+	}
+
+	if cursor == "" && pitID != "" {
+		if closeErr := elasticSearchClient.ClosePointInTime(ctx, pitID); closeErr != nil {
+			log.Warn(ctx, "closing point in time failed", log.Data{"error": closeErr.Error()})
+		}
+	}
+
+	return cursor, nil
+}
+
+// processPointInTimeSearchQuery runs the content query for a cursor-paged search against its point in time,
+// sending the response, or nil if it failed, to responseDataChan. The error is set before nil is sent, so that
+// an expired cursor can be told apart from a failed search.
+func processPointInTimeSearchQuery(ctx context.Context, cfg *config.Config, elasticSearchClient DpElasticSearcher, queryBuilder QueryBuilder, reqParams *query.SearchRequest, responseDataChan chan []byte, searchErr *error) {
+	responseData, err := pointInTimeSearch(ctx, cfg, elasticSearchClient, queryBuilder, reqParams)
+	if err != nil {
+		log.Error(ctx, "point in time search failed", err, log.Data{ParamQ: reqParams.Term})
+		*searchErr = err
+		responseDataChan <- nil
+		return
+	}
+
+	responseDataChan <- responseData
+}
+
+// pointInTimeSearch runs the content query of a search against its point in time, opening a new point in time
+// for the first page. The single search response is wrapped so it has the same shape as a multi search response
+func pointInTimeSearch(ctx context.Context, cfg *config.Config, elasticSearchClient DpElasticSearcher, queryBuilder QueryBuilder, reqParams *query.SearchRequest) ([]byte, error) {
+	if reqParams.PointInTime.ID == "" {
+		pitID, err := elasticSearchClient.OpenPointInTime(ctx, []string{searchAlias}, reqParams.PointInTime.KeepAlive)
+		if err != nil {
+			return nil, fmt.Errorf("opening point in time failed: %w", err)
+		}
+		reqParams.PointInTime.ID = pitID
+	}
+
+	formattedQuery, err := queryBuilder.BuildSearchQuery(ctx, reqParams, true)
+	if err != nil {
+		return nil, fmt.Errorf("creation of point in time search query failed: %w", err)
+	}
+
+	var searches []client.Search
+	if err = json.Unmarshal(formattedQuery, &searches); err != nil || len(searches) == 0 {
+		return nil, errors.New("creation of point in time search query failed: no content query built")
+	}
+
+	if cfg.DebugMode {
+		log.Info(ctx, "[DEBUG] Point in time search sent to elasticsearch", log.Data{"query": string(searches[0].Query)})
+	}
+
+	responseData, err := elasticSearchClient.PointInTimeSearch(ctx, searches[0].Query)
+	if err != nil {
+		return nil, fmt.Errorf("elasticsearch point in time query failed: %w", err)
+	}
+
+	if !json.Valid(responseData) {
+		return nil, errors.New("elasticsearch returned invalid JSON for point in time search query")
+	}
+
+	return bytes.Join([][]byte{[]byte(`{"responses":[`), responseData, []byte(`]}`)}, nil), nil
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/dp-search-api/query"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+const testPointInTimeResponse = `{"pit_id":"next-pit-id","took":3,"hits":{"total":3,"hits":[
+	{"_source":{"uri":"/a"},"sort":[1678450504000,"/a"]},
+	{"_source":{"uri":"/b"},"sort":[1678450503000,"/b"]}
+]}}`
+
+func TestParseCursor(t *testing.T) {
+	cfg := &config.Config{CursorKeepAlive: time.Minute}
+
+	c.Convey("Given no cursor parameter", t, func() {
+		pit, err := parseCursor(context.Background(), url.Values{}, cfg)
+
+		c.Convey("Then the search is not cursor-paged", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(pit, c.ShouldBeNil)
+		})
+	})
+
+	c.Convey("Given the start cursor", t, func() {
+		pit, err := parseCursor(context.Background(), url.Values{ParamCursor: []string{"*"}}, cfg)
+
+		c.Convey("Then a new point in time is requested", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(pit, c.ShouldResemble, &query.PointInTime{KeepAlive: "60s"})
+		})
+	})
+
+	c.Convey("Given a cursor returned by a previous page", t, func() {
+		cursor, err := encodeCursor(searchCursor{PointInTime: "pit-id==", SearchAfter: json.RawMessage(`[1678450504000, 2.5, "/a"]`)})
+		c.So(err, c.ShouldBeNil)
+
+		pit, err := parseCursor(context.Background(), url.Values{ParamCursor: []string{cursor}}, cfg)
+
+		c.Convey("Then the point in time and search_after values are restored", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(pit, c.ShouldResemble, &query.PointInTime{ID: "pit-id==", KeepAlive: "60s", SearchAfter: `[1678450504000,2.5,"/a"]`})
+		})
+	})
+
+	c.Convey("Given cursors that have been tampered with", t, func() {
+		invalidPIT, _ := encodeCursor(searchCursor{PointInTime: `pit"}, "size": 10000`, SearchAfter: json.RawMessage(`[1]`)})
+		invalidAfter, _ := encodeCursor(searchCursor{PointInTime: "pit-id", SearchAfter: json.RawMessage(`[{"a": 1}]`)})
+
+		for _, cursor := range []string{"not a cursor", invalidPIT, invalidAfter} {
+			pit, err := parseCursor(context.Background(), url.Values{ParamCursor: []string{cursor}}, cfg)
+
+			c.So(err, c.ShouldNotBeNil)
+			c.So(err.Error(), c.ShouldEqual, "invalid cursor parameter")
+			c.So(pit, c.ShouldBeNil)
+		}
+	})
+}
+
+func TestNextCursor(t *testing.T) {
+	response := []byte(`{"responses":[` + testPointInTimeResponse + `]}`)
+
+	c.Convey("Given a full page of point in time search results", t, func() {
+		searchReq := &query.SearchRequest{Term: "a", Size: 2, SortBy: "release_date", Now: "2024-01-01T00:00:00Z"}
+		cursor, pitID, err := nextCursor(response, searchReq)
+
+		c.Convey("Then the cursor points after the last hit, using the latest point in time", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(pitID, c.ShouldEqual, "next-pit-id")
+			decoded, decodeErr := decodeCursor(cursor)
+			c.So(decodeErr, c.ShouldBeNil)
+			c.So(decoded.PointInTime, c.ShouldEqual, "next-pit-id")
+			c.So(string(decoded.SearchAfter), c.ShouldEqual, `[1678450503000,"/b"]`)
+		})
+
+		c.Convey("Then the cursor is accepted by later pages of the same search, with any page size", func() {
+			params := url.Values{ParamCursor: []string{cursor}}
+			c.So(checkCursor(context.Background(), params, &query.SearchRequest{Term: "a", Size: 5, SortBy: "release_date", Now: "2024-01-02T00:00:00Z"}), c.ShouldBeNil)
+		})
+
+		c.Convey("Then the cursor is rejected by a search with a different sort", func() {
+			params := url.Values{ParamCursor: []string{cursor}}
+			err := checkCursor(context.Background(), params, &query.SearchRequest{Term: "a", Size: 2, SortBy: "relevance"})
+			c.So(err, c.ShouldNotBeNil)
+			c.So(err.Error(), c.ShouldEqual, "cursor was returned for a different sort, start again with cursor=*")
+		})
+
+		c.Convey("Then the cursor is rejected by a different search", func() {
+			params := url.Values{ParamCursor: []string{cursor}}
+			err := checkCursor(context.Background(), params, &query.SearchRequest{Term: "b", Size: 2, SortBy: "release_date"})
+			c.So(err, c.ShouldNotBeNil)
+			c.So(err.Error(), c.ShouldEqual, "cursor was returned for a different search, start again with cursor=*")
+		})
+	})
+
+	c.Convey("Given the last page of point in time search results", t, func() {
+		cursor, pitID, err := nextCursor(response, &query.SearchRequest{Term: "a", Size: 10})
+
+		c.Convey("Then no cursor is returned", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(cursor, c.ShouldBeEmpty)
+			c.So(pitID, c.ShouldEqual, "next-pit-id")
+		})
+	})
+}
+
+func TestSearchHandlerFuncCursor(t *testing.T) {
+	validator := query.NewSearchQueryParamValidator()
+	cfg := &config.Config{
+		CursorKeepAlive: time.Minute,
+		DefaultSort:     "relevance",
+	}
+	searches, _ := json.Marshal([]client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"pit":{}}`)}})
+
+	c.Convey("Given a search requesting the first page of a cursor", t, func() {
+		qbMock := newQueryBuilderMock(searches, nil)
+		esMock := newDpElasticSearcherMock([]byte(`{"count":3}`), nil)
+		esMock.OpenPointInTimeFunc = func(ctx context.Context, indices []string, keepAlive string) (string, error) {
+			return "pit-id", nil
+		}
+		esMock.PointInTimeSearchFunc = func(ctx context.Context, query []byte) ([]byte, error) {
+			return []byte(testPointInTimeResponse), nil
+		}
+		esMock.ClosePointInTimeFunc = func(ctx context.Context, id string) error {
+			return nil
+		}
+		trMock := newResponseTransformerMock([]byte(`{"count":3,"items":[{"uri":"/a"},{"uri":"/b"}]}`), nil)
+
+		searchHandler := SearchHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		c.Convey("When the search is run", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search?q=a&limit=2&cursor=*", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then a point in time is opened on the search alias and the content query is run against it", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(esMock.OpenPointInTimeCalls(), c.ShouldHaveLength, 1)
+				c.So(esMock.OpenPointInTimeCalls()[0].Indices, c.ShouldResemble, []string{"ons"})
+				c.So(esMock.OpenPointInTimeCalls()[0].KeepAlive, c.ShouldEqual, "60s")
+				c.So(esMock.PointInTimeSearchCalls(), c.ShouldHaveLength, 1)
+				c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 0)
+				c.So(qbMock.BuildSearchQueryCalls()[0].Req.PointInTime.ID, c.ShouldEqual, "pit-id")
+
+				c.Convey("And the response contains a cursor for the next page", func() {
+					var searchResp models.SearchResponse
+					c.So(json.Unmarshal(resp.Body.Bytes(), &searchResp), c.ShouldBeNil)
+					c.So(searchResp.Cursor, c.ShouldNotBeEmpty)
+					c.So(esMock.ClosePointInTimeCalls(), c.ShouldHaveLength, 0)
+				})
+			})
+		})
+
+		c.Convey("When the search is the last page", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search?q=a&limit=5&cursor=*", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then no cursor is returned and the point in time is closed", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				var searchResp models.SearchResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &searchResp), c.ShouldBeNil)
+				c.So(searchResp.Cursor, c.ShouldBeEmpty)
+				c.So(esMock.ClosePointInTimeCalls(), c.ShouldHaveLength, 1)
+				c.So(esMock.ClosePointInTimeCalls()[0].ID, c.ShouldEqual, "next-pit-id")
+			})
+		})
+
+		c.Convey("When an offset is also provided", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search?q=a&offset=10&cursor=*", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then a bad request error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "offset cannot be used with cursor")
+				c.So(esMock.OpenPointInTimeCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	c.Convey("Given a cursor returned by the first page of a search", t, func() {
+		qbMock := newQueryBuilderMock(searches, nil)
+		esMock := newDpElasticSearcherMock([]byte(`{"count":3}`), nil)
+		esMock.OpenPointInTimeFunc = func(ctx context.Context, indices []string, keepAlive string) (string, error) {
+			return "pit-id", nil
+		}
+		esMock.PointInTimeSearchFunc = func(ctx context.Context, query []byte) ([]byte, error) {
+			return []byte(testPointInTimeResponse), nil
+		}
+		trMock := newResponseTransformerMock([]byte(`{"count":3,"items":[]}`), nil)
+
+		searchHandler := SearchHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+		get := func(target string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, target, http.NoBody)
+			resp := httptest.NewRecorder()
+			searchHandler.ServeHTTP(resp, req)
+			return resp
+		}
+
+		var firstPage models.SearchResponse
+		c.So(json.Unmarshal(get("http://localhost:8080/search?q=a&limit=2&cursor=*").Body.Bytes(), &firstPage), c.ShouldBeNil)
+		cursor := firstPage.Cursor
+		c.So(cursor, c.ShouldNotBeEmpty)
+
+		c.Convey("When the next page is requested", func() {
+			resp := get("http://localhost:8080/search?q=a&limit=2&cursor=" + cursor)
+
+			c.Convey("Then it is read from the point in time of the cursor", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(qbMock.BuildSearchQueryCalls()[1].Req.PointInTime.ID, c.ShouldEqual, "next-pit-id")
+				c.So(qbMock.BuildSearchQueryCalls()[1].Req.PointInTime.SearchAfter, c.ShouldEqual, `[1678450503000,"/b"]`)
+			})
+		})
+
+		c.Convey("When the next page is requested with a different sort or query", func() {
+			for _, target := range []string{
+				"http://localhost:8080/search?q=a&limit=2&sort=release_date&cursor=" + cursor,
+				"http://localhost:8080/search?q=b&limit=2&cursor=" + cursor,
+			} {
+				resp := get(target)
+
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				var errResp models.ErrorResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &errResp), c.ShouldBeNil)
+				c.So(errResp.Errors, c.ShouldHaveLength, 1)
+				c.So(errResp.Errors[0].Code, c.ShouldEqual, apierrors.CodeInvalidParameter)
+				c.So(errResp.Errors[0].Param, c.ShouldEqual, ParamCursor)
+			}
+
+			c.Convey("Then the search is not run", func() {
+				c.So(esMock.PointInTimeSearchCalls(), c.ShouldHaveLength, 1)
+			})
+		})
+
+		c.Convey("When the next page is requested once the point in time has expired", func() {
+			esMock.PointInTimeSearchFunc = func(ctx context.Context, query []byte) ([]byte, error) {
+				return nil, esError.StatusError{Err: errors.New("search_context_missing_exception"), Code: http.StatusNotFound}
+			}
+			resp := get("http://localhost:8080/search?q=a&limit=2&cursor=" + cursor)
+
+			c.Convey("Then an invalid cursor parameter error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				var errResp models.ErrorResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &errResp), c.ShouldBeNil)
+				c.So(errResp.Errors, c.ShouldHaveLength, 1)
+				c.So(errResp.Errors[0].Code, c.ShouldEqual, apierrors.CodeInvalidParameter)
+				c.So(errResp.Errors[0].Param, c.ShouldEqual, ParamCursor)
+			})
+		})
+	})
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/models"
+)
+
+// paramErrors collects the invalid query parameters of a request, so that they are all reported together
+type paramErrors []models.Error
+
+// add records that the value of the query parameter is invalid
+func (pe *paramErrors) add(param, value string, err error) {
+	*pe = append(*pe, models.Error{Code: apierrors.CodeInvalidParameter, Description: err.Error(), Param: param, Value: value})
+}
+
+// addCode records an error with the code for the query parameter
+func (pe *paramErrors) addCode(code, param, value, description string) {
+	*pe = append(*pe, models.Error{Code: code, Description: description, Param: param, Value: value})
+}
+
+// writeError writes an error response holding a single error
+func writeError(w http.ResponseWriter, status int, code, description string) {
+	writeErrors(w, status, []models.Error{{Code: code, Description: description}})
+}
+
+// writeErrors writes an error response holding every error
+func writeErrors(w http.ResponseWriter, status int, errs []models.Error) {
+	body, err := json.Marshal(models.ErrorResponse{Errors: errs})
+	if err != nil {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// writeSearchFailed writes a search failed error, unless elasticsearch calls are failing fast as the circuit breaker
+// of the searcher has tripped, in which case 503 Service Unavailable is written with a Retry-After header
+func writeSearchFailed(w http.ResponseWriter, searcher DpElasticSearcher, description string) {
+	if breaker, ok := searcher.(CircuitBreaker); ok {
+		if retryAfter, tripped := breaker.Tripped(); tripped {
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(retryAfter)))
+			writeError(w, http.StatusServiceUnavailable, apierrors.CodeServiceUnavailable, "search is temporarily unavailable")
+			return
+		}
+	}
+
+	writeError(w, http.StatusInternalServerError, apierrors.CodeSearchFailed, description)
+}
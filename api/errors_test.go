@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/dp-search-api/query"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSearchHandlerFuncErrors(t *testing.T) {
+	cfg := &config.Config{DefaultSort: "relevance"}
+
+	c.Convey("Given a search handler", t, func() {
+		esMock := newDpElasticSearcherMock([]byte(validESResponse), nil)
+		searchHandler := SearchHandlerFunc(query.NewSearchQueryParamValidator(), newQueryBuilderMock(nil, nil), cfg, &ClientList{DpESClient: esMock}, newResponseTransformerMock(nil, nil))
+
+		c.Convey("When a search is made with several invalid parameters", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=a&limit=x&offset=y&fromDate=2024-02-01&toDate=2024-01-01", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then every invalid parameter is reported in a JSON error response", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Header().Get("Content-Type"), c.ShouldEqual, "application/json;charset=utf-8")
+				var errResp models.ErrorResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &errResp), c.ShouldBeNil)
+				c.So(errResp.Errors, c.ShouldResemble, []models.Error{
+					{Code: apierrors.CodeInvalidParameter, Description: "invalid limit parameter", Param: ParamLimit, Value: "x"},
+					{Code: apierrors.CodeInvalidParameter, Description: "invalid offset parameter", Param: ParamOffset, Value: "y"},
+					{Code: apierrors.CodeConflictingParameters, Description: "invalid dates - 'from' after 'to'", Param: "fromDate", Value: "2024-02-01"},
+				})
+				c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+
+		c.Convey("When elasticsearch fails to run the search", func() {
+			esMock.MultiSearchFunc = func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
+				return nil, context.DeadlineExceeded
+			}
+			validQueryDocBytes, _ := json.Marshal([]client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"query":{}}`)}})
+			searchHandler = SearchHandlerFunc(query.NewSearchQueryParamValidator(), newQueryBuilderMock(validQueryDocBytes, nil), cfg, &ClientList{DpESClient: esMock}, newResponseTransformerMock(nil, nil))
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=a", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then a search failed error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusInternalServerError)
+				var errResp models.ErrorResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &errResp), c.ShouldBeNil)
+				c.So(errResp.Errors, c.ShouldHaveLength, 1)
+				c.So(errResp.Errors[0].Code, c.ShouldEqual, apierrors.CodeSearchFailed)
+			})
+		})
+	})
+}
+
+func TestSearchReleasesHandlerFuncErrors(t *testing.T) {
+	c.Convey("Given a release calendar search handler", t, func() {
+		searchHandler := SearchReleasesHandlerFunc(query.NewReleaseQueryParamValidator(), &ReleaseQueryBuilderMock{}, &config.Config{}, &ClientList{DpESClient: &DpElasticSearcherMock{}}, &ReleaseResponseTransformerMock{})
+
+		c.Convey("When releases are requested with several invalid parameters", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases?limit=x&offset=y&release-type=z", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then every invalid parameter is reported in a JSON error response", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				var errResp models.ErrorResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &errResp), c.ShouldBeNil)
+				c.So(errResp.Errors, c.ShouldResemble, []models.Error{
+					{Code: apierrors.CodeInvalidParameter, Description: "Invalid limit parameter", Param: ParamLimit, Value: "x"},
+					{Code: apierrors.CodeInvalidParameter, Description: "Invalid offset parameter", Param: ParamOffset, Value: "y"},
+					{Code: apierrors.CodeInvalidParameter, Description: "Invalid release-type parameter", Param: "release-type", Value: "z"},
+				})
+			})
+		})
+	})
+}
+
+// trippedSearcher is a DpElasticSearcher with a circuit breaker
+type trippedSearcher struct {
+	*DpElasticSearcherMock
+	retryAfter time.Duration
+	tripped    bool
+}
+
+func (ts *trippedSearcher) Tripped() (time.Duration, bool) {
+	return ts.retryAfter, ts.tripped
+}
+
+func (ts *trippedSearcher) CircuitBreakerChecker(ctx context.Context, state *health.CheckState) error {
+	return nil
+}
+
+func TestWriteSearchFailed(t *testing.T) {
+	c.Convey("Given a release calendar search handler whose searches fail", t, func() {
+		searcher := &trippedSearcher{DpElasticSearcherMock: &DpElasticSearcherMock{
+			MultiSearchFunc: func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
+				return nil, context.DeadlineExceeded
+			},
+		}}
+		builder := &ReleaseQueryBuilderMock{
+			BuildSearchQueryFunc: func(ctx context.Context, request interface{}) ([]client.Search, error) {
+				return []client.Search{{Query: []byte(`{"query": "test"}`)}}, nil
+			},
+		}
+		searchHandler := SearchReleasesHandlerFunc(query.NewReleaseQueryParamValidator(), builder, &config.Config{}, &ClientList{DpESClient: searcher}, &ReleaseResponseTransformerMock{})
+
+		c.Convey("When the circuit breaker of the searcher has tripped", func() {
+			searcher.retryAfter, searcher.tripped = 2500*time.Millisecond, true
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then service unavailable is returned with the seconds until elasticsearch is tried again", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusServiceUnavailable)
+				c.So(resp.Header().Get("Retry-After"), c.ShouldEqual, "3")
+				var errResp models.ErrorResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &errResp), c.ShouldBeNil)
+				c.So(errResp.Errors[0].Code, c.ShouldEqual, apierrors.CodeServiceUnavailable)
+			})
+		})
+
+		c.Convey("When the circuit breaker of the searcher has not tripped", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then a search failed error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusInternalServerError)
+				c.So(resp.Header().Get("Retry-After"), c.ShouldBeEmpty)
+				var errResp models.ErrorResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &errResp), c.ShouldBeNil)
+				c.So(errResp.Errors[0].Code, c.ShouldEqual, apierrors.CodeSearchFailed)
+			})
+		})
+	})
+}
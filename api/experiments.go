@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/dp-search-api/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// variantHeader is the response header that the experiment variant a request was assigned to is returned in
+const variantHeader = "X-Search-Variant"
+
+type variantContextKey struct{}
+
+// WithExperiments sets the experiments that search requests are assigned to, using the provided request header as
+// the session ID to bucket requests by. It must be called before the search handlers are registered.
+func (a *SearchAPI) WithExperiments(experiments query.Experiments, sessionHeader string) *SearchAPI {
+	a.experiments = experiments
+	a.experimentHeader = sessionHeader
+	return a
+}
+
+// assignVariant assigns the request to the variant requested by the variant parameter or, if none is requested,
+// to a variant bucketed by its session ID. The variant is added to the request context and returned in a header.
+// Responses vary by the session header, so it is listed in the Vary header for caches.
+func (a *SearchAPI) assignVariant(next http.HandlerFunc) http.HandlerFunc {
+	if len(a.experiments) == 0 {
+		return next
+	}
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		w.Header().Add("Vary", a.experimentHeader)
+
+		var variant *query.Variant
+		if variantParam := req.URL.Query().Get(ParamVariant); variantParam != "" {
+			var err error
+			if variant, err = a.experiments.Get(variantParam); err != nil {
+				log.Warn(ctx, err.Error(), log.Data{"param": ParamVariant, "value": variantParam})
+				writeErrors(w, http.StatusBadRequest, []models.Error{{
+					Code:        apierrors.CodeInvalidParameter,
+					Description: "variant parameter provided is invalid: " + err.Error(),
+					Param:       ParamVariant,
+					Value:       variantParam,
+				}})
+				return
+			}
+		} else {
+			variant = a.experiments.Assign(req.Header.Get(a.experimentHeader))
+		}
+
+		if variant == nil {
+			next(w, req)
+			return
+		}
+
+		log.Info(ctx, "search request assigned to experiment variant", log.Data{"experiment": variant.Experiment, "variant": variant.Name})
+		w.Header().Set(variantHeader, variant.Name)
+		next(w, req.WithContext(context.WithValue(ctx, variantContextKey{}, variant)))
+	}
+}
+
+// variantFromContext returns the experiment variant the request was assigned to, if any
+func variantFromContext(ctx context.Context) *query.Variant {
+	variant, _ := ctx.Value(variantContextKey{}).(*query.Variant)
+	return variant
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/dp-search-api/query"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSearchExperiments(t *testing.T) {
+	profiles, err := query.ParseBoostProfiles([]byte(`{"recent": {"recency_decay": {"scale": "365d"}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	experiments, err := query.ParseExperiments([]byte(`[{"name": "recency", "variants": [
+		{"name": "recent", "traffic": 100, "boost_profile": "recent", "templates": "/templates/recent"}
+	]}]`), profiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{DefaultSort: "relevance"}
+	validQueryDocBytes, _ := json.Marshal([]client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"query":{}}`)}})
+
+	c.Convey("Given a search API running an experiment on all traffic", t, func() {
+		qbMock := newQueryBuilderMock(validQueryDocBytes, nil)
+		esMock := newDpElasticSearcherMock([]byte(validESResponse), nil)
+		trMock := newResponseTransformerMock([]byte(validTransformedResponse), nil)
+		searchAPI := newIndexesSearchAPI(esMock).
+			WithExperiments(experiments, "X-Session-Id").
+			RegisterGetSearch(query.NewSearchQueryParamValidator().WithBoostProfiles(profiles), qbMock, cfg, trMock)
+
+		c.Convey("When a search is made in a session", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=census", http.NoBody)
+			req.Header.Set("X-Session-Id", "session-1")
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then the search is built with the variant's boost profile and templates", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				searchReq := qbMock.BuildSearchQueryCalls()[0].Req
+				c.So(searchReq.BoostProfile, c.ShouldEqual, profiles["recent"])
+				c.So(searchReq.TemplateSet, c.ShouldEqual, "recent")
+				c.So(qbMock.BuildCountQueryCalls()[0].Req.TemplateSet, c.ShouldEqual, "recent")
+			})
+
+			c.Convey("And the variant is returned in the header and the results", func() {
+				c.So(resp.Header().Get("X-Search-Variant"), c.ShouldEqual, "recent")
+				c.So(resp.Header().Values("Vary"), c.ShouldContain, "X-Session-Id")
+				var searchResp models.SearchResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &searchResp), c.ShouldBeNil)
+				c.So(searchResp.Experiment, c.ShouldEqual, "recency")
+				c.So(searchResp.Variant, c.ShouldEqual, "recent")
+			})
+		})
+
+		c.Convey("When a search requests a boost profile", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=census&profile=default", http.NoBody)
+			req.Header.Set("X-Session-Id", "session-1")
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then the requested profile is ignored, so the variant's ranking is unchanged", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(qbMock.BuildSearchQueryCalls()[0].Req.BoostProfile, c.ShouldEqual, profiles["recent"])
+			})
+		})
+
+		c.Convey("When a search is made without a session", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=census", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then the default search is used", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("X-Search-Variant"), c.ShouldBeEmpty)
+				c.So(resp.Header().Values("Vary"), c.ShouldContain, "X-Session-Id")
+				c.So(qbMock.BuildSearchQueryCalls()[0].Req.TemplateSet, c.ShouldBeEmpty)
+				c.So(qbMock.BuildSearchQueryCalls()[0].Req.BoostProfile, c.ShouldEqual, profiles[query.DefaultBoostProfile])
+			})
+		})
+
+		c.Convey("When a variant is requested explicitly", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=census&variant=recent", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then the search is assigned to the variant", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("X-Search-Variant"), c.ShouldEqual, "recent")
+			})
+		})
+
+		c.Convey("When an unknown variant is requested", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=census&variant=unknown", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then a bad request error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "variant parameter provided is invalid: variant must be one of: recent")
+				c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+	})
+}
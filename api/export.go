@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/dp-search-api/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+const (
+	ParamFormat  = "format"
+	ParamColumns = "columns"
+
+	exportFormatCSV    = "csv"
+	exportFormatNDJSON = "ndjson"
+
+	// closePointInTimeTimeout bounds closing a point in time once the request that opened it has finished
+	closePointInTimeTimeout = 5 * time.Second
+)
+
+// exportColumns are the item fields that can be exported, by column name
+var exportColumns = map[string]func(item *models.Item) interface{}{
+	"type":             func(item *models.Item) interface{} { return item.DataType },
+	"uri":              func(item *models.Item) interface{} { return item.URI },
+	"title":            func(item *models.Item) interface{} { return item.Title },
+	"summary":          func(item *models.Item) interface{} { return item.Summary },
+	"meta_description": func(item *models.Item) interface{} { return item.MetaDescription },
+	"release_date":     func(item *models.Item) interface{} { return item.ReleaseDate },
+	"edition":          func(item *models.Item) interface{} { return item.Edition },
+	"dataset_id":       func(item *models.Item) interface{} { return item.DatasetID },
+	"cdid":             func(item *models.Item) interface{} { return item.CDID },
+	"keywords":         func(item *models.Item) interface{} { return item.Keywords },
+	"topics":           func(item *models.Item) interface{} { return item.Topics },
+	"canonical_topic":  func(item *models.Item) interface{} { return item.CanonicalTopic },
+	"language":         func(item *models.Item) interface{} { return item.Language },
+	"survey":           func(item *models.Item) interface{} { return item.Survey },
+	"population_type":  func(item *models.Item) interface{} { return item.PopulationType },
+	"provisional_date": func(item *models.Item) interface{} { return item.ProvisionalDate },
+	"cancelled":        func(item *models.Item) interface{} { return item.Cancelled },
+	"finalised":        func(item *models.Item) interface{} { return item.Finalised },
+	"published":        func(item *models.Item) interface{} { return item.Published },
+}
+
+// itemWriter writes exported items in a particular format
+type itemWriter interface {
+	WriteHeader(columns []string) error
+	WriteItem(columns []string, item *models.Item) error
+	Flush() error
+}
+
+// csvItemWriter writes items as CSV rows, with list values separated by semicolons
+type csvItemWriter struct {
+	writer *csv.Writer
+}
+
+func (cw *csvItemWriter) WriteHeader(columns []string) error {
+	return cw.writer.Write(columns)
+}
+
+func (cw *csvItemWriter) WriteItem(columns []string, item *models.Item) error {
+	row := make([]string, len(columns))
+	for i, column := range columns {
+		switch value := exportColumns[column](item).(type) {
+		case string:
+			row[i] = value
+		case []string:
+			row[i] = strings.Join(value, ";")
+		case bool:
+			row[i] = strconv.FormatBool(value)
+		}
+	}
+	return cw.writer.Write(row)
+}
+
+func (cw *csvItemWriter) Flush() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// ndjsonItemWriter writes items as JSON objects holding the exported columns, one per line
+type ndjsonItemWriter struct {
+	encoder *json.Encoder
+}
+
+func (nw *ndjsonItemWriter) WriteHeader(_ []string) error {
+	return nil
+}
+
+func (nw *ndjsonItemWriter) WriteItem(columns []string, item *models.Item) error {
+	row := make(map[string]interface{}, len(columns))
+	for _, column := range columns {
+		row[column] = exportColumns[column](item)
+	}
+	return nw.encoder.Encode(row)
+}
+
+func (nw *ndjsonItemWriter) Flush() error {
+	return nil
+}
+
+// parseExportColumns returns the columns requested by the columns parameter, or the configured default columns
+func parseExportColumns(ctx context.Context, params url.Values, cfg *config.Config) ([]string, error) {
+	columnsParam := paramGet(params, ParamColumns, cfg.ExportColumns)
+	columns := sanitiseURLParams(columnsParam)
+
+	var invalid []string
+	for _, column := range columns {
+		if _, ok := exportColumns[column]; !ok {
+			invalid = append(invalid, column)
+		}
+	}
+
+	if len(columns) == 0 {
+		log.Warn(ctx, "no export columns", log.Data{"param": ParamColumns, "value": columnsParam})
+		return nil, errors.New("invalid columns: at least one column must be provided")
+	}
+
+	if len(invalid) > 0 {
+		log.Warn(ctx, "invalid export columns", log.Data{"param": ParamColumns, "value": columnsParam, "disallowed": invalid})
+		return nil, fmt.Errorf("invalid columns: %s", strings.Join(invalid, ","))
+	}
+
+	return columns, nil
+}
+
+// SearchExportHandlerFunc returns a http handler function that streams every item matching a search as CSV or NDJSON.
+// The items are paged through with a point in time, so that the whole result set is never held in memory. Exports
+// take longer than the server's write timeout allows, so they are given a write deadline of their own, and an export
+// that fails once it has started is aborted so that the client cannot mistake it for a complete one.
+func SearchExportHandlerFunc(validator QueryParamValidator, queryBuilder QueryBuilder, cfg *config.Config, clList *ClientList, transformer ResponseTransformer) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		params := req.URL.Query()
+
+		var errs paramErrors
+
+		format := paramGet(params, ParamFormat, exportFormatCSV)
+		if format != exportFormatCSV && format != exportFormatNDJSON {
+			log.Warn(ctx, "invalid export format", log.Data{"param": ParamFormat, "value": format})
+			errs.addCode(apierrors.CodeInvalidParameter, ParamFormat, format, "invalid format parameter, must be one of: csv, ndjson")
+		}
+
+		columns, err := parseExportColumns(ctx, params, cfg)
+		if err != nil {
+			errs.add(ParamColumns, params.Get(ParamColumns), err)
+		}
+
+		nlpCriteria := getNLPCriteria(ctx, params, cfg, queryBuilder, clList)
+
+		_, searchReq, _, searchErrs := createRequests(req, cfg, validator, nlpCriteria)
+		if errs = append(errs, searchErrs...); len(errs) > 0 {
+			writeErrors(w, http.StatusBadRequest, errs)
+			return
+		}
+
+		searchReq.From = 0
+		searchReq.Size = cfg.ExportPageSize
+		searchReq.Highlight = false
+		searchReq.PointInTime = &query.PointInTime{
+			KeepAlive: fmt.Sprintf("%ds", int(cfg.CursorKeepAlive.Seconds())),
+		}
+
+		// The first page is fetched before anything is written, so that failures can still be reported
+		responseData, err := pointInTimeSearch(ctx, cfg, clList.DpESClient, queryBuilder, searchReq)
+		if err != nil {
+			log.Error(ctx, "export search failed", err)
+			writeSearchFailed(w, clList.DpESClient, "Failed to run export query")
+			return
+		}
+		defer closePointInTime(ctx, clList.DpESClient, searchReq.PointInTime)
+
+		var deadline time.Time
+		if cfg.ExportTimeout > 0 {
+			deadline = time.Now().Add(cfg.ExportTimeout)
+		}
+		if err = http.NewResponseController(w).SetWriteDeadline(deadline); err != nil {
+			log.Warn(ctx, "export write deadline could not be set", log.Data{"error": err.Error()})
+		}
+
+		var writer itemWriter
+		if format == exportFormatCSV {
+			w.Header().Set("Content-Type", "text/csv;charset=utf-8")
+			writer = &csvItemWriter{writer: csv.NewWriter(w)}
+		} else {
+			w.Header().Set("Content-Type", "application/x-ndjson;charset=utf-8")
+			writer = &ndjsonItemWriter{encoder: json.NewEncoder(w)}
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "search-export."+format))
+
+		if err = exportItems(ctx, w, writer, columns, cfg, clList, queryBuilder, transformer, searchReq, responseData); err != nil {
+			// the response has already started, so the connection is aborted rather than ending the response normally
+			log.Error(ctx, "export failed after the response was started", err)
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+// exportItems writes the items of the provided first page and every page after it, flushing after each page
+func exportItems(ctx context.Context, w io.Writer, writer itemWriter, columns []string, cfg *config.Config, clList *ClientList,
+	queryBuilder QueryBuilder, transformer ResponseTransformer, searchReq *query.SearchRequest, responseData []byte) error {
+	if err := writer.WriteHeader(columns); err != nil {
+		return err
+	}
+
+	for {
+		searchAfter, pitID, err := nextSearchAfter(responseData, searchReq.Size)
+		if err != nil {
+			return err
+		}
+		if pitID != "" {
+			searchReq.PointInTime.ID = pitID
+		}
+
+		transformedData, err := transformer.TransformSearchResponse(ctx, responseData, "", false)
+		if err != nil {
+			return fmt.Errorf("transformation of response data failed: %w", err)
+		}
+
+		var searchResponse models.SearchResponse
+		if err = json.Unmarshal(transformedData, &searchResponse); err != nil {
+			return fmt.Errorf("failed to unmarshal the transformed response: %w", err)
+		}
+
+		for i := range searchResponse.Items {
+			if err = writer.WriteItem(columns, &searchResponse.Items[i]); err != nil {
+				return err
+			}
+		}
+
+		if err = writer.Flush(); err != nil {
+			return err
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		if searchAfter == nil {
+			return nil
+		}
+
+		searchReq.PointInTime.SearchAfter = string(searchAfter)
+		if responseData, err = pointInTimeSearch(ctx, cfg, clList.DpESClient, queryBuilder, searchReq); err != nil {
+			return err
+		}
+	}
+}
+
+// closePointInTime releases a point in time once it is no longer needed, rather than leaving it for elasticsearch to
+// expire. It is closed even if the request has been cancelled, e.g. by the client disconnecting.
+func closePointInTime(ctx context.Context, elasticSearchClient DpElasticSearcher, pit *query.PointInTime) {
+	if pit.ID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), closePointInTimeTimeout)
+	defer cancel()
+
+	if err := elasticSearchClient.ClosePointInTime(ctx, pit.ID); err != nil {
+		log.Warn(ctx, "closing point in time failed", log.Data{"error": err.Error()})
+	}
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/dp-search-api/query"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testExportFirstPage = `{"pit_id":"pit-id-2","took":3,"hits":{"total":3,"hits":[
+		{"_source":{"uri":"/a"},"sort":[1678450504000,"/a"]},
+		{"_source":{"uri":"/b"},"sort":[1678450503000,"/b"]}
+	]}}`
+	testExportLastPage = `{"pit_id":"pit-id-3","took":1,"hits":{"total":3,"hits":[
+		{"_source":{"uri":"/c"},"sort":[1678450502000,"/c"]}
+	]}}`
+)
+
+func TestSearchExportHandlerFunc(t *testing.T) {
+	validator := query.NewSearchQueryParamValidator()
+	cfg := &config.Config{
+		CursorKeepAlive: time.Minute,
+		DefaultSort:     "relevance",
+		ExportColumns:   "uri,title",
+		ExportPageSize:  2,
+	}
+	searches, _ := json.Marshal([]client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"pit":{}}`)}})
+
+	newExportMocks := func() (*QueryBuilderMock, *DpElasticSearcherMock, *ResponseTransformerMock) {
+		qbMock := newQueryBuilderMock(searches, nil)
+		esMock := newDpElasticSearcherMock(nil, nil)
+		esMock.OpenPointInTimeFunc = func(ctx context.Context, indices []string, keepAlive string) (string, error) {
+			return "pit-id-1", nil
+		}
+		esMock.PointInTimeSearchFunc = func(ctx context.Context, query []byte) ([]byte, error) {
+			if len(esMock.PointInTimeSearchCalls()) == 1 {
+				return []byte(testExportFirstPage), nil
+			}
+			return []byte(testExportLastPage), nil
+		}
+		esMock.ClosePointInTimeFunc = func(ctx context.Context, id string) error {
+			return nil
+		}
+		trMock := &ResponseTransformerMock{
+			TransformSearchResponseFunc: func(ctx context.Context, responseData []byte, q string, highlight bool) ([]byte, error) {
+				if strings.Contains(string(responseData), "pit-id-2") {
+					return []byte(`{"count":3,"items":[{"uri":"/a","title":"A, first","topics":["1","2"]},{"uri":"/b","title":"B"}]}`), nil
+				}
+				return []byte(`{"count":3,"items":[{"uri":"/c","title":"C"}]}`), nil
+			},
+		}
+		return qbMock, esMock, trMock
+	}
+
+	c.Convey("Given an export of search results that span two pages", t, func() {
+		qbMock, esMock, trMock := newExportMocks()
+		exportHandler := SearchExportHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		c.Convey("When the results are exported as CSV", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/export?q=a&columns=uri,title,topics", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			exportHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then every result is written as a CSV row", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("Content-Type"), c.ShouldEqual, "text/csv;charset=utf-8")
+				c.So(resp.Header().Get("Content-Disposition"), c.ShouldEqual, `attachment; filename="search-export.csv"`)
+				c.So(resp.Body.String(), c.ShouldEqual, "uri,title,topics\n/a,\"A, first\",1;2\n/b,B,\n/c,C,\n")
+			})
+
+			c.Convey("And the pages are fetched using the point in time, which is closed afterwards", func() {
+				c.So(esMock.OpenPointInTimeCalls(), c.ShouldHaveLength, 1)
+				c.So(esMock.PointInTimeSearchCalls(), c.ShouldHaveLength, 2)
+				c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 0)
+				c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 2)
+				lastPage := qbMock.BuildSearchQueryCalls()[1].Req
+				c.So(lastPage.Size, c.ShouldEqual, 2)
+				c.So(lastPage.PointInTime.SearchAfter, c.ShouldEqual, `[1678450503000,"/b"]`)
+				c.So(esMock.ClosePointInTimeCalls(), c.ShouldHaveLength, 1)
+				c.So(esMock.ClosePointInTimeCalls()[0].ID, c.ShouldEqual, "pit-id-3")
+			})
+		})
+
+		c.Convey("When the results are exported as NDJSON with the default columns", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/export?q=a&format=ndjson", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			exportHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then every result is written as a line of JSON", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("Content-Type"), c.ShouldEqual, "application/x-ndjson;charset=utf-8")
+				lines := strings.Split(strings.TrimSpace(resp.Body.String()), "\n")
+				c.So(lines, c.ShouldHaveLength, 3)
+				var row map[string]string
+				c.So(json.Unmarshal([]byte(lines[0]), &row), c.ShouldBeNil)
+				c.So(row, c.ShouldResemble, map[string]string{"uri": "/a", "title": "A, first"})
+			})
+		})
+	})
+
+	c.Convey("Given an export request with invalid parameters", t, func() {
+		qbMock, esMock, trMock := newExportMocks()
+		exportHandler := SearchExportHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		c.Convey("When an unknown format is requested", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/export?q=a&format=xml", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			exportHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then a bad request error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "invalid format parameter, must be one of: csv, ndjson")
+				c.So(esMock.OpenPointInTimeCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+
+		c.Convey("When unknown columns are requested", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/export?q=a&columns=uri,body,secret", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			exportHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then a bad request error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "invalid columns: body,secret")
+				c.So(esMock.OpenPointInTimeCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	c.Convey("Given elasticsearch fails to run the first page of an export", t, func() {
+		qbMock, esMock, trMock := newExportMocks()
+		esMock.PointInTimeSearchFunc = func(ctx context.Context, query []byte) ([]byte, error) {
+			return nil, errors.New("elasticsearch unavailable")
+		}
+		exportHandler := SearchExportHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		c.Convey("When the export is requested", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/export?q=a", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			exportHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then an internal server error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusInternalServerError)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "Failed to run export query")
+				c.So(trMock.TransformSearchResponseCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+	})
+
+	c.Convey("Given elasticsearch fails to run the second page of an export", t, func() {
+		qbMock, esMock, trMock := newExportMocks()
+		esMock.PointInTimeSearchFunc = func(ctx context.Context, query []byte) ([]byte, error) {
+			if len(esMock.PointInTimeSearchCalls()) == 1 {
+				return []byte(testExportFirstPage), nil
+			}
+			return nil, errors.New("elasticsearch unavailable")
+		}
+		var closeErr error
+		esMock.ClosePointInTimeFunc = func(ctx context.Context, id string) error {
+			closeErr = ctx.Err()
+			return nil
+		}
+		exportHandler := SearchExportHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		c.Convey("When the export is requested by a client that then disconnects", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/export?q=a", http.NoBody).WithContext(ctx)
+			resp := httptest.NewRecorder()
+			cancel()
+
+			c.Convey("Then the export is aborted after the first page, so that it cannot be mistaken for a complete one", func() {
+				c.So(func() { exportHandler.ServeHTTP(resp, req) }, c.ShouldPanicWith, http.ErrAbortHandler)
+				c.So(resp.Body.String(), c.ShouldEqual, "uri,title\n/a,\"A, first\"\n/b,B\n")
+
+				c.Convey("And the point in time is still closed", func() {
+					c.So(esMock.ClosePointInTimeCalls(), c.ShouldHaveLength, 1)
+					c.So(closeErr, c.ShouldBeNil)
+				})
+			})
+		})
+	})
+}
+
+func TestCSVItemWriter(t *testing.T) {
+	c.Convey("Given an item with list and boolean values", t, func() {
+		item := &models.Item{URI: "/a", Keywords: []string{"x", "y"}, Cancelled: true}
+		var sb strings.Builder
+		writer := &csvItemWriter{writer: csv.NewWriter(&sb)}
+
+		c.Convey("When it is written as CSV", func() {
+			c.So(writer.WriteItem([]string{"uri", "keywords", "cancelled", "finalised"}, item), c.ShouldBeNil)
+			c.So(writer.Flush(), c.ShouldBeNil)
+
+			c.Convey("Then lists are joined with semicolons and booleans are written as text", func() {
+				c.So(sb.String(), c.ShouldEqual, "/a,x;y,true,false\n")
+			})
+		})
+	})
+}
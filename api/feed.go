@@ -0,0 +1,235 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/feed"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/dp-search-api/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+const (
+	feedFormatAtom = "atom"
+	feedFormatRSS  = "rss"
+
+	searchFeedTitle   = "ONS search results"
+	releasesFeedTitle = "ONS release calendar"
+)
+
+// SearchFeedHandlerFunc returns a http handler function that returns the newest items matching a search as an Atom or
+// RSS feed, with an entry for each item that has a release date
+func SearchFeedHandlerFunc(validator QueryParamValidator, queryBuilder QueryBuilder, cfg *config.Config, clList *ClientList, transformer ResponseTransformer) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+		params := req.URL.Query()
+
+		format, errs := feedFormat(req)
+
+		nlpCriteria := getNLPCriteria(ctx, params, cfg, queryBuilder, clList)
+		q, searchReq, _, searchErrs := createRequests(req, cfg, validator, nlpCriteria)
+		if errs = append(errs, searchErrs...); len(errs) > 0 {
+			writeErrors(w, http.StatusBadRequest, errs)
+			return
+		}
+
+		// feeds list the newest items first, without facet counts, and are not paged by a cursor
+		searchReq.SortBy = "release_date"
+		searchReq.Highlight = false
+		searchReq.Facets = &query.Facets{}
+		searchReq.PointInTime = nil
+
+		responseDataChan := make(chan []byte, 1)
+		processSearchQuery(ctx, cfg, clList.DpESClient, queryBuilder, searchReq, responseDataChan)
+		responseData := <-responseDataChan
+		if responseData == nil {
+			log.Error(ctx, "call to elastic multisearch api failed", errors.New("nil response data"))
+			writeSearchFailed(w, clList.DpESClient, "call to elastic multisearch api failed")
+			return
+		}
+
+		if _, contentErr := failedSearches(responseData, searchReq); contentErr != nil {
+			log.Error(ctx, "elasticsearch content search failed", contentErr)
+			writeSearchFailed(w, clList.DpESClient, "call to elastic multisearch api failed")
+			return
+		}
+
+		responseData, err := transformer.TransformSearchResponse(ctx, responseData, q, searchReq.Highlight)
+		if err != nil {
+			log.Error(ctx, "transformation of response data failed", err)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "failed to transform search result")
+			return
+		}
+
+		var response models.SearchResponse
+		if err = json.Unmarshal(responseData, &response); err != nil {
+			log.Error(ctx, "failed to unmarshal the search response", err)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "failed to transform search result")
+			return
+		}
+
+		title, link := searchFeedTitle, websiteLink(cfg, "/search", "")
+		if q != "" {
+			title = fmt.Sprintf("%s for %q", searchFeedTitle, q)
+			link = websiteLink(cfg, "/search", url.Values{ParamQ: {q}}.Encode())
+		}
+
+		writeFeed(w, req, format, &feed.Feed{
+			ID:      selfLink(req),
+			Title:   title,
+			Link:    link,
+			Entries: itemFeedEntries(cfg, response.Items),
+		})
+	}
+}
+
+// SearchReleasesFeedHandlerFunc returns a http handler function that returns the releases matching a release calendar
+// search as an Atom or RSS feed, with an entry for each release that has a release date
+func SearchReleasesFeedHandlerFunc(validator QueryParamValidator, builder ReleaseQueryBuilder, cfg *config.Config, clList *ClientList, transformer ReleaseResponseTransformer) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		format, errs := feedFormat(req)
+
+		queryString, searchReq, searchErrs := createReleaseRequest(req, validator, false)
+		if errs = append(errs, searchErrs...); len(errs) > 0 {
+			writeErrors(w, http.StatusBadRequest, errs)
+			return
+		}
+		searchReq.Highlight = false
+
+		responseData, _, _ := searchReleases(w, req, builder, clList, transformer, queryString, searchReq, false)
+		if responseData == nil {
+			return // error already handled
+		}
+
+		entries, err := transformer.TransformFeedEntries(ctx, responseData, cfg.WebsiteURL)
+		if err != nil {
+			log.Error(ctx, "transformation of response data to feed entries failed", err)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to transform search result")
+			return
+		}
+
+		title, link := releasesFeedTitle, websiteLink(cfg, "/releasecalendar", "")
+		if queryString != "" {
+			title = fmt.Sprintf("%s for %q", releasesFeedTitle, queryString)
+			link = websiteLink(cfg, "/releasecalendar", url.Values{"query": {queryString}}.Encode())
+		}
+
+		writeFeed(w, req, format, &feed.Feed{
+			ID:      selfLink(req),
+			Title:   title,
+			Link:    link,
+			Entries: entries,
+		})
+	}
+}
+
+// feedFormat returns the format that a feed is requested in: the format parameter if it is set, or else RSS if the
+// Accept header asks for it, or else Atom
+func feedFormat(req *http.Request) (string, paramErrors) {
+	var errs paramErrors
+
+	format := req.URL.Query().Get(ParamFormat)
+	switch format {
+	case feedFormatAtom, feedFormatRSS:
+	case "":
+		format = feedFormatAtom
+		if acceptsMediaType(req.Header.Get("Accept"), feed.RSSContentType) {
+			format = feedFormatRSS
+		}
+	default:
+		log.Warn(req.Context(), "invalid feed format", log.Data{"param": ParamFormat, "value": format})
+		errs.addCode(apierrors.CodeInvalidParameter, ParamFormat, format, "invalid format parameter, must be one of: atom, rss")
+	}
+	return format, errs
+}
+
+// itemFeedEntries returns a feed entry for each search result item that has a release date, linking to the item on the
+// website
+func itemFeedEntries(cfg *config.Config, items []models.Item) []feed.Entry {
+	entries := make([]feed.Entry, 0, len(items))
+	for i := range items {
+		item := &items[i]
+		releaseDate, err := time.Parse(time.RFC3339, item.ReleaseDate)
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, feed.Entry{
+			ID:         websiteLink(cfg, item.URI, ""),
+			Title:      item.Title,
+			Summary:    item.Summary,
+			Published:  releaseDate,
+			Updated:    releaseDate,
+			Categories: []string{item.DataType},
+		})
+	}
+	return entries
+}
+
+// writeFeed writes a feed in the requested format, or 304 Not Modified if the request's If-None-Match header matches
+// its entity tag. The feed is updated when its latest entry was, or at the start of the day if it has no entries, so
+// that the entity tag of a feed only changes with its entries.
+func writeFeed(w http.ResponseWriter, req *http.Request, format string, f *feed.Feed) {
+	ctx := req.Context()
+
+	f.Updated = time.Now().UTC().Truncate(24 * time.Hour)
+	for i := range f.Entries {
+		if i == 0 || f.Entries[i].Updated.After(f.Updated) {
+			f.Updated = f.Entries[i].Updated
+		}
+	}
+
+	marshal, contentType := f.Atom, feed.AtomContentType
+	if format == feedFormatRSS {
+		marshal, contentType = f.RSS, feed.RSSContentType
+	}
+
+	data, err := marshal()
+	if err != nil {
+		log.Error(ctx, "marshalling feed failed", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to transform search result")
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+	if writeNotModified(w, req, newETag(data), nil) {
+		return
+	}
+	w.Header().Set("Content-Type", contentType+";charset=utf-8")
+	if _, err = w.Write(data); err != nil {
+		log.Error(ctx, "writing response failed", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to write http response")
+		return
+	}
+}
+
+// websiteLink returns the URL of a page on the website
+func websiteLink(cfg *config.Config, path, rawQuery string) string {
+	link := strings.TrimSuffix(cfg.WebsiteURL, "/") + path
+	if rawQuery != "" {
+		link += "?" + rawQuery
+	}
+	return link
+}
+
+// selfLink returns the URL that the request was made to, which identifies the feed it is for
+func selfLink(req *http.Request) string {
+	scheme := req.Header.Get("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+		if req.TLS != nil {
+			scheme = "https"
+		}
+	}
+	return scheme + "://" + req.Host + req.URL.RequestURI()
+}
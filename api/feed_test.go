@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/feed"
+	"github.com/ONSdigital/dp-search-api/query"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSearchFeedHandlerFunc(t *testing.T) {
+	validator := query.NewSearchQueryParamValidator()
+	cfg := &config.Config{DefaultSort: "relevance", WebsiteURL: "https://www.ons.gov.uk/"}
+	searches, _ := json.Marshal([]client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"query":{}}`)}})
+
+	newFeedMocks := func() (*QueryBuilderMock, *DpElasticSearcherMock, *ResponseTransformerMock) {
+		trMock := &ResponseTransformerMock{
+			TransformSearchResponseFunc: func(ctx context.Context, responseData []byte, q string, highlight bool) ([]byte, error) {
+				return []byte(`{"count":3,"items":[
+					{"type":"bulletin","uri":"/inflation/december2023","title":"Inflation: December 2023","summary":"Prices","release_date":"2024-01-17T07:00:00.000Z"},
+					{"type":"bulletin","uri":"/inflation/november2023","title":"Inflation: November 2023","release_date":"2023-12-20T07:00:00.000Z"},
+					{"type":"timeseries","uri":"/inflation/cpih","title":"CPIH"}
+				]}`), nil
+			},
+		}
+		return newQueryBuilderMock(searches, nil), newDpElasticSearcherMock([]byte(`{"responses":[{}]}`), nil), trMock
+	}
+
+	c.Convey("Given a feed of search results", t, func() {
+		qbMock, esMock, trMock := newFeedMocks()
+		feedHandler := SearchFeedHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		c.Convey("When the feed is requested", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/feed?q=inflation&sort=title", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			feedHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then the newest results are searched for, without highlighting or facet counts", func() {
+				c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 1)
+				searchReq := qbMock.BuildSearchQueryCalls()[0].Req
+				c.So(searchReq.SortBy, c.ShouldEqual, "release_date")
+				c.So(searchReq.Highlight, c.ShouldBeFalse)
+				c.So(searchReq.Searches(), c.ShouldResemble, []string{query.ContentSearch})
+			})
+
+			c.Convey("Then an Atom feed with an entry for each result with a release date is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("Content-Type"), c.ShouldEqual, "application/atom+xml;charset=utf-8")
+				body := resp.Body.String()
+				c.So(body, c.ShouldContainSubstring, `<link href="http://localhost:23900/search/feed?q=inflation&amp;sort=title" rel="self" type="application/atom+xml"></link>`)
+				c.So(body, c.ShouldContainSubstring, `<link href="https://www.ons.gov.uk/search?q=inflation" rel="alternate" type="text/html"></link>`)
+				c.So(body, c.ShouldContainSubstring, "<title>ONS search results for &#34;inflation&#34;</title>")
+				c.So(strings.Count(body, "<entry>"), c.ShouldEqual, 2)
+				c.So(body, c.ShouldContainSubstring, "<id>https://www.ons.gov.uk/inflation/december2023</id>")
+				c.So(body, c.ShouldContainSubstring, `<category term="bulletin"></category>`)
+				c.So(body, c.ShouldNotContainSubstring, "CPIH")
+			})
+
+			c.Convey("Then the feed is updated when its newest entry was, and tagged", func() {
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "<updated>2024-01-17T07:00:00Z</updated>\n  <link")
+				c.So(resp.Header().Get("ETag"), c.ShouldNotBeEmpty)
+			})
+
+			c.Convey("And the feed is requested again with its entity tag", func() {
+				req = httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/feed?q=inflation&sort=title", http.NoBody)
+				req.Header.Set("If-None-Match", resp.Header().Get("ETag"))
+				notModified := httptest.NewRecorder()
+
+				feedHandler.ServeHTTP(notModified, req)
+
+				c.Convey("Then 304 Not Modified is returned", func() {
+					c.So(notModified.Code, c.ShouldEqual, http.StatusNotModified)
+					c.So(notModified.Body.Len(), c.ShouldEqual, 0)
+				})
+			})
+		})
+
+		c.Convey("When the feed is requested as RSS", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/feed?q=inflation", http.NoBody)
+			req.Header.Set("Accept", feed.RSSContentType)
+			resp := httptest.NewRecorder()
+
+			feedHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then an RSS feed is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("Content-Type"), c.ShouldEqual, "application/rss+xml;charset=utf-8")
+				c.So(strings.Count(resp.Body.String(), "<item>"), c.ShouldEqual, 2)
+			})
+		})
+
+		c.Convey("When the feed is requested in an invalid format, along with other invalid parameters", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/feed?format=json&limit=x", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			feedHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then 400 Bad Request is returned with an error for each parameter", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "invalid format parameter, must be one of: atom, rss")
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "invalid limit parameter")
+				c.So(qbMock.BuildSearchQueryCalls(), c.ShouldBeEmpty)
+			})
+		})
+	})
+
+	c.Convey("Given a feed of search results whose search fails", t, func() {
+		qbMock, _, trMock := newFeedMocks()
+		esMock := newDpElasticSearcherMock(nil, context.DeadlineExceeded)
+		feedHandler := SearchFeedHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		c.Convey("When the feed is requested", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/feed?q=inflation", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			feedHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then 500 Internal Server Error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusInternalServerError)
+				c.So(trMock.TransformSearchResponseCalls(), c.ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestSearchReleasesFeedHandlerFunc(t *testing.T) {
+	builder := &ReleaseQueryBuilderMock{
+		BuildSearchQueryFunc: func(ctx context.Context, request interface{}) ([]client.Search, error) {
+			return []client.Search{{Query: []byte(`{"query": "test"}`)}}, nil
+		},
+	}
+	searcher := &DpElasticSearcherMock{
+		MultiSearchFunc: func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
+			return []byte(`{"responses":[]}`), nil
+		},
+	}
+	transformer := &ReleaseResponseTransformerMock{
+		TransformSearchResponseFunc: func(ctx context.Context, responseData []byte, req query.ReleaseSearchRequest, highlight bool) ([]byte, error) {
+			return []byte(`{"took":1,"releases":[]}`), nil
+		},
+		TransformFeedEntriesFunc: func(ctx context.Context, responseData []byte, websiteURL string) ([]feed.Entry, error) {
+			return []feed.Entry{
+				{ID: websiteURL + "/releases/census2021", Title: "Census 2021", Updated: time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)},
+				{ID: websiteURL + "/releases/census2021b", Title: "Census 2021b", Updated: time.Date(2024, 3, 8, 9, 30, 0, 0, time.UTC)},
+			}, nil
+		},
+	}
+	cfg := &config.Config{WebsiteURL: "https://www.ons.gov.uk"}
+	feedHandler := SearchReleasesFeedHandlerFunc(query.NewReleaseQueryParamValidator(), builder, cfg, &ClientList{DpESClient: searcher}, transformer)
+
+	c.Convey("Given a feed of upcoming census releases", t, func() {
+		req := httptest.NewRequest(http.MethodGet, "https://localhost:23900/search/releases/feed?query=census&release-type=type-upcoming&format=rss", http.NoBody)
+		req.Header.Set("X-Forwarded-Proto", "https")
+
+		c.Convey("When the feed is requested", func() {
+			resp := httptest.NewRecorder()
+
+			feedHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then an RSS feed with an entry for each release is returned, updated when its latest release is", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("Content-Type"), c.ShouldEqual, "application/rss+xml;charset=utf-8")
+				c.So(resp.Header().Get("ETag"), c.ShouldNotBeEmpty)
+				body := resp.Body.String()
+				c.So(body, c.ShouldContainSubstring, "<title>ONS release calendar for &#34;census&#34;</title>")
+				c.So(body, c.ShouldContainSubstring, "<link>https://www.ons.gov.uk/releasecalendar?query=census</link>")
+				c.So(body, c.ShouldContainSubstring, `<atom:link href="https://localhost:23900/search/releases/feed?query=census&amp;release-type=type-upcoming&amp;format=rss"`)
+				c.So(body, c.ShouldContainSubstring, "<lastBuildDate>Fri, 08 Mar 2024 09:30:00 +0000</lastBuildDate>")
+				c.So(strings.Count(body, "<item>"), c.ShouldEqual, 2)
+			})
+
+			c.Convey("Then the releases are searched for without highlighting, and their feed entries link to the website", func() {
+				builderCalls := builder.BuildSearchQueryCalls()
+				searchReq := builderCalls[len(builderCalls)-1].Request.(*query.ReleaseSearchRequest)
+				c.So(searchReq.Highlight, c.ShouldBeFalse)
+				c.So(searchReq.Type, c.ShouldEqual, query.Upcoming)
+				calls := transformer.TransformFeedEntriesCalls()
+				c.So(string(calls[len(calls)-1].ResponseData), c.ShouldEqual, `{"took":1,"releases":[]}`)
+				c.So(calls[len(calls)-1].WebsiteURL, c.ShouldEqual, "https://www.ons.gov.uk")
+			})
+		})
+	})
+
+	c.Convey("Given a release calendar feed request with an invalid parameter", t, func() {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases/feed?format=ics", http.NoBody)
+
+		c.Convey("When the feed is requested", func() {
+			resp := httptest.NewRecorder()
+
+			feedHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then 400 Bad Request is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, `"code":"invalid_parameter"`)
+			})
+		})
+	})
+}
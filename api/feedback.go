@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// maxFeedbackBodySize is the largest feedback request body accepted, as the endpoint is public and unauthenticated
+const maxFeedbackBodySize = 16 * 1024
+
+// CreateSearchFeedbackHandlerFunc records a click on a search result
+func (a SearchAPI) CreateSearchFeedbackHandlerFunc(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	if a.clList.FeedbackSink == nil {
+		writeError(w, http.StatusNotImplemented, apierrors.CodeNotImplemented, "search feedback is not enabled")
+		return
+	}
+
+	var feedback models.Feedback
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxFeedbackBodySize)).Decode(&feedback); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			log.Warn(ctx, "feedback request body too large", log.Data{"limit": maxBytesErr.Limit})
+			writeError(w, http.StatusRequestEntityTooLarge, apierrors.CodeInvalidBody, "request body too large")
+			return
+		}
+		log.Warn(ctx, "invalid feedback request body", log.Data{"error": err.Error()})
+		writeError(w, http.StatusBadRequest, apierrors.CodeInvalidBody, "invalid request body")
+		return
+	}
+
+	if err := validateFeedback(&feedback); err != nil {
+		log.Warn(ctx, "invalid feedback provided", log.Data{"error": err.Error()})
+		writeError(w, http.StatusBadRequest, apierrors.CodeInvalidBody, err.Error())
+		return
+	}
+
+	event := &models.FeedbackEvent{
+		Type:      models.FeedbackClick,
+		RequestID: feedback.RequestID,
+		Query:     feedback.Query,
+		URI:       feedback.URI,
+		Position:  feedback.Position,
+		Variant:   feedback.Variant,
+		Session:   feedback.Session,
+		Timestamp: time.Now().UTC(),
+	}
+	if err := a.clList.FeedbackSink.Record(ctx, event); err != nil {
+		log.Error(ctx, "recording feedback failed with this error", err, log.Data{"request_id": feedback.RequestID})
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateFeedback checks that the clicked result and its position are provided
+func validateFeedback(feedback *models.Feedback) error {
+	if feedback.RequestID == "" {
+		return errors.New("request_id must be provided")
+	}
+	if !strings.HasPrefix(feedback.URI, "/") {
+		return errors.New("uri must be provided and start with /")
+	}
+	if feedback.Position < 1 {
+		return errors.New("position must be 1 or more")
+	}
+	return nil
+}
+
+// recordImpression records the results returned for a search, so that clicks can be joined to them by request ID.
+// Failures are logged rather than returned, so that they do not fail the search.
+func recordImpression(ctx context.Context, clList *ClientList, event *models.FeedbackEvent, response *models.SearchResponse) {
+	if clList.FeedbackSink == nil {
+		return
+	}
+
+	event.Type = models.FeedbackImpression
+	event.URIs = make([]string, 0, len(response.Items))
+	for i := range response.Items {
+		event.URIs = append(event.URIs, response.Items[i].URI)
+	}
+	event.Timestamp = time.Now().UTC()
+
+	if err := clList.FeedbackSink.Record(ctx, event); err != nil {
+		log.Error(ctx, "recording search impression failed", err, log.Data{"request_id": event.RequestID})
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/feedback"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/dp-search-api/query"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCreateSearchFeedbackHandlerFunc(t *testing.T) {
+	c.Convey("Given a search API recording feedback", t, func() {
+		sink := feedback.NewMemorySink()
+		searchAPI := newIndexesSearchAPI(newIndexesESClientMock()).RegisterPostSearchFeedback()
+		searchAPI.clList.FeedbackSink = sink
+
+		c.Convey("When a click is sent", func() {
+			body := `{"request_id": "r1", "query": "cpi", "uri": "/economy/cpi", "position": 2, "variant": "recent", "session": "s1"}`
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:23900/search/feedback", strings.NewReader(body))
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then the click is recorded", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusNoContent)
+				events := sink.Events()
+				c.So(events, c.ShouldHaveLength, 1)
+				c.So(events[0].Type, c.ShouldEqual, models.FeedbackClick)
+				c.So(events[0].RequestID, c.ShouldEqual, "r1")
+				c.So(events[0].URI, c.ShouldEqual, "/economy/cpi")
+				c.So(events[0].Position, c.ShouldEqual, 2)
+				c.So(events[0].Variant, c.ShouldEqual, "recent")
+				c.So(events[0].Session, c.ShouldEqual, "s1")
+				c.So(events[0].Timestamp.IsZero(), c.ShouldBeFalse)
+			})
+		})
+
+		c.Convey("When a click with a body that is too large is sent", func() {
+			body := `{"request_id": "r1", "query": "` + strings.Repeat("a", maxFeedbackBodySize) + `", "uri": "/economy/cpi", "position": 2}`
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:23900/search/feedback", strings.NewReader(body))
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then it is rejected without being recorded", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusRequestEntityTooLarge)
+				c.So(sink.Events(), c.ShouldBeEmpty)
+			})
+		})
+
+		c.Convey("When an invalid click is sent", func() {
+			for _, body := range []string{
+				`not json`,
+				`{"uri": "/economy/cpi", "position": 2}`,
+				`{"request_id": "r1", "uri": "economy", "position": 2}`,
+				`{"request_id": "r1", "uri": "/economy/cpi", "position": 0}`,
+			} {
+				req := httptest.NewRequest(http.MethodPost, "http://localhost:23900/search/feedback", strings.NewReader(body))
+				resp := httptest.NewRecorder()
+
+				searchAPI.Router.ServeHTTP(resp, req)
+
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+			}
+
+			c.Convey("Then nothing is recorded", func() {
+				c.So(sink.Events(), c.ShouldBeEmpty)
+			})
+		})
+	})
+
+	c.Convey("Given a search API that does not record feedback", t, func() {
+		searchAPI := newIndexesSearchAPI(newIndexesESClientMock()).RegisterPostSearchFeedback()
+
+		c.Convey("When a click is sent", func() {
+			body := `{"request_id": "r1", "uri": "/economy/cpi", "position": 2}`
+			req := httptest.NewRequest(http.MethodPost, "http://localhost:23900/search/feedback", strings.NewReader(body))
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then a not implemented error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusNotImplemented)
+			})
+		})
+	})
+}
+
+func TestSearchImpressions(t *testing.T) {
+	c.Convey("Given a search handler recording feedback", t, func() {
+		sink := feedback.NewMemorySink()
+		cfg := &config.Config{DefaultSort: "relevance", DefaultLimit: 10, ExperimentHeader: "X-Session-Id"}
+		searches, _ := json.Marshal([]client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"query":{}}`)}})
+		qbMock := newQueryBuilderMock(searches, nil)
+		esMock := newDpElasticSearcherMock([]byte(validESResponse), nil)
+		trMock := newResponseTransformerMock([]byte(`{"count":2,"items":[{"uri":"/a"},{"uri":"/b"}]}`), nil)
+
+		searchHandler := SearchHandlerFunc(query.NewSearchQueryParamValidator(), qbMock, cfg, &ClientList{DpESClient: esMock, FeedbackSink: sink}, trMock)
+
+		c.Convey("When a search is made", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=cpi&offset=10", http.NoBody)
+			req = req.WithContext(request.WithRequestId(req.Context(), "r1"))
+			req.Header.Set("X-Session-Id", "s1")
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then the request ID is returned with the results", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				var searchResp models.SearchResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &searchResp), c.ShouldBeNil)
+				c.So(searchResp.RequestID, c.ShouldEqual, "r1")
+			})
+
+			c.Convey("And an impression of the results is recorded", func() {
+				events := sink.Events()
+				c.So(events, c.ShouldHaveLength, 1)
+				c.So(events[0].Type, c.ShouldEqual, models.FeedbackImpression)
+				c.So(events[0].RequestID, c.ShouldEqual, "r1")
+				c.So(events[0].Query, c.ShouldEqual, "cpi")
+				c.So(events[0].URIs, c.ShouldResemble, []string{"/a", "/b"})
+				c.So(events[0].Position, c.ShouldEqual, 11)
+				c.So(events[0].Session, c.ShouldEqual, "s1")
+			})
+
+			c.Convey("And the search is repeated with the entity tag of its response", func() {
+				req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=cpi&offset=10", http.NoBody)
+				req = req.WithContext(request.WithRequestId(req.Context(), "r2"))
+				req.Header.Set("If-None-Match", resp.Header().Get("ETag"))
+				notModified := httptest.NewRecorder()
+
+				searchHandler.ServeHTTP(notModified, req)
+
+				c.Convey("Then not modified is returned with a weak entity tag and the new request ID", func() {
+					c.So(notModified.Code, c.ShouldEqual, http.StatusNotModified)
+					c.So(notModified.Header().Get("ETag"), c.ShouldStartWith, "W/")
+					c.So(notModified.Header().Get(request.RequestHeaderKey), c.ShouldEqual, "r2")
+				})
+
+				c.Convey("And an impression is recorded for the repeated search", func() {
+					events := sink.Events()
+					c.So(events, c.ShouldHaveLength, 2)
+					c.So(events[1].RequestID, c.ShouldEqual, "r2")
+					c.So(events[1].URIs, c.ShouldResemble, []string{"/a", "/b"})
+				})
+			})
+		})
+	})
+}
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// searchAlias is the alias that every search query is sent to
+	searchAlias = "ons"
+
+	// searchIndexPattern matches all of the timestamped indexes created by POST /search
+	searchIndexPattern = searchAlias + "*"
+)
+
+// searchIndexName matches the names generated by createIndexName, e.g. ons1689178742000000
+var searchIndexName = regexp.MustCompile(`^` + searchAlias + `\d+$`)
+
+// esIndex is the subset of an elasticsearch GET /{index} response that we need
+type esIndex struct {
+	Aliases  map[string]interface{} `json:"aliases"`
+	Settings struct {
+		Index struct {
+			CreationDate string `json:"creation_date"`
+		} `json:"index"`
+	} `json:"settings"`
+}
+
+// esAliases is an elasticsearch GET /_alias response, keyed by index name
+type esAliases map[string]struct {
+	Aliases map[string]interface{} `json:"aliases"`
+}
+
+// GetSearchIndexesHandlerFunc lists the search indexes along with the aliases that each one holds
+func (a SearchAPI) GetSearchIndexesHandlerFunc(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	responseData, err := a.clList.DpESClient.GetIndices(ctx, []string{searchIndexPattern})
+	if err != nil {
+		log.Error(ctx, "getting indexes failed with this error", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	var indices map[string]esIndex
+	if err = json.Unmarshal(responseData, &indices); err != nil {
+		log.Error(ctx, "failed to unmarshal elasticsearch indexes response", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	indexesResponse := models.IndexesResponse{
+		Alias:   searchAlias,
+		Indexes: make([]models.Index, 0, len(indices)),
+	}
+
+	for name, index := range indices {
+		idx := models.Index{
+			Name:    name,
+			Aliases: make([]string, 0, len(index.Aliases)),
+		}
+		for alias := range index.Aliases {
+			idx.Aliases = append(idx.Aliases, alias)
+		}
+		sort.Strings(idx.Aliases)
+
+		if millis, parseErr := strconv.ParseInt(index.Settings.Index.CreationDate, 10, 64); parseErr == nil {
+			idx.CreatedAt = time.UnixMilli(millis).UTC().Format(time.RFC3339)
+		}
+
+		indexesResponse.Indexes = append(indexesResponse.Indexes, idx)
+	}
+
+	// newest index first, as index names are suffixed with their creation timestamp
+	sort.Slice(indexesResponse.Indexes, func(i, j int) bool {
+		return indexesResponse.Indexes[i].Name > indexesResponse.Indexes[j].Name
+	})
+
+	writeJSON(w, req, http.StatusOK, indexesResponse)
+}
+
+// UpdateSearchIndexAliasHandlerFunc atomically moves the search alias from whichever indexes currently
+// hold it onto the index named in the path
+func (a SearchAPI) UpdateSearchIndexAliasHandlerFunc(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	indexName := mux.Vars(req)["name"]
+	logData := log.Data{"index_name": indexName, "alias": searchAlias}
+
+	if !searchIndexName.MatchString(indexName) {
+		log.Warn(ctx, "invalid index name provided for alias update", logData)
+		writeErrors(w, http.StatusBadRequest, []models.Error{{
+			Code:        apierrors.CodeInvalidParameter,
+			Description: "invalid index name",
+			Param:       "name",
+			Value:       indexName,
+		}})
+		return
+	}
+
+	if _, err := a.clList.DpESClient.GetIndices(ctx, []string{indexName}); err != nil {
+		if esError.ErrorStatus(err) == http.StatusNotFound {
+			log.Warn(ctx, "index to alias not found", logData)
+			writeError(w, http.StatusNotFound, apierrors.CodeNotFound, "index not found")
+			return
+		}
+		log.Error(ctx, "getting index failed with this error", err, logData)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	holders, err := a.getAliasHolders(req)
+	if err != nil {
+		log.Error(ctx, "getting aliases failed with this error", err, logData)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	var removeIndices []string
+	for _, holder := range holders {
+		if holder != indexName {
+			removeIndices = append(removeIndices, holder)
+		}
+	}
+	logData["previous_indexes"] = removeIndices
+
+	if err = a.clList.DpESClient.UpdateAliases(ctx, searchAlias, removeIndices, []string{indexName}); err != nil {
+		log.Error(ctx, "updating alias failed with this error", err, logData)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	log.Info(ctx, "search alias moved to index", logData)
+	purgeCache(ctx, a.clList.ResponseCache, "search alias moved")
+
+	writeJSON(w, req, http.StatusOK, models.UpdateAliasResponse{
+		Alias:           searchAlias,
+		IndexName:       indexName,
+		PreviousIndexes: removeIndices,
+	})
+}
+
+// DeleteSearchIndexHandlerFunc deletes the index named in the path,
+// refusing to delete the index that currently holds the search alias
+func (a SearchAPI) DeleteSearchIndexHandlerFunc(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	indexName := mux.Vars(req)["name"]
+	logData := log.Data{"index_name": indexName}
+
+	if !searchIndexName.MatchString(indexName) {
+		log.Warn(ctx, "invalid index name provided for deletion", logData)
+		writeErrors(w, http.StatusBadRequest, []models.Error{{
+			Code:        apierrors.CodeInvalidParameter,
+			Description: "invalid index name",
+			Param:       "name",
+			Value:       indexName,
+		}})
+		return
+	}
+
+	holders, err := a.getAliasHolders(req)
+	if err != nil {
+		log.Error(ctx, "getting aliases failed with this error", err, logData)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	for _, holder := range holders {
+		if holder == indexName {
+			log.Warn(ctx, "refusing to delete index holding the search alias", logData)
+			writeError(w, http.StatusConflict, apierrors.CodeConflict, "index holds the search alias and cannot be deleted")
+			return
+		}
+	}
+
+	if err = a.clList.DpESClient.DeleteIndex(ctx, indexName); err != nil {
+		if esError.ErrorStatus(err) == http.StatusNotFound {
+			log.Warn(ctx, "index to delete not found", logData)
+			writeError(w, http.StatusNotFound, apierrors.CodeNotFound, "index not found")
+			return
+		}
+		log.Error(ctx, "deleting index failed with this error", err, logData)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	log.Info(ctx, "search index deleted", logData)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getAliasHolders returns the names of the indexes that currently hold the search alias
+func (a SearchAPI) getAliasHolders(req *http.Request) ([]string, error) {
+	responseData, err := a.clList.DpESClient.GetAlias(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	var aliases esAliases
+	if err = json.Unmarshal(responseData, &aliases); err != nil {
+		return nil, errors.New("failed to unmarshal elasticsearch alias response")
+	}
+
+	var holders []string
+	for index, entry := range aliases {
+		if _, ok := entry.Aliases[searchAlias]; ok {
+			holders = append(holders, index)
+		}
+	}
+	sort.Strings(holders)
+
+	return holders, nil
+}
+
+// writeJSON marshals the response and writes it with the provided status code
+func writeJSON(w http.ResponseWriter, req *http.Request, status int, response interface{}) {
+	ctx := req.Context()
+
+	jsonResponse, err := json.Marshal(response)
+	if err != nil {
+		log.Error(ctx, "marshalling response failed", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err = w.Write(jsonResponse); err != nil {
+		log.Error(ctx, "writing response failed", err)
+	}
+}
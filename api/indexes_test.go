@@ -0,0 +1,219 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-authorisation/auth"
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/gorilla/mux"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+const (
+	testIndicesResponse = `{
+		"ons1700000000000000": {"aliases": {"ons": {}}, "settings": {"index": {"creation_date": "1700000000000"}}},
+		"ons1600000000000000": {"aliases": {}, "settings": {"index": {"creation_date": "1600000000000"}}}
+	}`
+	testAliasResponse = `{
+		"ons1700000000000000": {"aliases": {"ons": {}}},
+		"ons1600000000000000": {"aliases": {}},
+		"other": {"aliases": {"other_alias": {}}}
+	}`
+)
+
+func newIndexesESClientMock() *DpElasticSearcherMock {
+	return &DpElasticSearcherMock{
+		GetIndicesFunc: func(ctx context.Context, indexPatterns []string) ([]byte, error) {
+			if indexPatterns[0] == "ons1500000000000000" {
+				return nil, esError.StatusError{Err: errors.New("index_not_found_exception"), Code: http.StatusNotFound}
+			}
+			return []byte(testIndicesResponse), nil
+		},
+		GetAliasFunc: func(ctx context.Context) ([]byte, error) {
+			return []byte(testAliasResponse), nil
+		},
+		UpdateAliasesFunc: func(ctx context.Context, alias string, removeIndices, addIndices []string) error {
+			return nil
+		},
+		DeleteIndexFunc: func(ctx context.Context, indexName string) error {
+			return nil
+		},
+	}
+}
+
+func newIndexesSearchAPI(dpESClient DpElasticSearcher) *SearchAPI {
+	return NewSearchAPI(mux.NewRouter(), &ClientList{DpESClient: dpESClient}, &AuthHandlerMock{
+		RequireFunc: func(_ auth.Permissions, handler http.HandlerFunc) http.HandlerFunc { return handler },
+	})
+}
+
+func TestGetSearchIndexesHandlerFunc(t *testing.T) {
+	c.Convey("Given a Search API with two search indexes", t, func() {
+		dpESClient := newIndexesESClientMock()
+		searchAPI := newIndexesSearchAPI(dpESClient).RegisterSearchIndexes()
+
+		c.Convey("When the indexes are listed", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/indexes", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then the indexes are returned newest first with their aliases", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				var indexes models.IndexesResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &indexes), c.ShouldBeNil)
+				c.So(indexes, c.ShouldResemble, models.IndexesResponse{
+					Alias: "ons",
+					Indexes: []models.Index{
+						{Name: "ons1700000000000000", Aliases: []string{"ons"}, CreatedAt: "2023-11-14T22:13:20Z"},
+						{Name: "ons1600000000000000", Aliases: []string{}, CreatedAt: "2020-09-13T12:26:40Z"},
+					},
+				})
+				c.So(dpESClient.GetIndicesCalls()[0].IndexPatterns, c.ShouldResemble, []string{"ons*"})
+			})
+		})
+	})
+
+	c.Convey("Given elasticsearch fails to return the indexes", t, func() {
+		dpESClient := newIndexesESClientMock()
+		dpESClient.GetIndicesFunc = func(ctx context.Context, indexPatterns []string) ([]byte, error) {
+			return nil, errors.New("something went wrong")
+		}
+		searchAPI := newIndexesSearchAPI(dpESClient).RegisterSearchIndexes()
+
+		c.Convey("When the indexes are listed", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/indexes", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then an internal server error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusInternalServerError)
+			})
+		})
+	})
+}
+
+func TestUpdateSearchIndexAliasHandlerFunc(t *testing.T) {
+	c.Convey("Given a Search API where the search alias is held by another index", t, func() {
+		dpESClient := newIndexesESClientMock()
+		searchAPI := newIndexesSearchAPI(dpESClient).RegisterSearchIndexes()
+
+		c.Convey("When the alias is moved to a new index", func() {
+			req := httptest.NewRequest(http.MethodPut, "http://localhost:23900/search/indexes/ons1600000000000000/alias", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then the alias is removed from the old index and added to the new one in a single update", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(dpESClient.UpdateAliasesCalls(), c.ShouldHaveLength, 1)
+				c.So(dpESClient.UpdateAliasesCalls()[0].Alias, c.ShouldEqual, "ons")
+				c.So(dpESClient.UpdateAliasesCalls()[0].RemoveIndices, c.ShouldResemble, []string{"ons1700000000000000"})
+				c.So(dpESClient.UpdateAliasesCalls()[0].AddIndices, c.ShouldResemble, []string{"ons1600000000000000"})
+
+				var aliasResp models.UpdateAliasResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &aliasResp), c.ShouldBeNil)
+				c.So(aliasResp.IndexName, c.ShouldEqual, "ons1600000000000000")
+				c.So(aliasResp.PreviousIndexes, c.ShouldResemble, []string{"ons1700000000000000"})
+			})
+		})
+
+		c.Convey("When the alias is held by more than one index", func() {
+			dpESClient.GetAliasFunc = func(ctx context.Context) ([]byte, error) {
+				return []byte(`{
+					"ons1700000000000000": {"aliases": {"ons": {}}},
+					"ons1650000000000000": {"aliases": {"ons": {}}},
+					"ons1600000000000000": {"aliases": {}}
+				}`), nil
+			}
+			req := httptest.NewRequest(http.MethodPut, "http://localhost:23900/search/indexes/ons1600000000000000/alias", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then the alias is removed from every index holding it in the same update", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(dpESClient.UpdateAliasesCalls(), c.ShouldHaveLength, 1)
+				c.So(dpESClient.UpdateAliasesCalls()[0].RemoveIndices, c.ShouldResemble, []string{"ons1650000000000000", "ons1700000000000000"})
+				c.So(dpESClient.UpdateAliasesCalls()[0].AddIndices, c.ShouldResemble, []string{"ons1600000000000000"})
+			})
+		})
+
+		c.Convey("When the alias is moved to an index that does not exist", func() {
+			req := httptest.NewRequest(http.MethodPut, "http://localhost:23900/search/indexes/ons1500000000000000/alias", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then a not found error is returned and the alias is left untouched", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusNotFound)
+				c.So(dpESClient.UpdateAliasesCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+
+		c.Convey("When the alias is moved to an index that is not a search index", func() {
+			req := httptest.NewRequest(http.MethodPut, "http://localhost:23900/search/indexes/other/alias", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then a bad request error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(dpESClient.UpdateAliasesCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
+func TestDeleteSearchIndexHandlerFunc(t *testing.T) {
+	c.Convey("Given a Search API with two search indexes", t, func() {
+		dpESClient := newIndexesESClientMock()
+		searchAPI := newIndexesSearchAPI(dpESClient).RegisterSearchIndexes()
+
+		c.Convey("When a stale index is deleted", func() {
+			req := httptest.NewRequest(http.MethodDelete, "http://localhost:23900/search/indexes/ons1600000000000000", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then the index is deleted", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusNoContent)
+				c.So(dpESClient.DeleteIndexCalls(), c.ShouldHaveLength, 1)
+				c.So(dpESClient.DeleteIndexCalls()[0].IndexName, c.ShouldEqual, "ons1600000000000000")
+			})
+		})
+
+		c.Convey("When the index holding the search alias is deleted", func() {
+			req := httptest.NewRequest(http.MethodDelete, "http://localhost:23900/search/indexes/ons1700000000000000", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then a conflict error is returned and the index is kept", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusConflict)
+				c.So(dpESClient.DeleteIndexCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+
+		c.Convey("When an index that does not exist is deleted", func() {
+			dpESClient.DeleteIndexFunc = func(ctx context.Context, indexName string) error {
+				return esError.StatusError{Err: errors.New("index_not_found_exception"), Code: http.StatusNotFound}
+			}
+			req := httptest.NewRequest(http.MethodDelete, "http://localhost:23900/search/indexes/ons1500000000000000", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then a not found error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusNotFound)
+			})
+		})
+	})
+}
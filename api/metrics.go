@@ -0,0 +1,173 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ONSdigital/dp-search-api/metrics"
+	"github.com/gorilla/mux"
+)
+
+// The routes that searches are recorded against
+const (
+	searchRoute           = "/search"
+	releasesRoute         = "/search/releases"
+	releasesCalendarRoute = "/search/releases/calendar"
+)
+
+// Metrics records the requests made to the API, along with the searches and calls to the NLP services made to answer
+// them. Nothing is recorded by nil Metrics, so that metrics are optional.
+type Metrics struct {
+	requests        *metrics.Counter
+	requestDuration *metrics.Histogram
+	searches        *metrics.Counter
+	zeroResults     *metrics.Counter
+	esTook          *metrics.Histogram
+	cacheLookups    *metrics.Counter
+	nlpDuration     *metrics.Histogram
+	nlpFailures     *metrics.Counter
+}
+
+// NewMetrics registers the metrics of the API in the registry
+func NewMetrics(registry *metrics.Registry) *Metrics {
+	return &Metrics{
+		requests: registry.NewCounter("search_api_requests_total",
+			"Requests handled, by method, route and response status", "method", "route", "status"),
+		requestDuration: registry.NewHistogram("search_api_request_duration_seconds",
+			"Time taken to handle requests, by method, route and response status", metrics.DefaultBuckets, "method", "route", "status"),
+		searches: registry.NewCounter("search_api_searches_total",
+			"Searches answered with results, by route", "route"),
+		zeroResults: registry.NewCounter("search_api_zero_result_searches_total",
+			"Searches answered without any results, by route", "route"),
+		esTook: registry.NewHistogram("search_api_elasticsearch_took_seconds",
+			"Time elasticsearch reported taking to run the queries of a search, by route", metrics.DefaultBuckets, "route"),
+		cacheLookups: registry.NewCounter("search_api_response_cache_lookups_total",
+			"Lookups of cached responses, by route and whether the response was cached", "route", "outcome"),
+		nlpDuration: registry.NewHistogram("search_api_nlp_request_duration_seconds",
+			"Time taken by requests to the NLP services, by client", metrics.DefaultBuckets, "client"),
+		nlpFailures: registry.NewCounter("search_api_nlp_request_failures_total",
+			"Failed requests to the NLP services, by client", "client"),
+	}
+}
+
+// WithMetrics records the metrics of the API in the registry, counting and timing every request to a registered route
+func (a *SearchAPI) WithMetrics(registry *metrics.Registry) *SearchAPI {
+	a.clList.Metrics = NewMetrics(registry)
+	a.Router.Use(a.clList.Metrics.middleware)
+	return a
+}
+
+func (m *Metrics) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		route, ok := routeTemplate(req)
+		if !ok {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(recorder, req)
+
+		status := strconv.Itoa(recorder.statusCode())
+		m.requests.Inc(req.Method, route, status)
+		m.requestDuration.Observe(time.Since(start).Seconds(), req.Method, route, status)
+	})
+}
+
+// recordResults records a search answered with the number of results
+func (m *Metrics) recordResults(route string, count int) {
+	if m == nil {
+		return
+	}
+	m.searches.Inc(route)
+	if count == 0 {
+		m.zeroResults.Inc(route)
+	}
+}
+
+// recordTook records the time elasticsearch reported taking to run a search, in milliseconds
+func (m *Metrics) recordTook(route string, took int) {
+	if m == nil {
+		return
+	}
+	m.esTook.Observe(float64(took)/1000, route)
+}
+
+// recordCacheLookup records whether a response was found in the cache
+func (m *Metrics) recordCacheLookup(route string, hit bool) {
+	if m == nil {
+		return
+	}
+	outcome := "miss"
+	if hit {
+		outcome = "hit"
+	}
+	m.cacheLookups.Inc(route, outcome)
+}
+
+// recordNLPRequest records the time taken by a request to an NLP service started at start, and whether it failed
+func (m *Metrics) recordNLPRequest(client string, start time.Time, failed bool) {
+	if m == nil {
+		return
+	}
+	m.nlpDuration.Observe(time.Since(start).Seconds(), client)
+	if failed {
+		m.nlpFailures.Inc(client)
+	}
+}
+
+// routeTemplate returns the path template of the route matched by the request
+func routeTemplate(req *http.Request) (string, bool) {
+	route := mux.CurrentRoute(req)
+	if route == nil {
+		return "", false
+	}
+
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return "", false
+	}
+	return template, true
+}
+
+// statusRecorder records the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(statusCode int) {
+	if sr.status == 0 {
+		sr.status = statusCode
+	}
+	sr.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (sr *statusRecorder) Write(b []byte) (int, error) {
+	if sr.status == 0 {
+		sr.status = http.StatusOK
+	}
+	return sr.ResponseWriter.Write(b)
+}
+
+// Flush flushes the response, so that streamed exports are still written as they are read
+func (sr *statusRecorder) Flush() {
+	if flusher, ok := sr.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying response writer, for use by http.ResponseController
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// statusCode returns the status code written, which is 200 OK if the handler wrote nothing
+func (sr *statusRecorder) statusCode() int {
+	if sr.status == 0 {
+		return http.StatusOK
+	}
+	return sr.status
+}
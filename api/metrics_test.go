@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	brErr "github.com/ONSdigital/dp-api-clients-go/v2/nlp/berlin/errors"
+	catModels "github.com/ONSdigital/dp-api-clients-go/v2/nlp/category/models"
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/metrics"
+	"github.com/ONSdigital/dp-search-api/query"
+	scrModels "github.com/ONSdigital/dp-search-scrubber-api/models"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSearchMetrics(t *testing.T) {
+	cfg := &config.Config{DefaultSort: "relevance"}
+	validQueryDocBytes, _ := json.Marshal([]client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"query":{}}`)}})
+
+	c.Convey("Given a search API recording metrics and caching responses", t, func() {
+		registry := metrics.NewRegistry()
+		esMock := newDpElasticSearcherMock([]byte(validESResponse), nil)
+		searchAPI := newIndexesSearchAPI(esMock).
+			WithMetrics(registry).
+			RegisterGetSearch(query.NewSearchQueryParamValidator(), newQueryBuilderMock(validQueryDocBytes, nil), cfg,
+				newResponseTransformerMock([]byte(validTransformedResponse), nil))
+		searchAPI.clList.ResponseCache = NewLRUCache(10, time.Minute)
+		m := searchAPI.clList.Metrics
+
+		c.Convey("When the same search without results is made twice, and an invalid search once", func() {
+			for _, url := range []string{"/search?q=census", "/search?q=census", "/search?limit=x"} {
+				searchAPI.Router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:23900"+url, http.NoBody))
+			}
+
+			c.Convey("Then every request is counted and timed by route and status", func() {
+				c.So(m.requests.Value(http.MethodGet, "/search", "200"), c.ShouldEqual, 2)
+				c.So(m.requests.Value(http.MethodGet, "/search", "400"), c.ShouldEqual, 1)
+				c.So(m.requestDuration.Count(http.MethodGet, "/search", "200"), c.ShouldEqual, 2)
+			})
+
+			c.Convey("And both searches are counted as having no results", func() {
+				c.So(m.searches.Value("/search"), c.ShouldEqual, 2)
+				c.So(m.zeroResults.Value("/search"), c.ShouldEqual, 2)
+			})
+
+			c.Convey("And the time elasticsearch took is only recorded for the search it ran", func() {
+				c.So(m.esTook.Count("/search"), c.ShouldEqual, 1)
+			})
+
+			c.Convey("And the cache lookups are counted", func() {
+				c.So(m.cacheLookups.Value("/search", "miss"), c.ShouldEqual, 1)
+				c.So(m.cacheLookups.Value("/search", "hit"), c.ShouldEqual, 1)
+			})
+
+			c.Convey("And the metrics are served by the registry", func() {
+				resp := httptest.NewRecorder()
+				registry.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://localhost:23900/metrics", http.NoBody))
+				c.So(resp.Body.String(), c.ShouldContainSubstring, `search_api_requests_total{method="GET",route="/search",status="200"} 2`)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, `search_api_zero_result_searches_total{route="/search"} 2`)
+			})
+		})
+	})
+
+	c.Convey("Given a release calendar search handler recording metrics", t, func() {
+		m := NewMetrics(metrics.NewRegistry())
+		builder := &ReleaseQueryBuilderMock{
+			BuildSearchQueryFunc: func(ctx context.Context, request interface{}) ([]client.Search, error) {
+				return []client.Search{{Query: []byte(`{"query": "test"}`)}}, nil
+			},
+		}
+		searcher := &DpElasticSearcherMock{
+			MultiSearchFunc: func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
+				return []byte(`{"responses":[]}`), nil
+			},
+		}
+		transformer := &ReleaseResponseTransformerMock{
+			TransformSearchResponseFunc: func(ctx context.Context, responseData []byte, req query.ReleaseSearchRequest, highlight bool) ([]byte, error) {
+				return []byte(`{"took":250,"breakdown":{"total":3},"releases":[]}`), nil
+			},
+		}
+		searchHandler := SearchReleasesHandlerFunc(query.NewReleaseQueryParamValidator(), builder, &config.Config{}, &ClientList{DpESClient: searcher, Metrics: m}, transformer)
+
+		c.Convey("When releases are searched", func() {
+			searchHandler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases", http.NoBody))
+
+			c.Convey("Then the search and the time elasticsearch took are recorded", func() {
+				c.So(m.searches.Value("/search/releases"), c.ShouldEqual, 1)
+				c.So(m.zeroResults.Value("/search/releases"), c.ShouldEqual, 0)
+				c.So(m.esTook.Count("/search/releases"), c.ShouldEqual, 1)
+				c.So(m.cacheLookups.Value("/search/releases", "miss"), c.ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestNLPMetrics(t *testing.T) {
+	c.Convey("Given NLP clients where berlin is failing", t, func() {
+		m := NewMetrics(metrics.NewRegistry())
+		clList := &ClientList{
+			ScrubberClient: newScrubberClienterMock(&scrModels.ScrubberResp{Query: "dentists in london"}, nil),
+			BerlinClient:   newBerlinClienterMock(nil, brErr.StatusError{Err: context.DeadlineExceeded}),
+			CategoryClient: newCategoryClienterMock(&[]catModels.Category{}, nil),
+			Metrics:        m,
+		}
+
+		c.Convey("When NLP is added to a search", func() {
+			AddNlpToSearch(context.Background(), newQueryBuilderMock(nil, nil), url.Values{"q": []string{"dentists in london"}}, query.NlpSettings{}, clList)
+
+			c.Convey("Then the requests to every client are timed and the berlin failure is counted", func() {
+				for _, client := range []string{"scrubber", "berlin", "category"} {
+					c.So(m.nlpDuration.Count(client), c.ShouldEqual, 1)
+				}
+				c.So(m.nlpFailures.Value("berlin"), c.ShouldEqual, 1)
+				c.So(m.nlpFailures.Value("scrubber"), c.ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	c.Convey("Given a handler that streams its response behind the metrics middleware", t, func() {
+		m := NewMetrics(metrics.NewRegistry())
+		searchAPI := newIndexesSearchAPI(nil)
+		searchAPI.Router.Use(m.middleware)
+		searchAPI.Router.HandleFunc("/search/export", func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte("uri\n"))
+			w.(http.Flusher).Flush()
+		})
+
+		c.Convey("When a request is made", func() {
+			resp := httptest.NewRecorder()
+			searchAPI.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/export", http.NoBody))
+
+			c.Convey("Then the response is flushed and counted as 200 OK", func() {
+				c.So(resp.Flushed, c.ShouldBeTrue)
+				c.So(m.requests.Value(http.MethodGet, "/search/export", "200"), c.ShouldEqual, 1)
+			})
+		})
+
+		c.Convey("When a request is made to an unknown route", func() {
+			resp := httptest.NewRecorder()
+			searchAPI.Router.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://localhost:23900/unknown", http.NoBody))
+
+			c.Convey("Then it is not counted", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusNotFound)
+				c.So(m.requests.Value(http.MethodGet, "/unknown", "404"), c.ShouldEqual, 0)
+			})
+		})
+	})
+}
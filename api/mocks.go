@@ -8,6 +8,7 @@ import (
 	"github.com/ONSdigital/dp-authorisation/auth"
 	"github.com/ONSdigital/dp-elasticsearch/v3/client"
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-search-api/feed"
 	"github.com/ONSdigital/dp-search-api/query"
 	"net/http"
 	"sync"
@@ -172,15 +173,42 @@ var _ DpElasticSearcher = &DpElasticSearcherMock{}
 //			CheckerFunc: func(ctx context.Context, state *health.CheckState) error {
 //				panic("mock out the Checker method")
 //			},
+//			ClosePointInTimeFunc: func(ctx context.Context, id string) error {
+//				panic("mock out the ClosePointInTime method")
+//			},
 //			CountFunc: func(ctx context.Context, count client.Count) ([]byte, error) {
 //				panic("mock out the Count method")
 //			},
 //			CreateIndexFunc: func(ctx context.Context, indexName string, indexSettings []byte) error {
 //				panic("mock out the CreateIndex method")
 //			},
+//			DeleteIndexFunc: func(ctx context.Context, indexName string) error {
+//				panic("mock out the DeleteIndex method")
+//			},
+//			GetAliasFunc: func(ctx context.Context) ([]byte, error) {
+//				panic("mock out the GetAlias method")
+//			},
+//			GetIndexSettingsFunc: func(ctx context.Context, index string) ([]byte, error) {
+//				panic("mock out the GetIndexSettings method")
+//			},
+//			GetIndicesFunc: func(ctx context.Context, indexPatterns []string) ([]byte, error) {
+//				panic("mock out the GetIndices method")
+//			},
 //			MultiSearchFunc: func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
 //				panic("mock out the MultiSearch method")
 //			},
+//			OpenPointInTimeFunc: func(ctx context.Context, indices []string, keepAlive string) (string, error) {
+//				panic("mock out the OpenPointInTime method")
+//			},
+//			PointInTimeSearchFunc: func(ctx context.Context, query []byte) ([]byte, error) {
+//				panic("mock out the PointInTimeSearch method")
+//			},
+//			UpdateAliasesFunc: func(ctx context.Context, alias string, removeIndices []string, addIndices []string) error {
+//				panic("mock out the UpdateAliases method")
+//			},
+//			UpdateAnalysisSettingsFunc: func(ctx context.Context, index string, settings []byte) error {
+//				panic("mock out the UpdateAnalysisSettings method")
+//			},
 //		}
 //
 //		// use mockedDpElasticSearcher in code that requires DpElasticSearcher
@@ -191,15 +219,42 @@ type DpElasticSearcherMock struct {
 	// CheckerFunc mocks the Checker method.
 	CheckerFunc func(ctx context.Context, state *health.CheckState) error
 
+	// ClosePointInTimeFunc mocks the ClosePointInTime method.
+	ClosePointInTimeFunc func(ctx context.Context, id string) error
+
 	// CountFunc mocks the Count method.
 	CountFunc func(ctx context.Context, count client.Count) ([]byte, error)
 
 	// CreateIndexFunc mocks the CreateIndex method.
 	CreateIndexFunc func(ctx context.Context, indexName string, indexSettings []byte) error
 
+	// DeleteIndexFunc mocks the DeleteIndex method.
+	DeleteIndexFunc func(ctx context.Context, indexName string) error
+
+	// GetAliasFunc mocks the GetAlias method.
+	GetAliasFunc func(ctx context.Context) ([]byte, error)
+
+	// GetIndexSettingsFunc mocks the GetIndexSettings method.
+	GetIndexSettingsFunc func(ctx context.Context, index string) ([]byte, error)
+
+	// GetIndicesFunc mocks the GetIndices method.
+	GetIndicesFunc func(ctx context.Context, indexPatterns []string) ([]byte, error)
+
 	// MultiSearchFunc mocks the MultiSearch method.
 	MultiSearchFunc func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error)
 
+	// OpenPointInTimeFunc mocks the OpenPointInTime method.
+	OpenPointInTimeFunc func(ctx context.Context, indices []string, keepAlive string) (string, error)
+
+	// PointInTimeSearchFunc mocks the PointInTimeSearch method.
+	PointInTimeSearchFunc func(ctx context.Context, query []byte) ([]byte, error)
+
+	// UpdateAliasesFunc mocks the UpdateAliases method.
+	UpdateAliasesFunc func(ctx context.Context, alias string, removeIndices []string, addIndices []string) error
+
+	// UpdateAnalysisSettingsFunc mocks the UpdateAnalysisSettings method.
+	UpdateAnalysisSettingsFunc func(ctx context.Context, index string, settings []byte) error
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// Checker holds details about calls to the Checker method.
@@ -209,6 +264,13 @@ type DpElasticSearcherMock struct {
 			// State is the state argument value.
 			State *health.CheckState
 		}
+		// ClosePointInTime holds details about calls to the ClosePointInTime method.
+		ClosePointInTime []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID string
+		}
 		// Count holds details about calls to the Count method.
 		Count []struct {
 			// Ctx is the ctx argument value.
@@ -225,6 +287,32 @@ type DpElasticSearcherMock struct {
 			// IndexSettings is the indexSettings argument value.
 			IndexSettings []byte
 		}
+		// DeleteIndex holds details about calls to the DeleteIndex method.
+		DeleteIndex []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// IndexName is the indexName argument value.
+			IndexName string
+		}
+		// GetAlias holds details about calls to the GetAlias method.
+		GetAlias []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// GetIndexSettings holds details about calls to the GetIndexSettings method.
+		GetIndexSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Index is the index argument value.
+			Index string
+		}
+		// GetIndices holds details about calls to the GetIndices method.
+		GetIndices []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// IndexPatterns is the indexPatterns argument value.
+			IndexPatterns []string
+		}
 		// MultiSearch holds details about calls to the MultiSearch method.
 		MultiSearch []struct {
 			// Ctx is the ctx argument value.
@@ -234,11 +322,56 @@ type DpElasticSearcherMock struct {
 			// Params is the params argument value.
 			Params *client.QueryParams
 		}
+		// OpenPointInTime holds details about calls to the OpenPointInTime method.
+		OpenPointInTime []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Indices is the indices argument value.
+			Indices []string
+			// KeepAlive is the keepAlive argument value.
+			KeepAlive string
+		}
+		// PointInTimeSearch holds details about calls to the PointInTimeSearch method.
+		PointInTimeSearch []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Query is the query argument value.
+			Query []byte
+		}
+		// UpdateAliases holds details about calls to the UpdateAliases method.
+		UpdateAliases []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Alias is the alias argument value.
+			Alias string
+			// RemoveIndices is the removeIndices argument value.
+			RemoveIndices []string
+			// AddIndices is the addIndices argument value.
+			AddIndices []string
+		}
+		// UpdateAnalysisSettings holds details about calls to the UpdateAnalysisSettings method.
+		UpdateAnalysisSettings []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Index is the index argument value.
+			Index string
+			// Settings is the settings argument value.
+			Settings []byte
+		}
 	}
-	lockChecker     sync.RWMutex
-	lockCount       sync.RWMutex
-	lockCreateIndex sync.RWMutex
-	lockMultiSearch sync.RWMutex
+	lockChecker                sync.RWMutex
+	lockClosePointInTime       sync.RWMutex
+	lockCount                  sync.RWMutex
+	lockCreateIndex            sync.RWMutex
+	lockDeleteIndex            sync.RWMutex
+	lockGetAlias               sync.RWMutex
+	lockGetIndexSettings       sync.RWMutex
+	lockGetIndices             sync.RWMutex
+	lockMultiSearch            sync.RWMutex
+	lockOpenPointInTime        sync.RWMutex
+	lockPointInTimeSearch      sync.RWMutex
+	lockUpdateAliases          sync.RWMutex
+	lockUpdateAnalysisSettings sync.RWMutex
 }
 
 // Checker calls CheckerFunc.
@@ -277,6 +410,42 @@ func (mock *DpElasticSearcherMock) CheckerCalls() []struct {
 	return calls
 }
 
+// ClosePointInTime calls ClosePointInTimeFunc.
+func (mock *DpElasticSearcherMock) ClosePointInTime(ctx context.Context, id string) error {
+	if mock.ClosePointInTimeFunc == nil {
+		panic("DpElasticSearcherMock.ClosePointInTimeFunc: method is nil but DpElasticSearcher.ClosePointInTime was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		ID  string
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockClosePointInTime.Lock()
+	mock.calls.ClosePointInTime = append(mock.calls.ClosePointInTime, callInfo)
+	mock.lockClosePointInTime.Unlock()
+	return mock.ClosePointInTimeFunc(ctx, id)
+}
+
+// ClosePointInTimeCalls gets all the calls that were made to ClosePointInTime.
+// Check the length with:
+//
+//	len(mockedDpElasticSearcher.ClosePointInTimeCalls())
+func (mock *DpElasticSearcherMock) ClosePointInTimeCalls() []struct {
+	Ctx context.Context
+	ID  string
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  string
+	}
+	mock.lockClosePointInTime.RLock()
+	calls = mock.calls.ClosePointInTime
+	mock.lockClosePointInTime.RUnlock()
+	return calls
+}
+
 // Count calls CountFunc.
 func (mock *DpElasticSearcherMock) Count(ctx context.Context, count client.Count) ([]byte, error) {
 	if mock.CountFunc == nil {
@@ -353,6 +522,146 @@ func (mock *DpElasticSearcherMock) CreateIndexCalls() []struct {
 	return calls
 }
 
+// DeleteIndex calls DeleteIndexFunc.
+func (mock *DpElasticSearcherMock) DeleteIndex(ctx context.Context, indexName string) error {
+	if mock.DeleteIndexFunc == nil {
+		panic("DpElasticSearcherMock.DeleteIndexFunc: method is nil but DpElasticSearcher.DeleteIndex was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		IndexName string
+	}{
+		Ctx:       ctx,
+		IndexName: indexName,
+	}
+	mock.lockDeleteIndex.Lock()
+	mock.calls.DeleteIndex = append(mock.calls.DeleteIndex, callInfo)
+	mock.lockDeleteIndex.Unlock()
+	return mock.DeleteIndexFunc(ctx, indexName)
+}
+
+// DeleteIndexCalls gets all the calls that were made to DeleteIndex.
+// Check the length with:
+//
+//	len(mockedDpElasticSearcher.DeleteIndexCalls())
+func (mock *DpElasticSearcherMock) DeleteIndexCalls() []struct {
+	Ctx       context.Context
+	IndexName string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		IndexName string
+	}
+	mock.lockDeleteIndex.RLock()
+	calls = mock.calls.DeleteIndex
+	mock.lockDeleteIndex.RUnlock()
+	return calls
+}
+
+// GetAlias calls GetAliasFunc.
+func (mock *DpElasticSearcherMock) GetAlias(ctx context.Context) ([]byte, error) {
+	if mock.GetAliasFunc == nil {
+		panic("DpElasticSearcherMock.GetAliasFunc: method is nil but DpElasticSearcher.GetAlias was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockGetAlias.Lock()
+	mock.calls.GetAlias = append(mock.calls.GetAlias, callInfo)
+	mock.lockGetAlias.Unlock()
+	return mock.GetAliasFunc(ctx)
+}
+
+// GetAliasCalls gets all the calls that were made to GetAlias.
+// Check the length with:
+//
+//	len(mockedDpElasticSearcher.GetAliasCalls())
+func (mock *DpElasticSearcherMock) GetAliasCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockGetAlias.RLock()
+	calls = mock.calls.GetAlias
+	mock.lockGetAlias.RUnlock()
+	return calls
+}
+
+// GetIndexSettings calls GetIndexSettingsFunc.
+func (mock *DpElasticSearcherMock) GetIndexSettings(ctx context.Context, index string) ([]byte, error) {
+	if mock.GetIndexSettingsFunc == nil {
+		panic("DpElasticSearcherMock.GetIndexSettingsFunc: method is nil but DpElasticSearcher.GetIndexSettings was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Index string
+	}{
+		Ctx:   ctx,
+		Index: index,
+	}
+	mock.lockGetIndexSettings.Lock()
+	mock.calls.GetIndexSettings = append(mock.calls.GetIndexSettings, callInfo)
+	mock.lockGetIndexSettings.Unlock()
+	return mock.GetIndexSettingsFunc(ctx, index)
+}
+
+// GetIndexSettingsCalls gets all the calls that were made to GetIndexSettings.
+// Check the length with:
+//
+//	len(mockedDpElasticSearcher.GetIndexSettingsCalls())
+func (mock *DpElasticSearcherMock) GetIndexSettingsCalls() []struct {
+	Ctx   context.Context
+	Index string
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Index string
+	}
+	mock.lockGetIndexSettings.RLock()
+	calls = mock.calls.GetIndexSettings
+	mock.lockGetIndexSettings.RUnlock()
+	return calls
+}
+
+// GetIndices calls GetIndicesFunc.
+func (mock *DpElasticSearcherMock) GetIndices(ctx context.Context, indexPatterns []string) ([]byte, error) {
+	if mock.GetIndicesFunc == nil {
+		panic("DpElasticSearcherMock.GetIndicesFunc: method is nil but DpElasticSearcher.GetIndices was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		IndexPatterns []string
+	}{
+		Ctx:           ctx,
+		IndexPatterns: indexPatterns,
+	}
+	mock.lockGetIndices.Lock()
+	mock.calls.GetIndices = append(mock.calls.GetIndices, callInfo)
+	mock.lockGetIndices.Unlock()
+	return mock.GetIndicesFunc(ctx, indexPatterns)
+}
+
+// GetIndicesCalls gets all the calls that were made to GetIndices.
+// Check the length with:
+//
+//	len(mockedDpElasticSearcher.GetIndicesCalls())
+func (mock *DpElasticSearcherMock) GetIndicesCalls() []struct {
+	Ctx           context.Context
+	IndexPatterns []string
+} {
+	var calls []struct {
+		Ctx           context.Context
+		IndexPatterns []string
+	}
+	mock.lockGetIndices.RLock()
+	calls = mock.calls.GetIndices
+	mock.lockGetIndices.RUnlock()
+	return calls
+}
+
 // MultiSearch calls MultiSearchFunc.
 func (mock *DpElasticSearcherMock) MultiSearch(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
 	if mock.MultiSearchFunc == nil {
@@ -393,6 +702,166 @@ func (mock *DpElasticSearcherMock) MultiSearchCalls() []struct {
 	return calls
 }
 
+// OpenPointInTime calls OpenPointInTimeFunc.
+func (mock *DpElasticSearcherMock) OpenPointInTime(ctx context.Context, indices []string, keepAlive string) (string, error) {
+	if mock.OpenPointInTimeFunc == nil {
+		panic("DpElasticSearcherMock.OpenPointInTimeFunc: method is nil but DpElasticSearcher.OpenPointInTime was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Indices   []string
+		KeepAlive string
+	}{
+		Ctx:       ctx,
+		Indices:   indices,
+		KeepAlive: keepAlive,
+	}
+	mock.lockOpenPointInTime.Lock()
+	mock.calls.OpenPointInTime = append(mock.calls.OpenPointInTime, callInfo)
+	mock.lockOpenPointInTime.Unlock()
+	return mock.OpenPointInTimeFunc(ctx, indices, keepAlive)
+}
+
+// OpenPointInTimeCalls gets all the calls that were made to OpenPointInTime.
+// Check the length with:
+//
+//	len(mockedDpElasticSearcher.OpenPointInTimeCalls())
+func (mock *DpElasticSearcherMock) OpenPointInTimeCalls() []struct {
+	Ctx       context.Context
+	Indices   []string
+	KeepAlive string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Indices   []string
+		KeepAlive string
+	}
+	mock.lockOpenPointInTime.RLock()
+	calls = mock.calls.OpenPointInTime
+	mock.lockOpenPointInTime.RUnlock()
+	return calls
+}
+
+// PointInTimeSearch calls PointInTimeSearchFunc.
+func (mock *DpElasticSearcherMock) PointInTimeSearch(ctx context.Context, query []byte) ([]byte, error) {
+	if mock.PointInTimeSearchFunc == nil {
+		panic("DpElasticSearcherMock.PointInTimeSearchFunc: method is nil but DpElasticSearcher.PointInTimeSearch was just called")
+	}
+	callInfo := struct {
+		Ctx   context.Context
+		Query []byte
+	}{
+		Ctx:   ctx,
+		Query: query,
+	}
+	mock.lockPointInTimeSearch.Lock()
+	mock.calls.PointInTimeSearch = append(mock.calls.PointInTimeSearch, callInfo)
+	mock.lockPointInTimeSearch.Unlock()
+	return mock.PointInTimeSearchFunc(ctx, query)
+}
+
+// PointInTimeSearchCalls gets all the calls that were made to PointInTimeSearch.
+// Check the length with:
+//
+//	len(mockedDpElasticSearcher.PointInTimeSearchCalls())
+func (mock *DpElasticSearcherMock) PointInTimeSearchCalls() []struct {
+	Ctx   context.Context
+	Query []byte
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Query []byte
+	}
+	mock.lockPointInTimeSearch.RLock()
+	calls = mock.calls.PointInTimeSearch
+	mock.lockPointInTimeSearch.RUnlock()
+	return calls
+}
+
+// UpdateAliases calls UpdateAliasesFunc.
+func (mock *DpElasticSearcherMock) UpdateAliases(ctx context.Context, alias string, removeIndices []string, addIndices []string) error {
+	if mock.UpdateAliasesFunc == nil {
+		panic("DpElasticSearcherMock.UpdateAliasesFunc: method is nil but DpElasticSearcher.UpdateAliases was just called")
+	}
+	callInfo := struct {
+		Ctx           context.Context
+		Alias         string
+		RemoveIndices []string
+		AddIndices    []string
+	}{
+		Ctx:           ctx,
+		Alias:         alias,
+		RemoveIndices: removeIndices,
+		AddIndices:    addIndices,
+	}
+	mock.lockUpdateAliases.Lock()
+	mock.calls.UpdateAliases = append(mock.calls.UpdateAliases, callInfo)
+	mock.lockUpdateAliases.Unlock()
+	return mock.UpdateAliasesFunc(ctx, alias, removeIndices, addIndices)
+}
+
+// UpdateAliasesCalls gets all the calls that were made to UpdateAliases.
+// Check the length with:
+//
+//	len(mockedDpElasticSearcher.UpdateAliasesCalls())
+func (mock *DpElasticSearcherMock) UpdateAliasesCalls() []struct {
+	Ctx           context.Context
+	Alias         string
+	RemoveIndices []string
+	AddIndices    []string
+} {
+	var calls []struct {
+		Ctx           context.Context
+		Alias         string
+		RemoveIndices []string
+		AddIndices    []string
+	}
+	mock.lockUpdateAliases.RLock()
+	calls = mock.calls.UpdateAliases
+	mock.lockUpdateAliases.RUnlock()
+	return calls
+}
+
+// UpdateAnalysisSettings calls UpdateAnalysisSettingsFunc.
+func (mock *DpElasticSearcherMock) UpdateAnalysisSettings(ctx context.Context, index string, settings []byte) error {
+	if mock.UpdateAnalysisSettingsFunc == nil {
+		panic("DpElasticSearcherMock.UpdateAnalysisSettingsFunc: method is nil but DpElasticSearcher.UpdateAnalysisSettings was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Index    string
+		Settings []byte
+	}{
+		Ctx:      ctx,
+		Index:    index,
+		Settings: settings,
+	}
+	mock.lockUpdateAnalysisSettings.Lock()
+	mock.calls.UpdateAnalysisSettings = append(mock.calls.UpdateAnalysisSettings, callInfo)
+	mock.lockUpdateAnalysisSettings.Unlock()
+	return mock.UpdateAnalysisSettingsFunc(ctx, index, settings)
+}
+
+// UpdateAnalysisSettingsCalls gets all the calls that were made to UpdateAnalysisSettings.
+// Check the length with:
+//
+//	len(mockedDpElasticSearcher.UpdateAnalysisSettingsCalls())
+func (mock *DpElasticSearcherMock) UpdateAnalysisSettingsCalls() []struct {
+	Ctx      context.Context
+	Index    string
+	Settings []byte
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Index    string
+		Settings []byte
+	}
+	mock.lockUpdateAnalysisSettings.RLock()
+	calls = mock.calls.UpdateAnalysisSettings
+	mock.lockUpdateAnalysisSettings.RUnlock()
+	return calls
+}
+
 // Ensure, that QueryParamValidatorMock does implement QueryParamValidator.
 // If this is not the case, regenerate this file with moq.
 var _ QueryParamValidator = &QueryParamValidatorMock{}
@@ -721,6 +1190,9 @@ var _ ReleaseQueryBuilder = &ReleaseQueryBuilderMock{}
 //
 //		// make and configure a mocked ReleaseQueryBuilder
 //		mockedReleaseQueryBuilder := &ReleaseQueryBuilderMock{
+//			BuildCalendarQueryFunc: func(ctx context.Context, req query.ReleaseCalendarRequest) ([]client.Search, error) {
+//				panic("mock out the BuildCalendarQuery method")
+//			},
 //			BuildSearchQueryFunc: func(ctx context.Context, request interface{}) ([]client.Search, error) {
 //				panic("mock out the BuildSearchQuery method")
 //			},
@@ -731,11 +1203,21 @@ var _ ReleaseQueryBuilder = &ReleaseQueryBuilderMock{}
 //
 //	}
 type ReleaseQueryBuilderMock struct {
+	// BuildCalendarQueryFunc mocks the BuildCalendarQuery method.
+	BuildCalendarQueryFunc func(ctx context.Context, req query.ReleaseCalendarRequest) ([]client.Search, error)
+
 	// BuildSearchQueryFunc mocks the BuildSearchQuery method.
 	BuildSearchQueryFunc func(ctx context.Context, request interface{}) ([]client.Search, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// BuildCalendarQuery holds details about calls to the BuildCalendarQuery method.
+		BuildCalendarQuery []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Req is the req argument value.
+			Req query.ReleaseCalendarRequest
+		}
 		// BuildSearchQuery holds details about calls to the BuildSearchQuery method.
 		BuildSearchQuery []struct {
 			// Ctx is the ctx argument value.
@@ -744,7 +1226,44 @@ type ReleaseQueryBuilderMock struct {
 			Request interface{}
 		}
 	}
-	lockBuildSearchQuery sync.RWMutex
+	lockBuildCalendarQuery sync.RWMutex
+	lockBuildSearchQuery   sync.RWMutex
+}
+
+// BuildCalendarQuery calls BuildCalendarQueryFunc.
+func (mock *ReleaseQueryBuilderMock) BuildCalendarQuery(ctx context.Context, req query.ReleaseCalendarRequest) ([]client.Search, error) {
+	if mock.BuildCalendarQueryFunc == nil {
+		panic("ReleaseQueryBuilderMock.BuildCalendarQueryFunc: method is nil but ReleaseQueryBuilder.BuildCalendarQuery was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Req query.ReleaseCalendarRequest
+	}{
+		Ctx: ctx,
+		Req: req,
+	}
+	mock.lockBuildCalendarQuery.Lock()
+	mock.calls.BuildCalendarQuery = append(mock.calls.BuildCalendarQuery, callInfo)
+	mock.lockBuildCalendarQuery.Unlock()
+	return mock.BuildCalendarQueryFunc(ctx, req)
+}
+
+// BuildCalendarQueryCalls gets all the calls that were made to BuildCalendarQuery.
+// Check the length with:
+//
+//	len(mockedReleaseQueryBuilder.BuildCalendarQueryCalls())
+func (mock *ReleaseQueryBuilderMock) BuildCalendarQueryCalls() []struct {
+	Ctx context.Context
+	Req query.ReleaseCalendarRequest
+} {
+	var calls []struct {
+		Ctx context.Context
+		Req query.ReleaseCalendarRequest
+	}
+	mock.lockBuildCalendarQuery.RLock()
+	calls = mock.calls.BuildCalendarQuery
+	mock.lockBuildCalendarQuery.RUnlock()
+	return calls
 }
 
 // BuildSearchQuery calls BuildSearchQueryFunc.
@@ -783,6 +1302,78 @@ func (mock *ReleaseQueryBuilderMock) BuildSearchQueryCalls() []struct {
 	return calls
 }
 
+// Ensure, that SuggestQueryBuilderMock does implement SuggestQueryBuilder.
+// If this is not the case, regenerate this file with moq.
+var _ SuggestQueryBuilder = &SuggestQueryBuilderMock{}
+
+// SuggestQueryBuilderMock is a mock implementation of SuggestQueryBuilder.
+//
+//	func TestSomethingThatUsesSuggestQueryBuilder(t *testing.T) {
+//
+//		// make and configure a mocked SuggestQueryBuilder
+//		mockedSuggestQueryBuilder := &SuggestQueryBuilderMock{
+//			BuildSearchQueryFunc: func(ctx context.Context, req query.SuggestRequest) ([]client.Search, error) {
+//				panic("mock out the BuildSearchQuery method")
+//			},
+//		}
+//
+//		// use mockedSuggestQueryBuilder in code that requires SuggestQueryBuilder
+//		// and then make assertions.
+//
+//	}
+type SuggestQueryBuilderMock struct {
+	// BuildSearchQueryFunc mocks the BuildSearchQuery method.
+	BuildSearchQueryFunc func(ctx context.Context, req query.SuggestRequest) ([]client.Search, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// BuildSearchQuery holds details about calls to the BuildSearchQuery method.
+		BuildSearchQuery []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Req is the req argument value.
+			Req query.SuggestRequest
+		}
+	}
+	lockBuildSearchQuery sync.RWMutex
+}
+
+// BuildSearchQuery calls BuildSearchQueryFunc.
+func (mock *SuggestQueryBuilderMock) BuildSearchQuery(ctx context.Context, req query.SuggestRequest) ([]client.Search, error) {
+	if mock.BuildSearchQueryFunc == nil {
+		panic("SuggestQueryBuilderMock.BuildSearchQueryFunc: method is nil but SuggestQueryBuilder.BuildSearchQuery was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Req query.SuggestRequest
+	}{
+		Ctx: ctx,
+		Req: req,
+	}
+	mock.lockBuildSearchQuery.Lock()
+	mock.calls.BuildSearchQuery = append(mock.calls.BuildSearchQuery, callInfo)
+	mock.lockBuildSearchQuery.Unlock()
+	return mock.BuildSearchQueryFunc(ctx, req)
+}
+
+// BuildSearchQueryCalls gets all the calls that were made to BuildSearchQuery.
+// Check the length with:
+//
+//	len(mockedSuggestQueryBuilder.BuildSearchQueryCalls())
+func (mock *SuggestQueryBuilderMock) BuildSearchQueryCalls() []struct {
+	Ctx context.Context
+	Req query.SuggestRequest
+} {
+	var calls []struct {
+		Ctx context.Context
+		Req query.SuggestRequest
+	}
+	mock.lockBuildSearchQuery.RLock()
+	calls = mock.calls.BuildSearchQuery
+	mock.lockBuildSearchQuery.RUnlock()
+	return calls
+}
+
 // Ensure, that ResponseTransformerMock does implement ResponseTransformer.
 // If this is not the case, regenerate this file with moq.
 var _ ResponseTransformer = &ResponseTransformerMock{}
@@ -999,6 +1590,15 @@ var _ ReleaseResponseTransformer = &ReleaseResponseTransformerMock{}
 //
 //		// make and configure a mocked ReleaseResponseTransformer
 //		mockedReleaseResponseTransformer := &ReleaseResponseTransformerMock{
+//			TransformCalendarCountsResponseFunc: func(ctx context.Context, responseData []byte, req query.ReleaseCalendarRequest) ([]byte, error) {
+//				panic("mock out the TransformCalendarCountsResponse method")
+//			},
+//			TransformCalendarResponseFunc: func(ctx context.Context, responseData []byte, websiteURL string) ([]byte, error) {
+//				panic("mock out the TransformCalendarResponse method")
+//			},
+//			TransformFeedEntriesFunc: func(ctx context.Context, responseData []byte, websiteURL string) ([]feed.Entry, error) {
+//				panic("mock out the TransformFeedEntries method")
+//			},
 //			TransformSearchResponseFunc: func(ctx context.Context, responseData []byte, req query.ReleaseSearchRequest, highlight bool) ([]byte, error) {
 //				panic("mock out the TransformSearchResponse method")
 //			},
@@ -1009,11 +1609,47 @@ var _ ReleaseResponseTransformer = &ReleaseResponseTransformerMock{}
 //
 //	}
 type ReleaseResponseTransformerMock struct {
+	// TransformCalendarCountsResponseFunc mocks the TransformCalendarCountsResponse method.
+	TransformCalendarCountsResponseFunc func(ctx context.Context, responseData []byte, req query.ReleaseCalendarRequest) ([]byte, error)
+
+	// TransformCalendarResponseFunc mocks the TransformCalendarResponse method.
+	TransformCalendarResponseFunc func(ctx context.Context, responseData []byte, websiteURL string) ([]byte, error)
+
+	// TransformFeedEntriesFunc mocks the TransformFeedEntries method.
+	TransformFeedEntriesFunc func(ctx context.Context, responseData []byte, websiteURL string) ([]feed.Entry, error)
+
 	// TransformSearchResponseFunc mocks the TransformSearchResponse method.
 	TransformSearchResponseFunc func(ctx context.Context, responseData []byte, req query.ReleaseSearchRequest, highlight bool) ([]byte, error)
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// TransformCalendarCountsResponse holds details about calls to the TransformCalendarCountsResponse method.
+		TransformCalendarCountsResponse []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ResponseData is the responseData argument value.
+			ResponseData []byte
+			// Req is the req argument value.
+			Req query.ReleaseCalendarRequest
+		}
+		// TransformCalendarResponse holds details about calls to the TransformCalendarResponse method.
+		TransformCalendarResponse []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ResponseData is the responseData argument value.
+			ResponseData []byte
+			// WebsiteURL is the websiteURL argument value.
+			WebsiteURL string
+		}
+		// TransformFeedEntries holds details about calls to the TransformFeedEntries method.
+		TransformFeedEntries []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ResponseData is the responseData argument value.
+			ResponseData []byte
+			// WebsiteURL is the websiteURL argument value.
+			WebsiteURL string
+		}
 		// TransformSearchResponse holds details about calls to the TransformSearchResponse method.
 		TransformSearchResponse []struct {
 			// Ctx is the ctx argument value.
@@ -1026,7 +1662,130 @@ type ReleaseResponseTransformerMock struct {
 			Highlight bool
 		}
 	}
-	lockTransformSearchResponse sync.RWMutex
+	lockTransformCalendarCountsResponse sync.RWMutex
+	lockTransformCalendarResponse       sync.RWMutex
+	lockTransformFeedEntries            sync.RWMutex
+	lockTransformSearchResponse         sync.RWMutex
+}
+
+// TransformCalendarCountsResponse calls TransformCalendarCountsResponseFunc.
+func (mock *ReleaseResponseTransformerMock) TransformCalendarCountsResponse(ctx context.Context, responseData []byte, req query.ReleaseCalendarRequest) ([]byte, error) {
+	if mock.TransformCalendarCountsResponseFunc == nil {
+		panic("ReleaseResponseTransformerMock.TransformCalendarCountsResponseFunc: method is nil but ReleaseResponseTransformer.TransformCalendarCountsResponse was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		ResponseData []byte
+		Req          query.ReleaseCalendarRequest
+	}{
+		Ctx:          ctx,
+		ResponseData: responseData,
+		Req:          req,
+	}
+	mock.lockTransformCalendarCountsResponse.Lock()
+	mock.calls.TransformCalendarCountsResponse = append(mock.calls.TransformCalendarCountsResponse, callInfo)
+	mock.lockTransformCalendarCountsResponse.Unlock()
+	return mock.TransformCalendarCountsResponseFunc(ctx, responseData, req)
+}
+
+// TransformCalendarCountsResponseCalls gets all the calls that were made to TransformCalendarCountsResponse.
+// Check the length with:
+//
+//	len(mockedReleaseResponseTransformer.TransformCalendarCountsResponseCalls())
+func (mock *ReleaseResponseTransformerMock) TransformCalendarCountsResponseCalls() []struct {
+	Ctx          context.Context
+	ResponseData []byte
+	Req          query.ReleaseCalendarRequest
+} {
+	var calls []struct {
+		Ctx          context.Context
+		ResponseData []byte
+		Req          query.ReleaseCalendarRequest
+	}
+	mock.lockTransformCalendarCountsResponse.RLock()
+	calls = mock.calls.TransformCalendarCountsResponse
+	mock.lockTransformCalendarCountsResponse.RUnlock()
+	return calls
+}
+
+// TransformCalendarResponse calls TransformCalendarResponseFunc.
+func (mock *ReleaseResponseTransformerMock) TransformCalendarResponse(ctx context.Context, responseData []byte, websiteURL string) ([]byte, error) {
+	if mock.TransformCalendarResponseFunc == nil {
+		panic("ReleaseResponseTransformerMock.TransformCalendarResponseFunc: method is nil but ReleaseResponseTransformer.TransformCalendarResponse was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		ResponseData []byte
+		WebsiteURL   string
+	}{
+		Ctx:          ctx,
+		ResponseData: responseData,
+		WebsiteURL:   websiteURL,
+	}
+	mock.lockTransformCalendarResponse.Lock()
+	mock.calls.TransformCalendarResponse = append(mock.calls.TransformCalendarResponse, callInfo)
+	mock.lockTransformCalendarResponse.Unlock()
+	return mock.TransformCalendarResponseFunc(ctx, responseData, websiteURL)
+}
+
+// TransformCalendarResponseCalls gets all the calls that were made to TransformCalendarResponse.
+// Check the length with:
+//
+//	len(mockedReleaseResponseTransformer.TransformCalendarResponseCalls())
+func (mock *ReleaseResponseTransformerMock) TransformCalendarResponseCalls() []struct {
+	Ctx          context.Context
+	ResponseData []byte
+	WebsiteURL   string
+} {
+	var calls []struct {
+		Ctx          context.Context
+		ResponseData []byte
+		WebsiteURL   string
+	}
+	mock.lockTransformCalendarResponse.RLock()
+	calls = mock.calls.TransformCalendarResponse
+	mock.lockTransformCalendarResponse.RUnlock()
+	return calls
+}
+
+// TransformFeedEntries calls TransformFeedEntriesFunc.
+func (mock *ReleaseResponseTransformerMock) TransformFeedEntries(ctx context.Context, responseData []byte, websiteURL string) ([]feed.Entry, error) {
+	if mock.TransformFeedEntriesFunc == nil {
+		panic("ReleaseResponseTransformerMock.TransformFeedEntriesFunc: method is nil but ReleaseResponseTransformer.TransformFeedEntries was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		ResponseData []byte
+		WebsiteURL   string
+	}{
+		Ctx:          ctx,
+		ResponseData: responseData,
+		WebsiteURL:   websiteURL,
+	}
+	mock.lockTransformFeedEntries.Lock()
+	mock.calls.TransformFeedEntries = append(mock.calls.TransformFeedEntries, callInfo)
+	mock.lockTransformFeedEntries.Unlock()
+	return mock.TransformFeedEntriesFunc(ctx, responseData, websiteURL)
+}
+
+// TransformFeedEntriesCalls gets all the calls that were made to TransformFeedEntries.
+// Check the length with:
+//
+//	len(mockedReleaseResponseTransformer.TransformFeedEntriesCalls())
+func (mock *ReleaseResponseTransformerMock) TransformFeedEntriesCalls() []struct {
+	Ctx          context.Context
+	ResponseData []byte
+	WebsiteURL   string
+} {
+	var calls []struct {
+		Ctx          context.Context
+		ResponseData []byte
+		WebsiteURL   string
+	}
+	mock.lockTransformFeedEntries.RLock()
+	calls = mock.calls.TransformFeedEntries
+	mock.lockTransformFeedEntries.RUnlock()
+	return calls
 }
 
 // TransformSearchResponse calls TransformSearchResponseFunc.
@@ -1072,3 +1831,247 @@ func (mock *ReleaseResponseTransformerMock) TransformSearchResponseCalls() []str
 	mock.lockTransformSearchResponse.RUnlock()
 	return calls
 }
+
+// Ensure, that SuggestResponseTransformerMock does implement SuggestResponseTransformer.
+// If this is not the case, regenerate this file with moq.
+var _ SuggestResponseTransformer = &SuggestResponseTransformerMock{}
+
+// SuggestResponseTransformerMock is a mock implementation of SuggestResponseTransformer.
+//
+//	func TestSomethingThatUsesSuggestResponseTransformer(t *testing.T) {
+//
+//		// make and configure a mocked SuggestResponseTransformer
+//		mockedSuggestResponseTransformer := &SuggestResponseTransformerMock{
+//			TransformSuggestResponseFunc: func(ctx context.Context, responseData []byte) ([]byte, error) {
+//				panic("mock out the TransformSuggestResponse method")
+//			},
+//		}
+//
+//		// use mockedSuggestResponseTransformer in code that requires SuggestResponseTransformer
+//		// and then make assertions.
+//
+//	}
+type SuggestResponseTransformerMock struct {
+	// TransformSuggestResponseFunc mocks the TransformSuggestResponse method.
+	TransformSuggestResponseFunc func(ctx context.Context, responseData []byte) ([]byte, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// TransformSuggestResponse holds details about calls to the TransformSuggestResponse method.
+		TransformSuggestResponse []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ResponseData is the responseData argument value.
+			ResponseData []byte
+		}
+	}
+	lockTransformSuggestResponse sync.RWMutex
+}
+
+// TransformSuggestResponse calls TransformSuggestResponseFunc.
+func (mock *SuggestResponseTransformerMock) TransformSuggestResponse(ctx context.Context, responseData []byte) ([]byte, error) {
+	if mock.TransformSuggestResponseFunc == nil {
+		panic("SuggestResponseTransformerMock.TransformSuggestResponseFunc: method is nil but SuggestResponseTransformer.TransformSuggestResponse was just called")
+	}
+	callInfo := struct {
+		Ctx          context.Context
+		ResponseData []byte
+	}{
+		Ctx:          ctx,
+		ResponseData: responseData,
+	}
+	mock.lockTransformSuggestResponse.Lock()
+	mock.calls.TransformSuggestResponse = append(mock.calls.TransformSuggestResponse, callInfo)
+	mock.lockTransformSuggestResponse.Unlock()
+	return mock.TransformSuggestResponseFunc(ctx, responseData)
+}
+
+// TransformSuggestResponseCalls gets all the calls that were made to TransformSuggestResponse.
+// Check the length with:
+//
+//	len(mockedSuggestResponseTransformer.TransformSuggestResponseCalls())
+func (mock *SuggestResponseTransformerMock) TransformSuggestResponseCalls() []struct {
+	Ctx          context.Context
+	ResponseData []byte
+} {
+	var calls []struct {
+		Ctx          context.Context
+		ResponseData []byte
+	}
+	mock.lockTransformSuggestResponse.RLock()
+	calls = mock.calls.TransformSuggestResponse
+	mock.lockTransformSuggestResponse.RUnlock()
+	return calls
+}
+
+// Ensure, that ResponseCacheMock does implement ResponseCache.
+// If this is not the case, regenerate this file with moq.
+var _ ResponseCache = &ResponseCacheMock{}
+
+// ResponseCacheMock is a mock implementation of ResponseCache.
+//
+//	func TestSomethingThatUsesResponseCache(t *testing.T) {
+//
+//		// make and configure a mocked ResponseCache
+//		mockedResponseCache := &ResponseCacheMock{
+//			GetFunc: func(ctx context.Context, key string) (*CachedResponse, bool) {
+//				panic("mock out the Get method")
+//			},
+//			PurgeFunc: func(ctx context.Context) error {
+//				panic("mock out the Purge method")
+//			},
+//			SetFunc: func(ctx context.Context, key string, body []byte) *CachedResponse {
+//				panic("mock out the Set method")
+//			},
+//		}
+//
+//		// use mockedResponseCache in code that requires ResponseCache
+//		// and then make assertions.
+//
+//	}
+type ResponseCacheMock struct {
+	// GetFunc mocks the Get method.
+	GetFunc func(ctx context.Context, key string) (*CachedResponse, bool)
+
+	// PurgeFunc mocks the Purge method.
+	PurgeFunc func(ctx context.Context) error
+
+	// SetFunc mocks the Set method.
+	SetFunc func(ctx context.Context, key string, body []byte) *CachedResponse
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Get holds details about calls to the Get method.
+		Get []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+		}
+		// Purge holds details about calls to the Purge method.
+		Purge []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// Set holds details about calls to the Set method.
+		Set []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Key is the key argument value.
+			Key string
+			// Body is the body argument value.
+			Body []byte
+		}
+	}
+	lockGet   sync.RWMutex
+	lockPurge sync.RWMutex
+	lockSet   sync.RWMutex
+}
+
+// Get calls GetFunc.
+func (mock *ResponseCacheMock) Get(ctx context.Context, key string) (*CachedResponse, bool) {
+	if mock.GetFunc == nil {
+		panic("ResponseCacheMock.GetFunc: method is nil but ResponseCache.Get was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+		Key string
+	}{
+		Ctx: ctx,
+		Key: key,
+	}
+	mock.lockGet.Lock()
+	mock.calls.Get = append(mock.calls.Get, callInfo)
+	mock.lockGet.Unlock()
+	return mock.GetFunc(ctx, key)
+}
+
+// GetCalls gets all the calls that were made to Get.
+// Check the length with:
+//
+//	len(mockedResponseCache.GetCalls())
+func (mock *ResponseCacheMock) GetCalls() []struct {
+	Ctx context.Context
+	Key string
+} {
+	var calls []struct {
+		Ctx context.Context
+		Key string
+	}
+	mock.lockGet.RLock()
+	calls = mock.calls.Get
+	mock.lockGet.RUnlock()
+	return calls
+}
+
+// Purge calls PurgeFunc.
+func (mock *ResponseCacheMock) Purge(ctx context.Context) error {
+	if mock.PurgeFunc == nil {
+		panic("ResponseCacheMock.PurgeFunc: method is nil but ResponseCache.Purge was just called")
+	}
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockPurge.Lock()
+	mock.calls.Purge = append(mock.calls.Purge, callInfo)
+	mock.lockPurge.Unlock()
+	return mock.PurgeFunc(ctx)
+}
+
+// PurgeCalls gets all the calls that were made to Purge.
+// Check the length with:
+//
+//	len(mockedResponseCache.PurgeCalls())
+func (mock *ResponseCacheMock) PurgeCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockPurge.RLock()
+	calls = mock.calls.Purge
+	mock.lockPurge.RUnlock()
+	return calls
+}
+
+// Set calls SetFunc.
+func (mock *ResponseCacheMock) Set(ctx context.Context, key string, body []byte) *CachedResponse {
+	if mock.SetFunc == nil {
+		panic("ResponseCacheMock.SetFunc: method is nil but ResponseCache.Set was just called")
+	}
+	callInfo := struct {
+		Ctx  context.Context
+		Key  string
+		Body []byte
+	}{
+		Ctx:  ctx,
+		Key:  key,
+		Body: body,
+	}
+	mock.lockSet.Lock()
+	mock.calls.Set = append(mock.calls.Set, callInfo)
+	mock.lockSet.Unlock()
+	return mock.SetFunc(ctx, key, body)
+}
+
+// SetCalls gets all the calls that were made to Set.
+// Check the length with:
+//
+//	len(mockedResponseCache.SetCalls())
+func (mock *ResponseCacheMock) SetCalls() []struct {
+	Ctx  context.Context
+	Key  string
+	Body []byte
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Key  string
+		Body []byte
+	}
+	mock.lockSet.RLock()
+	calls = mock.calls.Set
+	mock.lockSet.RUnlock()
+	return calls
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/metrics"
+	"github.com/ONSdigital/dp-search-api/ratelimit"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// RateLimitClients configures how the clients of rate limited routes are identified
+type RateLimitClients struct {
+	// KeyHeader is the request header holding a client's API key
+	KeyHeader string
+	// APIKeys are the API keys that identify clients, any other key sent in the key header is ignored
+	APIKeys []string
+	// TrustedProxies is the number of proxies in front of the API that each append the address they received the
+	// request from to the X-Forwarded-For header, or zero if the API is not behind a proxy
+	TrustedProxies int
+}
+
+// rateLimiter limits the rate of requests each client makes to the routes with a limit
+type rateLimiter struct {
+	store          ratelimit.Store
+	limits         ratelimit.Limits
+	keyHeader      string
+	apiKeys        map[string]bool
+	trustedProxies int
+	requests       *metrics.Counter
+}
+
+// WithRateLimits limits the rate of requests that each client makes to the routes with a limit, keyed by their path
+// template, using the store to hold a token bucket per client and route. Clients are identified by one of the API
+// keys sent in the key header or, if none is sent, by their IP address. Requests are counted in the metrics registry.
+func (a *SearchAPI) WithRateLimits(store ratelimit.Store, limits ratelimit.Limits, clients RateLimitClients, registry *metrics.Registry) *SearchAPI {
+	if len(limits) == 0 {
+		return a
+	}
+
+	limiter := &rateLimiter{
+		store:          store,
+		limits:         limits,
+		keyHeader:      clients.KeyHeader,
+		apiKeys:        make(map[string]bool, len(clients.APIKeys)),
+		trustedProxies: clients.TrustedProxies,
+	}
+	for _, key := range clients.APIKeys {
+		limiter.apiKeys[key] = true
+	}
+	if registry != nil {
+		limiter.requests = registry.NewCounter("search_api_rate_limited_requests_total",
+			"Requests to rate limited routes, by route and whether they were allowed or limited", "route", "outcome")
+	}
+
+	a.Router.Use(limiter.middleware)
+	return a
+}
+
+func (rl *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		route, limit, ok := rl.routeLimit(req)
+		if !ok {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		result, err := rl.store.Take(ctx, route+" "+rl.clientKey(req), limit)
+		if err != nil {
+			// requests are let through rather than failing because the limits cannot be checked
+			log.Error(ctx, "checking rate limit failed", err, log.Data{"route": route})
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			rl.requests.Inc(route, "limited")
+			w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			writeError(w, http.StatusTooManyRequests, apierrors.CodeRateLimited, "rate limit exceeded, retry later")
+			return
+		}
+
+		rl.requests.Inc(route, "allowed")
+		next.ServeHTTP(w, req)
+	})
+}
+
+// routeLimit returns the path template of the route matched by the request, and its limit if it has one
+func (rl *rateLimiter) routeLimit(req *http.Request) (string, ratelimit.Limit, bool) {
+	route, ok := routeTemplate(req)
+	if !ok {
+		return "", ratelimit.Limit{}, false
+	}
+
+	limit, ok := rl.limits[route]
+	return route, limit, ok
+}
+
+// clientKey identifies the client by its API key if it sends one of the configured keys, or else by its IP address.
+// Unknown keys are ignored, so that a client cannot get a fresh bucket by sending a new key with each request.
+func (rl *rateLimiter) clientKey(req *http.Request) string {
+	if rl.keyHeader != "" {
+		if key := req.Header.Get(rl.keyHeader); key != "" && rl.apiKeys[key] {
+			return "key:" + key
+		}
+	}
+	return "ip:" + rl.clientIP(req)
+}
+
+// clientIP returns the address that the outermost trusted proxy received the request from. Each proxy appends the
+// address it received the request from to X-Forwarded-For, so that address is counted back from the right of the
+// header by the number of trusted proxies, as anything further left was sent by the client and cannot be trusted.
+// Without trusted proxies, it is the address the request came from.
+func (rl *rateLimiter) clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	if rl.trustedProxies <= 0 {
+		return host
+	}
+
+	var forwardedFor []string
+	for _, header := range req.Header.Values("X-Forwarded-For") {
+		for _, address := range strings.Split(header, ",") {
+			if address = strings.TrimSpace(address); address != "" {
+				forwardedFor = append(forwardedFor, address)
+			}
+		}
+	}
+	if len(forwardedFor) == 0 {
+		return host
+	}
+
+	return forwardedFor[max(len(forwardedFor)-rl.trustedProxies, 0)]
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
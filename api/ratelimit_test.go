@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-search-api/metrics"
+	"github.com/ONSdigital/dp-search-api/ratelimit"
+	"github.com/gorilla/mux"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+// failingStore fails to take a token from every bucket
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store unavailable")
+}
+
+func newRateLimitedSearchAPI(store ratelimit.Store, registry *metrics.Registry) *SearchAPI {
+	router := mux.NewRouter()
+	ok := func(w http.ResponseWriter, req *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/search", ok)
+	router.HandleFunc("/search/releases", ok)
+
+	searchAPI := &SearchAPI{Router: router}
+	return searchAPI.WithRateLimits(store, ratelimit.Limits{"/search": {Rate: 1, Burst: 2}}, RateLimitClients{
+		KeyHeader:      "X-API-Key",
+		APIKeys:        []string{"abc"},
+		TrustedProxies: 1,
+	}, registry)
+}
+
+func rateLimitedRequest(searchAPI *SearchAPI, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "http://localhost:23900"+path, http.NoBody)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp := httptest.NewRecorder()
+	searchAPI.Router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestRateLimits(t *testing.T) {
+	c.Convey("Given a Search API with a rate limited route", t, func() {
+		registry := metrics.NewRegistry()
+		searchAPI := newRateLimitedSearchAPI(ratelimit.NewMemoryStore(), registry)
+		counter := registry.NewCounter("search_api_rate_limited_requests_total", "")
+
+		c.Convey("When a client makes more requests than the burst", func() {
+			first := rateLimitedRequest(searchAPI, "/search", nil)
+			rateLimitedRequest(searchAPI, "/search", nil)
+			limited := rateLimitedRequest(searchAPI, "/search", nil)
+
+			c.Convey("Then the requests within the burst are allowed, with the client's limit", func() {
+				c.So(first.Code, c.ShouldEqual, http.StatusOK)
+				c.So(first.Header().Get("X-RateLimit-Limit"), c.ShouldEqual, "2")
+				c.So(first.Header().Get("X-RateLimit-Remaining"), c.ShouldEqual, "1")
+				c.So(first.Header().Get("X-RateLimit-Reset"), c.ShouldEqual, "1")
+			})
+
+			c.Convey("And further requests are rejected with 429 Too Many Requests", func() {
+				c.So(limited.Code, c.ShouldEqual, http.StatusTooManyRequests)
+				c.So(limited.Header().Get("Retry-After"), c.ShouldEqual, "1")
+				c.So(limited.Header().Get("X-RateLimit-Remaining"), c.ShouldEqual, "0")
+				c.So(limited.Body.String(), c.ShouldEqual, `{"errors":[{"code":"rate_limited","description":"rate limit exceeded, retry later"}]}`)
+			})
+
+			c.Convey("And the requests are counted", func() {
+				c.So(counter.Value("/search", "allowed"), c.ShouldEqual, 2)
+				c.So(counter.Value("/search", "limited"), c.ShouldEqual, 1)
+			})
+
+			c.Convey("And requests from other addresses or with a configured API key are limited separately", func() {
+				resp := rateLimitedRequest(searchAPI, "/search", map[string]string{"X-Forwarded-For": "10.0.0.1, 198.51.100.7"})
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+
+				resp = rateLimitedRequest(searchAPI, "/search", map[string]string{"X-API-Key": "abc"})
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+			})
+
+			c.Convey("And requests with an unknown API key are limited by address", func() {
+				resp := rateLimitedRequest(searchAPI, "/search", map[string]string{"X-API-Key": "made-up"})
+				c.So(resp.Code, c.ShouldEqual, http.StatusTooManyRequests)
+			})
+		})
+
+		c.Convey("When a client behind the trusted proxy sends a different X-Forwarded-For address with every request", func() {
+			var codes []int
+			for _, spoofed := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+				resp := rateLimitedRequest(searchAPI, "/search", map[string]string{"X-Forwarded-For": spoofed + ", 198.51.100.7"})
+				codes = append(codes, resp.Code)
+			}
+
+			c.Convey("Then the requests are limited by the address the trusted proxy added", func() {
+				c.So(codes, c.ShouldResemble, []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests})
+			})
+
+			c.Convey("And routes without a limit are not limited", func() {
+				resp := rateLimitedRequest(searchAPI, "/search/releases", nil)
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("X-RateLimit-Limit"), c.ShouldBeEmpty)
+			})
+		})
+	})
+
+	c.Convey("Given a Search API whose rate limit store is failing", t, func() {
+		searchAPI := newRateLimitedSearchAPI(failingStore{}, nil)
+
+		c.Convey("When a request is made to a rate limited route", func() {
+			resp := rateLimitedRequest(searchAPI, "/search", nil)
+
+			c.Convey("Then the request is allowed", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("X-RateLimit-Limit"), c.ShouldBeEmpty)
+			})
+		})
+	})
+}
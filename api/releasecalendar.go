@@ -0,0 +1,158 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// createReleaseCalendarRequest reads the parameters from the request and generates the corresponding
+// ReleaseCalendarRequest, or else returns an error for every invalid parameter
+func createReleaseCalendarRequest(req *http.Request, validator QueryParamValidator) (*query.ReleaseCalendarRequest, paramErrors) {
+	ctx := req.Context()
+	params := req.URL.Query()
+	var errs paramErrors
+
+	from, fromErr := parseCalendarDate(req, params, validator, ParamFrom, &errs)
+	to, toErr := parseCalendarDate(req, params, validator, ParamTo, &errs)
+
+	if fromErr == nil && toErr == nil {
+		if fromAfterTo(from, to) {
+			log.Warn(ctx, "from after to", log.Data{ParamFrom: params.Get(ParamFrom), ParamTo: params.Get(ParamTo)})
+			errs.addCode(apierrors.CodeConflictingParameters, ParamFrom, params.Get(ParamFrom), "invalid dates - 'from' after 'to'")
+		} else if time.Time(to).Sub(time.Time(from)) > query.MaxCalendarDays*24*time.Hour {
+			log.Warn(ctx, "calendar too long", log.Data{ParamFrom: params.Get(ParamFrom), ParamTo: params.Get(ParamTo)})
+			errs.addCode(apierrors.CodeInvalidParameter, ParamTo, params.Get(ParamTo),
+				fmt.Sprintf("invalid dates - calendar cannot span more than %d days", query.MaxCalendarDays))
+		}
+	}
+
+	intervalParam := paramGet(params, ParamInterval, "day")
+	interval, intervalErr := validator.Validate(ctx, ParamInterval, intervalParam)
+	if intervalErr != nil {
+		log.Warn(ctx, intervalErr.Error(), log.Data{"param": ParamInterval, "value": intervalParam})
+		errs.addCode(apierrors.CodeInvalidParameter, ParamInterval, intervalParam, "Invalid interval parameter, must be one of: day, week")
+	}
+
+	language, languageErr := parseLanguage(ctx, params, validator)
+	if languageErr != nil {
+		errs.addCode(apierrors.CodeInvalidParameter, ParamLang, params.Get(ParamLang), "Invalid lang parameter")
+	}
+
+	topics, topicErr := parseTopics(ctx, params)
+	if topicErr != nil {
+		errs.add(ParamTopics, params.Get(ParamTopics), topicErr)
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return &query.ReleaseCalendarRequest{
+		ReleasedAfter:  from,
+		ReleasedBefore: to,
+		Interval:       interval.(string),
+		Census:         paramGetBool(params, ParamCensus, false),
+		Language:       language,
+		Topic:          topics,
+	}, nil
+}
+
+// parseCalendarDate returns the date of a required date parameter, adding an error if it is missing or invalid
+func parseCalendarDate(req *http.Request, params url.Values, validator QueryParamValidator, param string, errs *paramErrors) (query.Date, error) {
+	ctx := req.Context()
+
+	dateParam := params.Get(param)
+	if dateParam == "" {
+		log.Warn(ctx, "release calendar request without a date", log.Data{"param": param})
+		errs.addCode(apierrors.CodeMissingParameter, param, "", param+" parameter is required")
+		return query.Date{}, errors.New("missing date")
+	}
+
+	date, err := validator.Validate(ctx, "date", dateParam)
+	if err != nil {
+		log.Warn(ctx, err.Error(), log.Data{"param": param, "value": dateParam})
+		errs.addCode(apierrors.CodeInvalidParameter, param, dateParam, fmt.Sprintf("Invalid %s parameter", param))
+		return query.Date{}, err
+	}
+	return date.(query.Date), nil
+}
+
+// SearchReleasesCalendarHandlerFunc returns a http handler function that returns the number of releases of each type
+// and subtype released on each day or week of a calendar, for drawing a calendar grid. Transformed responses are
+// cached if the client list has a cache.
+func SearchReleasesCalendarHandlerFunc(validator QueryParamValidator, builder ReleaseQueryBuilder, clList *ClientList, transformer ReleaseResponseTransformer) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		calendarReq, errs := createReleaseCalendarRequest(req, validator)
+		if len(errs) > 0 {
+			writeErrors(w, http.StatusBadRequest, errs)
+			return
+		}
+
+		var key string
+		if clList.ResponseCache != nil {
+			var err error
+			if key, err = releaseCalendarCacheKey(calendarReq); err != nil {
+				log.Warn(ctx, "release calendar response will not be cached", log.Data{"error": err.Error()})
+			}
+		}
+
+		cached, ok := getCachedResponse(ctx, clList.ResponseCache, key)
+		if key != "" {
+			clList.Metrics.recordCacheLookup(releasesCalendarRoute, ok)
+		}
+
+		var responseData []byte
+		if ok {
+			responseData = cached.Body
+		} else {
+			searches, err := builder.BuildCalendarQuery(ctx, *calendarReq)
+			if err != nil {
+				log.Error(ctx, "creation of release calendar query failed", err)
+				writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to create release calendar query")
+				return
+			}
+
+			responseData, err = clList.DpESClient.MultiSearch(ctx, searches, nil)
+			if err != nil {
+				log.Error(ctx, "elasticsearch query failed", err)
+				writeSearchFailed(w, clList.DpESClient, "Failed to run release calendar query")
+				return
+			}
+
+			if !json.Valid(responseData) {
+				log.Error(ctx, "elastic search returned invalid JSON for release calendar query", errors.New("elastic search returned invalid JSON for release calendar query"))
+				writeError(w, http.StatusInternalServerError, apierrors.CodeSearchFailed, "Failed to process release calendar query")
+				return
+			}
+
+			responseData, err = transformer.TransformCalendarCountsResponse(ctx, responseData, *calendarReq)
+			if err != nil {
+				log.Error(ctx, "transformation of response data failed", err)
+				writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to transform release calendar result")
+				return
+			}
+
+			cached = setCachedResponse(ctx, clList.ResponseCache, key, responseData)
+		}
+
+		if writeNotModified(w, req, responseETag(responseData, cached), cached) {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		if _, err := w.Write(responseData); err != nil {
+			log.Error(ctx, "writing response failed", err)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to write http response")
+			return
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-search-api/query"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSearchReleasesCalendarHandlerFunc(t *testing.T) {
+	newCalendarMocks := func(esErr error) (*ReleaseQueryBuilderMock, *DpElasticSearcherMock, *ReleaseResponseTransformerMock) {
+		builder := &ReleaseQueryBuilderMock{
+			BuildCalendarQueryFunc: func(ctx context.Context, req query.ReleaseCalendarRequest) ([]client.Search, error) {
+				return []client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"size":0}`)}}, nil
+			},
+		}
+		transformer := &ReleaseResponseTransformerMock{
+			TransformCalendarCountsResponseFunc: func(ctx context.Context, responseData []byte, req query.ReleaseCalendarRequest) ([]byte, error) {
+				return []byte(`{"took":4,"interval":"day","buckets":[]}`), nil
+			},
+		}
+		return builder, newDpElasticSearcherMock([]byte(`{"responses":[{}]}`), esErr), transformer
+	}
+
+	c.Convey("Given a release calendar counts endpoint caching responses", t, func() {
+		builder, esMock, transformer := newCalendarMocks(nil)
+		clList := &ClientList{DpESClient: esMock, ResponseCache: NewLRUCache(10, time.Minute)}
+		handler := SearchReleasesCalendarHandlerFunc(query.NewReleaseQueryParamValidator(), builder, clList, transformer)
+
+		get := func(url, etag string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, url, http.NoBody)
+			if etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			return resp
+		}
+
+		c.Convey("When the counts of a month of census releases are requested", func() {
+			resp := get("http://localhost:23900/search/releases/calendar?from=2024-01-01&to=2024-01-31&census=true&topics=1234", "")
+
+			c.Convey("Then releases are counted daily over the month", func() {
+				c.So(builder.BuildCalendarQueryCalls(), c.ShouldHaveLength, 1)
+				calendarReq := builder.BuildCalendarQueryCalls()[0].Req
+				c.So(calendarReq.ReleasedAfter, c.ShouldEqual, query.MustParseDate("2024-01-01"))
+				c.So(calendarReq.ReleasedBefore, c.ShouldEqual, query.MustParseDate("2024-01-31"))
+				c.So(calendarReq.Interval, c.ShouldEqual, "day")
+				c.So(calendarReq.Census, c.ShouldBeTrue)
+				c.So(calendarReq.Topic, c.ShouldResemble, []string{"1234"})
+				c.So(transformer.TransformCalendarCountsResponseCalls()[0].Req, c.ShouldResemble, calendarReq)
+			})
+
+			c.Convey("Then the transformed counts are returned, tagged", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("Content-Type"), c.ShouldEqual, "application/json;charset=utf-8")
+				c.So(resp.Body.String(), c.ShouldEqual, `{"took":4,"interval":"day","buckets":[]}`)
+				c.So(resp.Header().Get("ETag"), c.ShouldNotBeEmpty)
+			})
+
+			c.Convey("And they are requested again with the entity tag of the response", func() {
+				notModified := get("http://localhost:23900/search/releases/calendar?from=2024-01-01&to=2024-01-31&census=true&topics=1234", resp.Header().Get("ETag"))
+
+				c.Convey("Then 304 Not Modified is returned from the cache", func() {
+					c.So(notModified.Code, c.ShouldEqual, http.StatusNotModified)
+					c.So(notModified.Body.Len(), c.ShouldEqual, 0)
+					c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 1)
+				})
+			})
+		})
+
+		c.Convey("When weekly counts are requested", func() {
+			resp := get("http://localhost:23900/search/releases/calendar?from=2024-01-01&to=2024-12-31&interval=week", "")
+
+			c.Convey("Then releases are counted weekly", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(builder.BuildCalendarQueryCalls()[0].Req.Interval, c.ShouldEqual, "week")
+			})
+		})
+
+		c.Convey("When counts are requested without dates, and for an invalid interval", func() {
+			resp := get("http://localhost:23900/search/releases/calendar?interval=month", "")
+
+			c.Convey("Then 400 Bad Request is returned with an error for each parameter", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "from parameter is required")
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "to parameter is required")
+				c.So(resp.Body.String(), c.ShouldContainSubstring, `"code":"missing_parameter"`)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "Invalid interval parameter, must be one of: day, week")
+				c.So(builder.BuildCalendarQueryCalls(), c.ShouldBeEmpty)
+			})
+		})
+
+		c.Convey("When counts are requested from after to", func() {
+			resp := get("http://localhost:23900/search/releases/calendar?from=2024-02-01&to=2024-01-01", "")
+
+			c.Convey("Then 400 Bad Request is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, `"code":"conflicting_parameters"`)
+			})
+		})
+
+		c.Convey("When counts are requested over dates 366 days apart", func() {
+			resp := get("http://localhost:23900/search/releases/calendar?from=2024-01-01&to=2025-01-01", "")
+
+			c.Convey("Then the releases are counted", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(builder.BuildCalendarQueryCalls(), c.ShouldHaveLength, 1)
+			})
+		})
+
+		c.Convey("When counts are requested over dates more than 366 days apart", func() {
+			resp := get("http://localhost:23900/search/releases/calendar?from=2024-01-01&to=2025-01-02", "")
+
+			c.Convey("Then 400 Bad Request is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "calendar cannot span more than 366 days")
+			})
+		})
+	})
+
+	c.Convey("Given a release calendar counts endpoint whose search fails", t, func() {
+		builder, esMock, transformer := newCalendarMocks(context.DeadlineExceeded)
+		handler := SearchReleasesCalendarHandlerFunc(query.NewReleaseQueryParamValidator(), builder, &ClientList{DpESClient: esMock}, transformer)
+
+		c.Convey("When counts are requested", func() {
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/releases/calendar?from=2024-01-01&to=2024-01-31", http.NoBody))
+
+			c.Convey("Then the search is reported as failed", func() {
+				c.So(resp.Code, c.ShouldBeGreaterThanOrEqualTo, http.StatusInternalServerError)
+				c.So(transformer.TransformCalendarCountsResponseCalls(), c.ShouldBeEmpty)
+			})
+		})
+	})
+}
@@ -1,75 +1,115 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/config"
+	"github.com/ONSdigital/dp-search-api/icalendar"
 	"github.com/ONSdigital/dp-search-api/query"
 	"github.com/ONSdigital/log.go/v2/log"
 )
 
+// CreateReleaseRequest reads the parameters from the request and generates the corresponding ReleaseSearchRequest.
+// If any validation fails, an error for every invalid parameter is written and nil is returned.
 func CreateReleaseRequest(w http.ResponseWriter, req *http.Request, validator QueryParamValidator) (string, *query.ReleaseSearchRequest) {
+	queryString, searchReq, errs := createReleaseRequest(req, validator, false)
+	if len(errs) > 0 {
+		writeErrors(w, http.StatusBadRequest, errs)
+		return "", nil
+	}
+	return queryString, searchReq
+}
+
+// createReleaseRequest reads the parameters from the request and generates the corresponding ReleaseSearchRequest,
+// or else returns an error for every invalid parameter. Calendars return every release up to a higher limit by
+// default, as calendar apps cannot page through releases.
+func createReleaseRequest(req *http.Request, validator QueryParamValidator, calendar bool) (string, *query.ReleaseSearchRequest, paramErrors) {
 	ctx := req.Context()
 	params := req.URL.Query()
+	var errs paramErrors
 
 	queryString := params.Get("query")
 	term, template := query.ParseQuery(queryString)
 
-	limitParam := paramGet(params, ParamLimit, "10")
-	limit, err := validator.Validate(ctx, ParamLimit, limitParam)
-	if err != nil {
-		log.Warn(ctx, err.Error(), log.Data{"param": ParamLimit, "value": limitParam})
-		http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
-		return "", nil
+	limitValidator, defaultLimit := ParamLimit, "10"
+	if calendar {
+		limitValidator, defaultLimit = "calendar-limit", strconv.Itoa(query.MaxCalendarReleases)
+	}
+	limitParam := paramGet(params, ParamLimit, defaultLimit)
+	limit, limitErr := validator.Validate(ctx, limitValidator, limitParam)
+	if limitErr != nil {
+		log.Warn(ctx, limitErr.Error(), log.Data{"param": ParamLimit, "value": limitParam})
+		errs.addCode(apierrors.CodeInvalidParameter, ParamLimit, limitParam, "Invalid limit parameter")
 	}
 
 	offsetParam := paramGet(params, ParamOffset, "0")
-	offset, err := validator.Validate(ctx, ParamOffset, offsetParam)
-	if err != nil {
-		log.Warn(ctx, err.Error(), log.Data{"param": ParamOffset, "value": offsetParam})
-		http.Error(w, "Invalid offset parameter", http.StatusBadRequest)
-		return "", nil
+	offset, offsetErr := validator.Validate(ctx, ParamOffset, offsetParam)
+	if offsetErr != nil {
+		log.Warn(ctx, offsetErr.Error(), log.Data{"param": ParamOffset, "value": offsetParam})
+		errs.addCode(apierrors.CodeInvalidParameter, ParamOffset, offsetParam, "Invalid offset parameter")
 	}
 
 	sortParam := paramGet(params, ParamSort, query.RelDateAsc.String())
-	sort, err := validator.Validate(ctx, ParamSort, sortParam)
-	if err != nil {
-		log.Warn(ctx, err.Error(), log.Data{"param": ParamSort, "value": sortParam})
-		http.Error(w, "Invalid sort parameter", http.StatusBadRequest)
-		return "", nil
+	sort, sortErr := validator.Validate(ctx, ParamSort, sortParam)
+	if sortErr != nil {
+		log.Warn(ctx, sortErr.Error(), log.Data{"param": ParamSort, "value": sortParam})
+		errs.addCode(apierrors.CodeInvalidParameter, ParamSort, sortParam, "Invalid sort parameter")
 	}
 
 	fromDateParam := paramGet(params, "fromDate", "")
-	fromDate, err := validator.Validate(ctx, "date", fromDateParam)
-	if err != nil {
-		log.Warn(ctx, err.Error(), log.Data{"param": "fromDate", "value": fromDateParam})
-		http.Error(w, "Invalid fromDate parameter", http.StatusBadRequest)
-		return "", nil
+	fromDate, fromDateErr := validator.Validate(ctx, "date", fromDateParam)
+	if fromDateErr != nil {
+		log.Warn(ctx, fromDateErr.Error(), log.Data{"param": "fromDate", "value": fromDateParam})
+		errs.addCode(apierrors.CodeInvalidParameter, "fromDate", fromDateParam, "Invalid fromDate parameter")
 	}
 
 	toDateParam := paramGet(params, "toDate", "")
-	toDate, err := validator.Validate(ctx, "date", toDateParam)
-	if err != nil {
-		log.Warn(ctx, err.Error(), log.Data{"param": "toDate", "value": toDateParam})
-		http.Error(w, "Invalid toDate parameter", http.StatusBadRequest)
-		return "", nil
+	toDate, toDateErr := validator.Validate(ctx, "date", toDateParam)
+	if toDateErr != nil {
+		log.Warn(ctx, toDateErr.Error(), log.Data{"param": "toDate", "value": toDateParam})
+		errs.addCode(apierrors.CodeInvalidParameter, "toDate", toDateParam, "Invalid toDate parameter")
 	}
 
-	if fromAfterTo(fromDate.(query.Date), toDate.(query.Date)) {
+	if fromDateErr == nil && toDateErr == nil && fromAfterTo(fromDate.(query.Date), toDate.(query.Date)) {
 		log.Warn(ctx, "fromDate after toDate", log.Data{"fromDate": fromDateParam, "toDate": toDateParam})
-		http.Error(w, "invalid dates - 'from' after 'to'", http.StatusBadRequest)
-		return "", nil
+		errs.addCode(apierrors.CodeConflictingParameters, "fromDate", fromDateParam, "invalid dates - 'from' after 'to'")
+	}
+
+	rescheduledSinceParam := paramGet(params, ParamRescheduledSince, "")
+	rescheduledSince, rescheduledSinceErr := validator.Validate(ctx, "date", rescheduledSinceParam)
+	if rescheduledSinceErr != nil {
+		log.Warn(ctx, rescheduledSinceErr.Error(), log.Data{"param": ParamRescheduledSince, "value": rescheduledSinceParam})
+		errs.addCode(apierrors.CodeInvalidParameter, ParamRescheduledSince, rescheduledSinceParam, "Invalid rescheduled_since parameter")
 	}
 
 	relTypeParam := paramGet(params, "release-type", query.Published.String())
-	relType, err := validator.Validate(ctx, "release-type", relTypeParam)
-	if err != nil {
-		log.Warn(ctx, err.Error(), log.Data{"param": "release-type", "value": relTypeParam})
-		http.Error(w, "Invalid release-type parameter", http.StatusBadRequest)
-		return "", nil
+	relType, relTypeErr := validator.Validate(ctx, "release-type", relTypeParam)
+	if relTypeErr != nil {
+		log.Warn(ctx, relTypeErr.Error(), log.Data{"param": "release-type", "value": relTypeParam})
+		errs.addCode(apierrors.CodeInvalidParameter, "release-type", relTypeParam, "Invalid release-type parameter")
 	}
+
+	language, languageErr := parseLanguage(ctx, params, validator)
+	if languageErr != nil {
+		errs.addCode(apierrors.CodeInvalidParameter, ParamLang, params.Get(ParamLang), "Invalid lang parameter")
+	}
+
+	topics, topicErr := parseTopics(ctx, params)
+	if topicErr != nil {
+		errs.add(ParamTopics, params.Get(ParamTopics), topicErr)
+	}
+
+	if len(errs) > 0 {
+		return "", nil, errs
+	}
+
 	provisional := paramGetBool(params, ParamSubtypeProvisional, false)
 	confirmed := paramGetBool(params, ParamSubtypeConfirmed, false)
 	postponed := paramGetBool(params, ParamSubtypePostponed, false)
@@ -77,74 +117,211 @@ func CreateReleaseRequest(w http.ResponseWriter, req *http.Request, validator Qu
 	census := paramGetBool(params, ParamCensus, false)
 
 	return queryString, &query.ReleaseSearchRequest{
-		Term:           term,
-		Template:       template,
-		From:           offset.(int),
-		Size:           limit.(int),
-		SortBy:         sort.(query.Sort),
-		ReleasedAfter:  fromDate.(query.Date),
-		ReleasedBefore: toDate.(query.Date),
-		Type:           relType.(query.ReleaseType),
-		Provisional:    provisional,
-		Confirmed:      confirmed,
-		Postponed:      postponed,
-		Census:         census,
-		Highlight:      highlight,
-	}
+		Term:             term,
+		Template:         template,
+		From:             offset.(int),
+		Size:             limit.(int),
+		SortBy:           sort.(query.Sort),
+		ReleasedAfter:    fromDate.(query.Date),
+		ReleasedBefore:   toDate.(query.Date),
+		Type:             relType.(query.ReleaseType),
+		Provisional:      provisional,
+		Confirmed:        confirmed,
+		Postponed:        postponed,
+		Census:           census,
+		Highlight:        highlight,
+		Language:         language,
+		Topic:            topics,
+		RescheduledSince: rescheduledSince.(query.Date),
+	}, nil
 }
 
 // SearchReleasesHandlerFunc returns a http handler function handling release calendar search api requests.
-func SearchReleasesHandlerFunc(validator QueryParamValidator, builder ReleaseQueryBuilder, searcher DpElasticSearcher, transformer ReleaseResponseTransformer) http.HandlerFunc {
+// Releases are returned as JSON or, if requested, as an iCalendar. Transformed responses are cached if the client
+// list has a cache, and searches are recorded if it has metrics.
+func SearchReleasesHandlerFunc(validator QueryParamValidator, builder ReleaseQueryBuilder, cfg *config.Config, clList *ClientList, transformer ReleaseResponseTransformer) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
-		ctx := req.Context()
 		params := req.URL.Query()
 
-		queryString, searchReq := CreateReleaseRequest(w, req, validator)
-		if searchReq == nil {
-			return // error already handled
-		}
+		raw := paramGetBool(params, "raw", false)
+		format, errs := releasesFormat(req, raw)
 
-		searches, err := builder.BuildSearchQuery(ctx, searchReq)
-		if err != nil {
-			log.Error(ctx, "creation of search release query failed", err, log.Data{
-				ParamQ:      queryString,
-				ParamSort:   searchReq.SortBy,
-				ParamLimit:  searchReq.Size,
-				ParamOffset: searchReq.From,
-			})
-			http.Error(w, "Failed to create search release query", http.StatusInternalServerError)
+		queryString, searchReq, searchErrs := createReleaseRequest(req, validator, format == releasesFormatICS)
+		if errs = append(errs, searchErrs...); len(errs) > 0 {
+			writeErrors(w, http.StatusBadRequest, errs)
 			return
 		}
+		if format == releasesFormatICS {
+			searchReq.Highlight = false
+		}
 
-		responseData, err := searcher.MultiSearch(ctx, searches, nil)
-		if err != nil {
-			log.Error(ctx, "elasticsearch query failed", err)
-			http.Error(w, "Failed to run search query", http.StatusInternalServerError)
-			return
+		responseData, etag, cached := searchReleases(w, req, builder, clList, transformer, queryString, searchReq, raw)
+		if responseData == nil {
+			return // error already handled
 		}
+		writeReleasesResponse(w, req, cfg, transformer, format, responseData, etag, cached)
+	}
+}
 
-		if !json.Valid(responseData) {
-			log.Error(ctx, "elastic search returned invalid JSON for search release query", errors.New("elastic search returned invalid JSON for search release query"))
-			http.Error(w, "Failed to process search release query", http.StatusInternalServerError)
-			return
+// searchReleases returns the transformed response to a release calendar search, from the cache if it has been cached,
+// along with its entity tag. Raw responses are returned untransformed and without an entity tag. If the search fails,
+// an error is written and nil is returned.
+func searchReleases(w http.ResponseWriter, req *http.Request, builder ReleaseQueryBuilder, clList *ClientList, transformer ReleaseResponseTransformer,
+	queryString string, searchReq *query.ReleaseSearchRequest, raw bool) (responseData []byte, etag string, cached *CachedResponse) {
+	ctx := req.Context()
+
+	var cacheKey string
+	if !raw && clList.ResponseCache != nil {
+		var err error
+		if cacheKey, err = releaseCacheKey(searchReq); err != nil {
+			log.Warn(ctx, "release search response will not be cached", log.Data{"error": err.Error()})
+		}
+	}
+
+	cached, ok := getCachedResponse(ctx, clList.ResponseCache, cacheKey)
+	if cacheKey != "" {
+		clList.Metrics.recordCacheLookup(releasesRoute, ok)
+	}
+	if ok {
+		recordReleaseResults(ctx, clList.Metrics, cached.Body, false)
+		return cached.Body, cached.ETag, cached
+	}
+
+	searches, err := builder.BuildSearchQuery(ctx, searchReq)
+	if err != nil {
+		log.Error(ctx, "creation of search release query failed", err, log.Data{
+			ParamQ:      queryString,
+			ParamSort:   searchReq.SortBy,
+			ParamLimit:  searchReq.Size,
+			ParamOffset: searchReq.From,
+		})
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to create search release query")
+		return nil, "", nil
+	}
+
+	responseData, err = clList.DpESClient.MultiSearch(ctx, searches, nil)
+	if err != nil {
+		log.Error(ctx, "elasticsearch query failed", err)
+		writeSearchFailed(w, clList.DpESClient, "Failed to run search query")
+		return nil, "", nil
+	}
+
+	if !json.Valid(responseData) {
+		log.Error(ctx, "elastic search returned invalid JSON for search release query", errors.New("elastic search returned invalid JSON for search release query"))
+		writeError(w, http.StatusInternalServerError, apierrors.CodeSearchFailed, "Failed to process search release query")
+		return nil, "", nil
+	}
+
+	if raw {
+		return responseData, "", nil
+	}
+
+	responseData, err = transformer.TransformSearchResponse(ctx, responseData, *searchReq, searchReq.Highlight)
+	if err != nil {
+		log.Error(ctx, "transformation of response data failed", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to transform search result")
+		return nil, "", nil
+	}
+
+	recordReleaseResults(ctx, clList.Metrics, responseData, true)
+	cached = setCachedResponse(ctx, clList.ResponseCache, cacheKey, responseData)
+	return responseData, responseETag(responseData, cached), cached
+}
+
+const (
+	releasesFormatJSON = "json"
+	releasesFormatICS  = "ics"
+)
+
+// releasesFormat returns the format that releases are requested in: the format parameter if it is set, or else an
+// iCalendar if the Accept header asks for one, or else JSON
+func releasesFormat(req *http.Request, raw bool) (string, paramErrors) {
+	var errs paramErrors
+
+	format := req.URL.Query().Get(ParamFormat)
+	switch format {
+	case releasesFormatJSON, releasesFormatICS:
+	case "":
+		format = releasesFormatJSON
+		if acceptsMediaType(req.Header.Get("Accept"), icalendar.ContentType) {
+			format = releasesFormatICS
 		}
+	default:
+		log.Warn(req.Context(), "invalid releases format", log.Data{"param": ParamFormat, "value": format})
+		errs.addCode(apierrors.CodeInvalidParameter, ParamFormat, format, "invalid format parameter, must be one of: json, ics")
+		return format, errs
+	}
 
-		if !paramGetBool(params, "raw", false) {
-			responseData, err = transformer.TransformSearchResponse(ctx, responseData, *searchReq, searchReq.Highlight)
-			if err != nil {
-				log.Error(ctx, "transformation of response data failed", err)
-				http.Error(w, "Failed to transform search result", http.StatusInternalServerError)
-				return
-			}
+	if raw && format != releasesFormatJSON {
+		errs.addCode(apierrors.CodeConflictingParameters, ParamFormat, format, "raw responses can only be returned as json")
+	}
+	return format, errs
+}
+
+// acceptsMediaType returns whether an Accept header lists the media type, ignoring wildcards so that clients only get
+// the media type if they ask for it by name
+func acceptsMediaType(accept, mediaType string) bool {
+	for _, accepted := range strings.Split(accept, ",") {
+		accepted, _, _ = strings.Cut(accepted, ";")
+		if strings.EqualFold(strings.TrimSpace(accepted), mediaType) {
+			return true
 		}
+	}
+	return false
+}
 
-		w.Header().Set("Content-Type", "application/json;charset=utf-8")
-		_, err = w.Write(responseData)
-		if err != nil {
-			log.Error(ctx, "writing response failed", err)
-			http.Error(w, "Failed to write http response", http.StatusInternalServerError)
+// writeReleasesResponse writes a release calendar search response in the requested format, or 304 Not Modified if the
+// request's If-None-Match header matches its entity tag. Raw responses are written without an entity tag, as are
+// iCalendars, as they are stamped with the time they are created.
+func writeReleasesResponse(w http.ResponseWriter, req *http.Request, cfg *config.Config, transformer ReleaseResponseTransformer,
+	format string, responseData []byte, etag string, cached *CachedResponse) {
+	ctx := req.Context()
+	contentType := "application/json;charset=utf-8"
+
+	if format == releasesFormatICS {
+		var err error
+		if responseData, err = transformer.TransformCalendarResponse(ctx, responseData, cfg.WebsiteURL); err != nil {
+			log.Error(ctx, "transformation of response data to a calendar failed", err)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to transform search result")
 			return
 		}
+		contentType = icalendar.ContentType + ";charset=utf-8"
+		etag = ""
+	}
+
+	w.Header().Add("Vary", "Accept")
+	if etag != "" && writeNotModified(w, req, etag, cached) {
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if _, err := w.Write(responseData); err != nil {
+		log.Error(ctx, "writing response failed", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to write http response")
+		return
+	}
+}
+
+// recordReleaseResults records a release calendar search answered with the transformed response, along with the time
+// elasticsearch took to run it if the response is not from the cache
+func recordReleaseResults(ctx context.Context, metrics *Metrics, responseData []byte, searched bool) {
+	if metrics == nil {
+		return
+	}
+
+	var response struct {
+		Took      int `json:"took"`
+		Breakdown struct {
+			Total int `json:"total"`
+		} `json:"breakdown"`
+	}
+	if err := json.Unmarshal(responseData, &response); err != nil {
+		log.Warn(ctx, "release search response not recorded in metrics", log.Data{"error": err.Error()})
+		return
+	}
+
+	metrics.recordResults(releasesRoute, response.Breakdown.Total)
+	if searched {
+		metrics.recordTook(releasesRoute, response.Took)
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-search-api/config"
 	"github.com/ONSdigital/dp-search-api/query"
 	"github.com/smartystreets/goconvey/convey"
 )
@@ -35,7 +36,7 @@ func TestSearchReleasesHandlerFunc(t *testing.T) {
 		},
 	}
 
-	searchHandler := SearchReleasesHandlerFunc(validator, builder, searcher, transformer)
+	searchHandler := SearchReleasesHandlerFunc(validator, builder, &config.Config{}, &ClientList{DpESClient: searcher}, transformer)
 
 	convey.Convey("Should return BadRequest for invalid limit parameter", t, func() {
 		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?limit=test", http.NoBody)
@@ -67,6 +68,69 @@ func TestSearchReleasesHandlerFunc(t *testing.T) {
 		convey.So(resp.Body.String(), convey.ShouldContainSubstring, "Invalid sort parameter")
 	})
 
+	convey.Convey("Should return BadRequest for invalid lang parameter", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?lang=fr", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusBadRequest)
+		convey.So(resp.Body.String(), convey.ShouldContainSubstring, "Invalid lang parameter")
+	})
+
+	convey.Convey("Should pass the requested language on to the query builder", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?query=test&lang=cy", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusOK)
+		calls := builder.BuildSearchQueryCalls()
+		convey.So(calls[len(calls)-1].Request.(*query.ReleaseSearchRequest).Language, convey.ShouldEqual, "cy")
+	})
+
+	convey.Convey("Should return BadRequest for invalid topics parameter", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?topics=1234,12", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusBadRequest)
+		convey.So(resp.Body.String(), convey.ShouldContainSubstring, "invalid topics: 12")
+	})
+
+	convey.Convey("Should pass the requested topics on to the query builder", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?topics=1234,5678", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusOK)
+		calls := builder.BuildSearchQueryCalls()
+		convey.So(calls[len(calls)-1].Request.(*query.ReleaseSearchRequest).Topic, convey.ShouldResemble, []string{"1234", "5678"})
+	})
+
+	convey.Convey("Should return BadRequest for invalid rescheduled_since parameter", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?rescheduled_since=yesterday", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusBadRequest)
+		convey.So(resp.Body.String(), convey.ShouldContainSubstring, "Invalid rescheduled_since parameter")
+	})
+
+	convey.Convey("Should pass the rescheduled_since date on to the query builder", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?release-type=type-upcoming&rescheduled_since=2024-01-01", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusOK)
+		calls := builder.BuildSearchQueryCalls()
+		convey.So(calls[len(calls)-1].Request.(*query.ReleaseSearchRequest).RescheduledSince, convey.ShouldEqual, query.MustParseDate("2024-01-01"))
+	})
+
 	convey.Convey("Should return valid response for correct parameters", t, func() {
 		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?query=test", http.NoBody)
 		resp := httptest.NewRecorder()
@@ -77,3 +141,108 @@ func TestSearchReleasesHandlerFunc(t *testing.T) {
 		convey.So(resp.Body.String(), convey.ShouldContainSubstring, `{"dummy":"response"}`)
 	})
 }
+
+func TestSearchReleasesHandlerFuncCalendar(t *testing.T) {
+	builder := &ReleaseQueryBuilderMock{
+		BuildSearchQueryFunc: func(ctx context.Context, request interface{}) ([]client.Search, error) {
+			return []client.Search{{Query: []byte(`{"query": "test"}`)}}, nil
+		},
+	}
+	searcher := &DpElasticSearcherMock{
+		MultiSearchFunc: func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
+			return []byte(`{"responses":[]}`), nil
+		},
+	}
+	transformer := &ReleaseResponseTransformerMock{
+		TransformSearchResponseFunc: func(ctx context.Context, responseData []byte, req query.ReleaseSearchRequest, highlight bool) ([]byte, error) {
+			return []byte(`{"took":1,"releases":[]}`), nil
+		},
+		TransformCalendarResponseFunc: func(ctx context.Context, responseData []byte, websiteURL string) ([]byte, error) {
+			return []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil
+		},
+	}
+	cfg := &config.Config{WebsiteURL: "https://www.ons.gov.uk"}
+	searchHandler := SearchReleasesHandlerFunc(query.NewReleaseQueryParamValidator(), builder, cfg, &ClientList{DpESClient: searcher}, transformer)
+
+	convey.Convey("Should return an iCalendar of the releases when format=ics", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?release-type=type-upcoming&format=ics", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusOK)
+		convey.So(resp.Header().Get("Content-Type"), convey.ShouldEqual, "text/calendar;charset=utf-8")
+		convey.So(resp.Header().Get("ETag"), convey.ShouldBeEmpty)
+		convey.So(resp.Body.String(), convey.ShouldEqual, "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")
+
+		calls := transformer.TransformCalendarResponseCalls()
+		convey.So(string(calls[len(calls)-1].ResponseData), convey.ShouldEqual, `{"took":1,"releases":[]}`)
+		convey.So(calls[len(calls)-1].WebsiteURL, convey.ShouldEqual, "https://www.ons.gov.uk")
+		builderCalls := builder.BuildSearchQueryCalls()
+		convey.So(builderCalls[len(builderCalls)-1].Request.(*query.ReleaseSearchRequest).Highlight, convey.ShouldBeFalse)
+		convey.So(builderCalls[len(builderCalls)-1].Request.(*query.ReleaseSearchRequest).Size, convey.ShouldEqual, query.MaxCalendarReleases)
+	})
+
+	convey.Convey("Should allow a higher limit for an iCalendar than for JSON", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?format=ics&limit=2000", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusOK)
+		builderCalls := builder.BuildSearchQueryCalls()
+		convey.So(builderCalls[len(builderCalls)-1].Request.(*query.ReleaseSearchRequest).Size, convey.ShouldEqual, 2000)
+
+		req = httptest.NewRequest("GET", "http://localhost:8080/search/releases?format=json&limit=2000", http.NoBody)
+		resp = httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusBadRequest)
+	})
+
+	convey.Convey("Should return an iCalendar of the releases when one is accepted", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases", http.NoBody)
+		req.Header.Set("Accept", "text/calendar;q=0.9, application/json;q=0.8")
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusOK)
+		convey.So(resp.Header().Get("Content-Type"), convey.ShouldEqual, "text/calendar;charset=utf-8")
+		convey.So(resp.Header().Get("Vary"), convey.ShouldEqual, "Accept")
+	})
+
+	convey.Convey("Should return JSON when any media type is accepted", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases", http.NoBody)
+		req.Header.Set("Accept", "*/*")
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusOK)
+		convey.So(resp.Header().Get("Content-Type"), convey.ShouldEqual, "application/json;charset=utf-8")
+		convey.So(resp.Body.String(), convey.ShouldEqual, `{"took":1,"releases":[]}`)
+	})
+
+	convey.Convey("Should return BadRequest for an invalid format along with other invalid parameters", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?format=pdf&limit=x", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusBadRequest)
+		convey.So(resp.Body.String(), convey.ShouldContainSubstring, "invalid format parameter, must be one of: json, ics")
+		convey.So(resp.Body.String(), convey.ShouldContainSubstring, "Invalid limit parameter")
+	})
+
+	convey.Convey("Should return BadRequest for a raw iCalendar", t, func() {
+		req := httptest.NewRequest("GET", "http://localhost:8080/search/releases?format=ics&raw=true", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		convey.So(resp.Code, convey.ShouldEqual, http.StatusBadRequest)
+		convey.So(resp.Body.String(), convey.ShouldContainSubstring, `"code":"conflicting_parameters"`)
+	})
+}
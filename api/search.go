@@ -16,6 +16,8 @@ import (
 	catCli "github.com/ONSdigital/dp-api-clients-go/v2/nlp/category"
 	catModel "github.com/ONSdigital/dp-api-clients-go/v2/nlp/category/models"
 	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-net/v3/request"
+	"github.com/ONSdigital/dp-search-api/apierrors"
 	"github.com/ONSdigital/dp-search-api/config"
 	"github.com/ONSdigital/dp-search-api/elasticsearch"
 	"github.com/ONSdigital/dp-search-api/models"
@@ -40,12 +42,26 @@ const (
 	ParamSubtypeConfirmed   = "subtype-confirmed"
 	ParamSubtypePostponed   = "subtype-postponed"
 	ParamCensus             = "census"
+	ParamRescheduledSince   = "rescheduled_since"
+	ParamFrom               = "from"
+	ParamTo                 = "to"
+	ParamInterval           = "interval"
 	ParamNLPWeighting       = "nlp_weighting"
 	ParamDatasetIDs         = "dataset_ids"
 	ParamURIPrefix          = "uri_prefix"
 	ParamCDIDs              = "cdids"
+	ParamCursor             = "cursor"
+	ParamFacets             = "facets"
+	ParamHistogram          = "histogram"
+	ParamAutoCorrect        = "auto_correct"
+	ParamLang               = "lang"
+	ParamProfile            = "profile"
+	ParamVariant            = "variant"
 )
 
+// DistinctItemsCountQuery names the query counting distinct items in the warnings of partial search responses
+const DistinctItemsCountQuery = "distinct_items_count"
+
 // defaultContentTypes is an array of all valid content types, which is the default param value
 var defaultContentTypes = []string{
 	"article",
@@ -175,16 +191,27 @@ func paramGetBool(params url.Values, key string, defaultValue bool) bool {
 }
 
 // CreateRequests reads the parameters from the request and generates the corresponding SearchRequest and CountRequest
-// If any validation fails, the http.Error is already handled, and nil is returned: in this case the caller may return straight away
+// If any validation fails, the error response is already written, and nil is returned: in this case the caller may return straight away
 func CreateRequests(w http.ResponseWriter, req *http.Request, cfg *config.Config, validator QueryParamValidator, nlpCriteria *query.NlpCriteria) (string, *query.SearchRequest, *query.CountRequest) {
+	q, reqSearch, reqCount, errs := createRequests(req, cfg, validator, nlpCriteria)
+	if len(errs) > 0 {
+		writeErrors(w, http.StatusBadRequest, errs)
+		return "", nil, nil
+	}
+	return q, reqSearch, reqCount
+}
+
+// createRequests reads the parameters from the request and generates the corresponding SearchRequest and CountRequest,
+// or else returns an error for every invalid parameter
+func createRequests(req *http.Request, cfg *config.Config, validator QueryParamValidator, nlpCriteria *query.NlpCriteria) (string, *query.SearchRequest, *query.CountRequest, paramErrors) {
 	ctx := req.Context()
 	params := req.URL.Query()
+	var errs paramErrors
 
 	// Sanitise and validate the query string
 	sanitisedQuery, sanitiseErr := sanitiseAndValidateQuery(ctx, params)
 	if sanitiseErr != nil {
-		http.Error(w, sanitiseErr.Error(), http.StatusBadRequest)
-		return "", nil, nil
+		errs.add(ParamQ, params.Get(ParamQ), sanitiseErr)
 	}
 
 	// Parse and validate other query parameters
@@ -192,72 +219,110 @@ func CreateRequests(w http.ResponseWriter, req *http.Request, cfg *config.Config
 
 	topics, topicErr := parseTopics(ctx, params)
 	if topicErr != nil {
-		http.Error(w, topicErr.Error(), http.StatusBadRequest)
-		return "", nil, nil
+		errs.add(ParamTopics, params.Get(ParamTopics), topicErr)
 	}
 
 	limit, limitErr := parseLimit(ctx, params, validator)
 	if limitErr != nil {
-		http.Error(w, limitErr.Error(), http.StatusBadRequest)
-		return "", nil, nil
+		errs.add(ParamLimit, params.Get(ParamLimit), limitErr)
 	}
 
 	offset, offsetErr := parseOffset(ctx, params, validator)
 	if offsetErr != nil {
-		http.Error(w, offsetErr.Error(), http.StatusBadRequest)
-		return "", nil, nil
+		errs.add(ParamOffset, params.Get(ParamOffset), offsetErr)
+	}
+
+	pointInTime, cursorErr := parseCursor(ctx, params, cfg)
+	if cursorErr != nil {
+		errs.add(ParamCursor, params.Get(ParamCursor), cursorErr)
+	}
+
+	if pointInTime != nil && offset > 0 {
+		log.Warn(ctx, "offset provided with cursor", log.Data{"param": ParamOffset, "value": offset})
+		errs.addCode(apierrors.CodeConflictingParameters, ParamOffset, params.Get(ParamOffset), "offset cannot be used with cursor")
 	}
 
 	contentTypes, contentTypesErr := parseAndValidateContentTypes(ctx, params)
 	if contentTypesErr != nil {
-		http.Error(w, contentTypesErr.Error(), http.StatusBadRequest)
-		return "", nil, nil
+		errs.add(ParamContentType, params.Get(ParamContentType), contentTypesErr)
+	}
+
+	facets, facetsErr := parseFacets(ctx, params, validator)
+	if facetsErr != nil {
+		errs.add(ParamFacets, params.Get(ParamFacets), facetsErr)
+	}
+
+	histogram, histogramErr := parseHistogram(ctx, params, validator)
+	if histogramErr != nil {
+		errs.add(ParamHistogram, params.Get(ParamHistogram), histogramErr)
+	}
+
+	language, languageErr := parseLanguage(ctx, params, validator)
+	if languageErr != nil {
+		errs.add(ParamLang, params.Get(ParamLang), languageErr)
+	}
+
+	// requests assigned to a variant are ranked by its boost profile, so that the profile parameter cannot change
+	// the ranking being trialled
+	variant := variantFromContext(ctx)
+	defaultBoostProfile := query.DefaultBoostProfile
+	profileParams := params
+	if variant != nil {
+		if variant.BoostProfile != "" {
+			defaultBoostProfile = variant.BoostProfile
+		}
+		if params.Has(ParamProfile) {
+			log.Warn(ctx, "profile parameter ignored for search assigned to experiment variant", log.Data{"param": ParamProfile, "value": params.Get(ParamProfile), "variant": variant.Name})
+		}
+		profileParams = url.Values{}
+	}
+
+	boostProfile, boostProfileErr := parseBoostProfile(ctx, profileParams, validator, defaultBoostProfile)
+	if boostProfileErr != nil {
+		errs.add(ParamProfile, params.Get(ParamProfile), boostProfileErr)
 	}
 
 	sort, sortErr := parseAndValidateSort(ctx, cfg, params, validator)
 	if sortErr != nil {
-		http.Error(w, sortErr.Error(), http.StatusBadRequest)
-		return "", nil, nil
+		errs.add(ParamSort, params.Get(ParamSort), sortErr)
 	}
 
 	fromDateParam := paramGet(params, "fromDate", "")
-	fromDate, err := validator.Validate(ctx, "date", fromDateParam)
-	if err != nil {
-		log.Warn(ctx, err.Error(), log.Data{"param": "fromDate", "value": fromDateParam})
-		http.Error(w, "Invalid fromDate parameter", http.StatusBadRequest)
-		return "", nil, nil
+	fromDate, fromDateErr := validator.Validate(ctx, "date", fromDateParam)
+	if fromDateErr != nil {
+		log.Warn(ctx, fromDateErr.Error(), log.Data{"param": "fromDate", "value": fromDateParam})
+		errs.addCode(apierrors.CodeInvalidParameter, "fromDate", fromDateParam, "Invalid fromDate parameter")
 	}
 
 	toDateParam := paramGet(params, "toDate", "")
-	toDate, err := validator.Validate(ctx, "date", toDateParam)
-	if err != nil {
-		log.Warn(ctx, err.Error(), log.Data{"param": "toDateParam", "value": toDateParam})
-		http.Error(w, "Invalid toDate parameter", http.StatusBadRequest)
-		return "", nil, nil
+	toDate, toDateErr := validator.Validate(ctx, "date", toDateParam)
+	if toDateErr != nil {
+		log.Warn(ctx, toDateErr.Error(), log.Data{"param": "toDateParam", "value": toDateParam})
+		errs.addCode(apierrors.CodeInvalidParameter, "toDate", toDateParam, "Invalid toDate parameter")
 	}
 
-	if fromAfterTo(fromDate.(query.Date), toDate.(query.Date)) {
+	if fromDateErr == nil && toDateErr == nil && fromAfterTo(fromDate.(query.Date), toDate.(query.Date)) {
 		log.Warn(ctx, "fromDate after toDate", log.Data{"fromDate": fromDateParam, "toDate": toDateParam})
-		http.Error(w, "invalid dates - 'from' after 'to'", http.StatusBadRequest)
-		return "", nil, nil
+		errs.addCode(apierrors.CodeConflictingParameters, "fromDate", fromDateParam, "invalid dates - 'from' after 'to'")
 	}
 
 	uriPrefix, uriPrefixErr := parseURIPrefix(ctx, params)
 	if uriPrefixErr != nil {
-		http.Error(w, uriPrefixErr.Error(), http.StatusBadRequest)
-		return "", nil, nil
+		errs.add(ParamURIPrefix, params.Get(ParamURIPrefix), uriPrefixErr)
 	}
 
 	cdids, cdidErr := parseCDID(ctx, params)
 	if cdidErr != nil {
-		http.Error(w, cdidErr.Error(), http.StatusBadRequest)
-		return "", nil, nil
+		errs.add(ParamCDIDs, params.Get(ParamCDIDs), cdidErr)
 	}
 
 	datasetIDs, datasetErr := parseDatasetIDs(ctx, params)
 	if datasetErr != nil {
-		http.Error(w, datasetErr.Error(), http.StatusBadRequest)
-		return "", nil, nil
+		errs.add(ParamDatasetIDs, params.Get(ParamDatasetIDs), datasetErr)
+	}
+
+	if len(errs) > 0 {
+		return "", nil, nil, errs
 	}
 
 	// Create SearchRequest
@@ -266,15 +331,31 @@ func CreateRequests(w http.ResponseWriter, req *http.Request, cfg *config.Config
 	// Process additional parameters like Population Types, Dimensions, and Dataset IDs
 	reqSearch.PopulationTypes = parsePopulationTypes(params)
 	reqSearch.Dimensions = parseDimensions(params)
+	reqSearch.PointInTime = pointInTime
+	reqSearch.Facets = facets
+	reqSearch.Histogram = histogram
+	reqSearch.Language = language
+	reqSearch.BoostProfile = boostProfile
 
 	// Create CountRequest
 	reqCount := createCountRequest(sanitisedQuery)
+	reqCount.Language = language
+
+	if variant != nil && variant.Templates != "" {
+		reqSearch.TemplateSet = variant.Name
+		reqCount.TemplateSet = variant.Name
+	}
+
+	if cursorErr = checkCursor(ctx, params, reqSearch); cursorErr != nil {
+		errs.add(ParamCursor, params.Get(ParamCursor), cursorErr)
+		return "", nil, nil, errs
+	}
 
 	if cfg.DebugMode {
 		log.Info(ctx, "[DEBUG]", log.Data{"search_request": reqSearch})
 	}
 
-	return params.Get(ParamQ), reqSearch, reqCount
+	return params.Get(ParamQ), reqSearch, reqCount, nil
 }
 
 func sanitiseAndValidateQuery(ctx context.Context, params url.Values) (string, error) {
@@ -336,6 +417,63 @@ func parseAndValidateContentTypes(ctx context.Context, params url.Values) (conte
 	return contentTypes, nil
 }
 
+// parseFacets returns the facets requested by the facets parameter, or nil if it is not provided so that all are returned
+func parseFacets(ctx context.Context, params url.Values, validator QueryParamValidator) (*query.Facets, error) {
+	facetsParam := paramGet(params, ParamFacets, "")
+	if facetsParam == "" {
+		return nil, nil
+	}
+
+	validatedFacets, validationErr := validator.Validate(ctx, ParamFacets, facetsParam)
+	if validationErr != nil {
+		log.Warn(ctx, validationErr.Error(), log.Data{"param": ParamFacets, "value": facetsParam})
+		return nil, validationErr
+	}
+	return validatedFacets.(*query.Facets), nil
+}
+
+// parseHistogram returns the interval of the release date histogram requested by the histogram parameter, if any
+func parseHistogram(ctx context.Context, params url.Values, validator QueryParamValidator) (string, error) {
+	histogramParam := paramGet(params, ParamHistogram, "")
+	if histogramParam == "" {
+		return "", nil
+	}
+
+	validatedHistogram, validationErr := validator.Validate(ctx, ParamHistogram, histogramParam)
+	if validationErr != nil {
+		log.Warn(ctx, validationErr.Error(), log.Data{"param": ParamHistogram, "value": histogramParam})
+		return "", validationErr
+	}
+	return validatedHistogram.(string), nil
+}
+
+// parseLanguage returns the language of the content requested by the lang parameter, if any
+func parseLanguage(ctx context.Context, params url.Values, validator QueryParamValidator) (string, error) {
+	languageParam := paramGet(params, ParamLang, "")
+	if languageParam == "" {
+		return "", nil
+	}
+
+	validatedLanguage, validationErr := validator.Validate(ctx, ParamLang, languageParam)
+	if validationErr != nil {
+		log.Warn(ctx, validationErr.Error(), log.Data{"param": ParamLang, "value": languageParam})
+		return "", validationErr
+	}
+	return validatedLanguage.(string), nil
+}
+
+// parseBoostProfile returns the boost profile requested by the profile parameter, or the provided default boost profile
+func parseBoostProfile(ctx context.Context, params url.Values, validator QueryParamValidator, defaultProfile string) (*query.BoostProfile, error) {
+	profileParam := paramGet(params, ParamProfile, defaultProfile)
+
+	validatedProfile, validationErr := validator.Validate(ctx, ParamProfile, profileParam)
+	if validationErr != nil {
+		log.Warn(ctx, validationErr.Error(), log.Data{"param": ParamProfile, "value": profileParam})
+		return nil, validationErr
+	}
+	return validatedProfile.(*query.BoostProfile), nil
+}
+
 func parseAndValidateSort(ctx context.Context, cfg *config.Config, params url.Values, validator QueryParamValidator) (sort string, err error) {
 	sortParam := paramGet(params, ParamSort, cfg.DefaultSort)
 	validatedSort, validationErr := validator.Validate(ctx, ParamSort, sortParam)
@@ -495,55 +633,86 @@ func SearchHandlerFunc(validator QueryParamValidator, queryBuilder QueryBuilder,
 			return // error already handled
 		}
 
-		var (
-			resDataChan        = make(chan []byte)
-			resCountChan       = make(chan []byte)
-			responseSearchData []byte
-			responseCountData  []byte
-			count              int
-			err                error
-		)
+		var err error
 
-		go func() {
-			processCountQuery(ctx, clList.DpESClient, queryBuilder, countReq, resCountChan)
-		}()
+		raw := paramGetBool(params, "raw", false)
+		autoCorrectEnabled := paramGetBool(params, ParamAutoCorrect, false)
 
-		go func() {
-			processSearchQuery(ctx, cfg, clList.DpESClient, queryBuilder, searchReq, resDataChan)
-		}()
+		// pages of a cursor are read from a point in time, so are not cached
+		var cacheKey string
+		if !raw && searchReq.PointInTime == nil && clList.ResponseCache != nil {
+			if cacheKey, err = searchCacheKey(searchReq, autoCorrectEnabled, variantFromContext(ctx)); err != nil {
+				log.Warn(ctx, "search response will not be cached", log.Data{"error": err.Error()})
+			}
+		}
 
-		for i := 0; i < 2; i++ {
-			select {
-			case responseSearchData = <-resDataChan:
-			case responseCountData = <-resCountChan:
+		cached, ok := getCachedResponse(ctx, clList.ResponseCache, cacheKey)
+		if cacheKey != "" {
+			clList.Metrics.recordCacheLookup(searchRoute, ok)
+		}
+		if ok {
+			var cachedResponse models.SearchResponse
+			if err = json.Unmarshal(cached.Body, &cachedResponse); err == nil {
+				if cachedResponse.CorrectedQuery != "" {
+					q = cachedResponse.CorrectedQuery
+				}
+				writeSearchResponse(w, req, cfg, clList, q, searchReq.From, &cachedResponse, cached.ETag, cached)
+				return
 			}
+			log.Warn(ctx, "invalid cached search response ignored", log.Data{"error": err.Error()})
 		}
 
-		if !paramGetBool(params, "raw", false) {
+		responseSearchData, responseCountData, searchErr := runSearch(ctx, cfg, clList, queryBuilder, searchReq, countReq)
+		if cursorExpired(params, searchErr) {
+			var errs paramErrors
+			errs.add(ParamCursor, params.Get(ParamCursor), errors.New("cursor has expired, start again with cursor=*"))
+			writeErrors(w, http.StatusBadRequest, errs)
+			return
+		}
+
+		var correctedQuery string
+		if autoCorrectEnabled && searchReq.PointInTime == nil {
+			if correctedSearchData, correctedCountData, correction := autoCorrect(ctx, cfg, clList, queryBuilder, searchReq, countReq, responseSearchData); correction != "" {
+				responseSearchData, responseCountData, correctedQuery = correctedSearchData, correctedCountData, correction
+				q = correction
+			}
+		}
+
+		if !raw {
 			if responseSearchData == nil {
 				log.Error(ctx, "call to elastic multisearch api failed", errors.New("nil response data"))
-				http.Error(w, "call to elastic multisearch api failed", http.StatusInternalServerError)
+				writeSearchFailed(w, clList.DpESClient, "call to elastic multisearch api failed")
 				return
 			}
 
-			responseSearchData, err = transformer.TransformSearchResponse(ctx, responseSearchData, q, searchReq.Highlight)
-			if err != nil {
-				log.Error(ctx, "transformation of response data failed", err)
-				http.Error(w, "failed to transform search result", http.StatusInternalServerError)
-				return
+			var cursor string
+			if searchReq.PointInTime != nil {
+				cursor, err = pageCursor(ctx, clList.DpESClient, responseSearchData, searchReq)
+				if err != nil {
+					log.Error(ctx, "creation of next cursor failed", err)
+					writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "failed to transform search result")
+					return
+				}
 			}
 
-			if responseCountData == nil {
-				log.Error(ctx, "call to elasticsearch count api failed due to", errors.New("nil response data"))
-				http.Error(w, "call to elasticsearch count api failed due to", http.StatusInternalServerError)
+			warnings, contentErr := failedSearches(responseSearchData, searchReq)
+			if contentErr != nil {
+				log.Error(ctx, "elasticsearch content search failed", contentErr)
+				writeSearchFailed(w, clList.DpESClient, "call to elastic multisearch api failed")
 				return
 			}
-			count, err = transformer.TransformCountResponse(ctx, responseCountData)
+
+			responseSearchData, err = transformer.TransformSearchResponse(ctx, responseSearchData, q, searchReq.Highlight)
 			if err != nil {
-				log.Error(ctx, "transformation of response count data failed", err)
-				http.Error(w, "failed to transform count result", http.StatusInternalServerError)
+				log.Error(ctx, "transformation of response data failed", err)
+				writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "failed to transform search result")
 				return
 			}
+
+			count, countWarning := distinctItemsCount(ctx, transformer, responseCountData)
+			if countWarning != nil {
+				warnings = append(warnings, *countWarning)
+			}
 			//TODO: This needs to be refactored as it involves multiple marshal and unmarshal code. So basically the
 			// transformSearchResponse function can return an interface that would satisfy both legacy search response and
 			// new search response instead of bytes. So here we just have to add the count instead of unmarshalling the bytes
@@ -551,26 +720,117 @@ func SearchHandlerFunc(validator QueryParamValidator, queryBuilder QueryBuilder,
 			var esSearchResponse models.SearchResponse
 			if SearchRespErr := json.Unmarshal(responseSearchData, &esSearchResponse); SearchRespErr != nil {
 				log.Error(ctx, "failed to unmarshal the essearchResponse data due to", SearchRespErr)
-				http.Error(w, "failed to unmarshal the essearchResponse data due to", http.StatusInternalServerError)
+				writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "failed to unmarshal the essearchResponse data due to")
 				return
 			}
+			clList.Metrics.recordTook(searchRoute, esSearchResponse.Took)
 			esSearchResponse.DistinctItemsCount = count
-			var responseDataErr error
-			responseSearchData, responseDataErr = json.Marshal(esSearchResponse)
-			if responseDataErr != nil {
-				log.Error(ctx, "failed to marshal the elasticsearch response data due to", responseDataErr)
-				http.Error(w, "failed to transform search result", http.StatusInternalServerError)
+			esSearchResponse.Warnings = warnings
+			esSearchResponse.Cursor = cursor
+			if correctedQuery != "" {
+				esSearchResponse.CorrectedQuery = correctedQuery
+				esSearchResponse.OriginalQuery = params.Get(ParamQ)
+			}
+			if variant := variantFromContext(ctx); variant != nil {
+				esSearchResponse.Experiment = variant.Experiment
+				esSearchResponse.Variant = variant.Name
+			}
+
+			// the response is tagged and cached before the request ID is added, as that differs for every request
+			transformedData, transformErr := json.Marshal(esSearchResponse)
+			if transformErr != nil {
+				log.Error(ctx, "failed to marshal the elasticsearch response data due to", transformErr)
+				writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "failed to transform search result")
 				return
 			}
+			// partial responses are not cached, so that results are complete again as soon as elasticsearch recovers
+			var cached *CachedResponse
+			if len(warnings) == 0 {
+				cached = setCachedResponse(ctx, clList.ResponseCache, cacheKey, transformedData)
+			}
+
+			writeSearchResponse(w, req, cfg, clList, q, searchReq.From, &esSearchResponse, responseETag(transformedData, cached), cached)
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json;charset=utf-8")
 		if _, err := w.Write(responseSearchData); err != nil {
 			log.Error(ctx, "writing response failed", err)
-			http.Error(w, "Failed to write http response", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to write http response")
+			return
+		}
+	}
+}
+
+// writeSearchResponse records a search response as an impression and writes it with the ID of the request, or
+// 304 Not Modified if the request's If-None-Match header matches its entity tag. The entity tag is weak, as it is
+// computed without the request ID, which is also returned in a header so that it is known for 304 responses.
+func writeSearchResponse(w http.ResponseWriter, req *http.Request, cfg *config.Config, clList *ClientList, q string, from int, response *models.SearchResponse, etag string, cached *CachedResponse) {
+	ctx := req.Context()
+
+	clList.Metrics.recordResults(searchRoute, response.Count)
+
+	// the request ID is set by the HTTP server's request ID middleware
+	impression := &models.FeedbackEvent{
+		RequestID: request.GetRequestId(ctx),
+		Query:     q,
+		Position:  from + 1,
+		Variant:   response.Variant,
+		Session:   req.Header.Get(cfg.ExperimentHeader),
+	}
+	response.RequestID = impression.RequestID
+	recordImpression(ctx, clList, impression, response)
+
+	if response.RequestID != "" {
+		w.Header().Set(request.RequestHeaderKey, response.RequestID)
+	}
+	if writeNotModified(w, req, weakETag(etag), cached) {
+		return
+	}
+
+	responseData, err := json.Marshal(response)
+	if err != nil {
+		log.Error(ctx, "failed to marshal the elasticsearch response data due to", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "failed to transform search result")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	if _, err = w.Write(responseData); err != nil {
+		log.Error(ctx, "writing response failed", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to write http response")
+		return
+	}
+}
+
+// runSearch runs the search and count queries concurrently, returning nil data for any query that failed, along
+// with the error of a failed point in time search
+func runSearch(ctx context.Context, cfg *config.Config, clList *ClientList, queryBuilder QueryBuilder, searchReq *query.SearchRequest, countReq *query.CountRequest) (responseSearchData, responseCountData []byte, searchErr error) {
+	var (
+		resDataChan  = make(chan []byte)
+		resCountChan = make(chan []byte)
+	)
+
+	go func() {
+		processCountQuery(ctx, clList.DpESClient, queryBuilder, countReq, resCountChan)
+	}()
+
+	go func() {
+		if searchReq.PointInTime != nil {
+			processPointInTimeSearchQuery(ctx, cfg, clList.DpESClient, queryBuilder, searchReq, resDataChan, &searchErr)
 			return
 		}
+		processSearchQuery(ctx, cfg, clList.DpESClient, queryBuilder, searchReq, resDataChan)
+	}()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case responseSearchData = <-resDataChan:
+		case responseCountData = <-resCountChan:
+		}
 	}
+
+	return responseSearchData, responseCountData, searchErr
 }
 
 // SearchURIsHandlerFunc handles the /search/uris endpoint
@@ -580,7 +840,7 @@ func SearchURIsHandlerFunc(validator QueryParamValidator, queryBuilder QueryBuil
 
 		req, reqErr := parseAndValidateSearchURIRequest(r, validator, cfg)
 		if reqErr != nil {
-			http.Error(w, reqErr.Error(), http.StatusBadRequest)
+			writeError(w, http.StatusBadRequest, apierrors.CodeInvalidBody, reqErr.Error())
 			return
 		}
 
@@ -603,7 +863,6 @@ func SearchURIsHandlerFunc(validator QueryParamValidator, queryBuilder QueryBuil
 			resCountChan       = make(chan []byte)
 			responseSearchData []byte
 			responseCountData  []byte
-			count              int
 			err                error
 		)
 
@@ -624,49 +883,50 @@ func SearchURIsHandlerFunc(validator QueryParamValidator, queryBuilder QueryBuil
 
 		if responseSearchData == nil {
 			log.Error(ctx, "call to elastic multisearch api failed", errors.New("nil response data"))
-			http.Error(w, "call to elastic multisearch api failed", http.StatusInternalServerError)
+			writeSearchFailed(w, clList.DpESClient, "call to elastic multisearch api failed")
+			return
+		}
+
+		warnings, contentErr := failedSearches(responseSearchData, searchRequest)
+		if contentErr != nil {
+			log.Error(ctx, "elasticsearch content search failed", contentErr)
+			writeSearchFailed(w, clList.DpESClient, "call to elastic multisearch api failed")
 			return
 		}
 
 		responseSearchData, err = transformer.TransformSearchResponse(ctx, responseSearchData, "", searchRequest.Highlight)
 		if err != nil {
 			log.Error(ctx, "transformation of response data failed", err)
-			http.Error(w, "failed to transform search result", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "failed to transform search result")
 			return
 		}
 
-		if responseCountData == nil {
-			log.Error(ctx, "call to elasticsearch count api failed due to", errors.New("nil response data"))
-			http.Error(w, "call to elasticsearch count api failed due to", http.StatusInternalServerError)
-			return
-		}
-		count, err = transformer.TransformCountResponse(ctx, responseCountData)
-		if err != nil {
-			log.Error(ctx, "transformation of response count data failed", err)
-			http.Error(w, "failed to transform count result", http.StatusInternalServerError)
-			return
+		count, countWarning := distinctItemsCount(ctx, transformer, responseCountData)
+		if countWarning != nil {
+			warnings = append(warnings, *countWarning)
 		}
 
 		var esSearchResponse models.SearchResponse
 		if SearchRespErr := json.Unmarshal(responseSearchData, &esSearchResponse); SearchRespErr != nil {
 			log.Error(ctx, "failed to unmarshal the essearchResponse data due to", SearchRespErr)
-			http.Error(w, "failed to unmarshal the essearchResponse data due to", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "failed to unmarshal the essearchResponse data due to")
 			return
 		}
 
 		esSearchResponse.DistinctItemsCount = count
+		esSearchResponse.Warnings = warnings
 		var responseDataErr error
 		responseSearchData, responseDataErr = json.Marshal(esSearchResponse)
 		if responseDataErr != nil {
 			log.Error(ctx, "failed to marshal the elasticsearch response data due to", responseDataErr)
-			http.Error(w, "failed to transform search result", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "failed to transform search result")
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json;charset=utf-8")
 		if _, err := w.Write(responseSearchData); err != nil {
 			log.Error(ctx, "writing response failed", err)
-			http.Error(w, "Failed to write http response", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to write http response")
 			return
 		}
 	}
@@ -760,20 +1020,20 @@ func LegacySearchHandlerFunc(validator QueryParamValidator, queryBuilder QueryBu
 				ParamLimit:  searchReq.Size,
 				ParamOffset: searchReq.From,
 			})
-			http.Error(w, "Failed to create search query", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to create search query")
 			return
 		}
 
 		responseData, err := clList.DeprecatedESClient.MultiSearch(ctx, "ons", "", formattedQuery)
 		if err != nil {
 			log.Error(ctx, "elasticsearch query failed", err)
-			http.Error(w, "Failed to run search query", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeSearchFailed, "Failed to run search query")
 			return
 		}
 
 		if !json.Valid(responseData) {
 			log.Error(ctx, "elastic search returned invalid JSON for search query", errors.New("elastic search returned invalid JSON for search query"))
-			http.Error(w, "Failed to process search query", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeSearchFailed, "Failed to process search query")
 			return
 		}
 
@@ -781,7 +1041,7 @@ func LegacySearchHandlerFunc(validator QueryParamValidator, queryBuilder QueryBu
 			responseData, err = transformer.TransformSearchResponse(ctx, responseData, q, searchReq.Highlight)
 			if err != nil {
 				log.Error(ctx, "transformation of response data failed", err)
-				http.Error(w, "Failed to transform search result", http.StatusInternalServerError)
+				writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to transform search result")
 				return
 			}
 		}
@@ -790,7 +1050,7 @@ func LegacySearchHandlerFunc(validator QueryParamValidator, queryBuilder QueryBu
 		_, err = w.Write(responseData)
 		if err != nil {
 			log.Error(ctx, "writing response failed", err)
-			http.Error(w, "Failed to write http response", http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to write http response")
 			return
 		}
 	}
@@ -818,10 +1078,10 @@ func (a SearchAPI) CreateSearchIndexHandlerFunc(w http.ResponseWriter, req *http
 	ctx := req.Context()
 	indexName := createIndexName("ons")
 
-	err := a.clList.DpESClient.CreateIndex(ctx, indexName, elasticsearch.GetSearchIndexSettings())
+	err := a.clList.DpESClient.CreateIndex(ctx, indexName, a.searchIndexSettings(ctx))
 	if err != nil {
 		log.Error(ctx, "creating index failed with this error", err)
-		http.Error(w, serverErrorMessage, http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
 		return
 	}
 
@@ -833,11 +1093,29 @@ func (a SearchAPI) CreateSearchIndexHandlerFunc(w http.ResponseWriter, req *http
 	_, err = w.Write(jsonResponse)
 	if err != nil {
 		log.Error(ctx, "writing response failed", err)
-		http.Error(w, serverErrorMessage, http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
 		return
 	}
 }
 
+// searchIndexSettings returns the settings for a new search index, carrying over the synonyms of the current
+// search index so that a reindex does not revert synonyms updated through PUT /search/synonyms
+func (a SearchAPI) searchIndexSettings(ctx context.Context) []byte {
+	synonyms, err := a.getSearchSynonyms(ctx)
+	if err != nil {
+		log.Warn(ctx, "using the default synonyms for the new index", log.Data{"error": err.Error()})
+		return elasticsearch.GetSearchIndexSettings()
+	}
+
+	settings, err := elasticsearch.GetSearchIndexSettingsWithSynonyms(synonyms)
+	if err != nil {
+		log.Warn(ctx, "using the default synonyms for the new index", log.Data{"error": err.Error()})
+		return elasticsearch.GetSearchIndexSettings()
+	}
+
+	return settings
+}
+
 func createIndexName(s string) string {
 	now := time.Now()
 	return fmt.Sprintf("%s%d", s, now.UnixMicro())
@@ -931,6 +1209,64 @@ func processCountQuery(ctx context.Context, elasticSearchClient DpElasticSearche
 	resCountChan <- countRes
 }
 
+// failedSearches returns a warning for every facet count search that elasticsearch failed in the multi search
+// response, or an error if the content search failed, as there are no results to return without it.
+// Responses that cannot be parsed are left for the transformer to report.
+func failedSearches(responseData []byte, searchReq *query.SearchRequest) ([]models.Warning, error) {
+	var esResponses struct {
+		Responses []struct {
+			Error json.RawMessage `json:"error"`
+		} `json:"responses"`
+	}
+	if err := json.Unmarshal(responseData, &esResponses); err != nil {
+		return nil, nil
+	}
+
+	searches := searchReq.Searches()
+	var warnings []models.Warning
+	for i, response := range esResponses.Responses {
+		if len(response.Error) == 0 || string(response.Error) == "null" {
+			continue
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("%s search failed: %s", query.ContentSearch, response.Error)
+		}
+
+		name := fmt.Sprintf("search %d", i)
+		if i < len(searches) {
+			name = searches[i]
+		}
+		warnings = append(warnings, models.Warning{
+			Query:       name,
+			Description: fmt.Sprintf("%s facet counts are missing as their search failed", name),
+		})
+	}
+
+	return warnings, nil
+}
+
+// distinctItemsCount returns the count of distinct items, or a warning if the count query failed,
+// so that the search results can still be returned without it
+func distinctItemsCount(ctx context.Context, transformer ResponseTransformer, responseCountData []byte) (*int, *models.Warning) {
+	warning := &models.Warning{
+		Query:       DistinctItemsCountQuery,
+		Description: "distinct items count is missing as its query failed",
+	}
+
+	if responseCountData == nil {
+		log.Warn(ctx, "call to elasticsearch count api failed, distinct items count omitted")
+		return nil, warning
+	}
+
+	count, err := transformer.TransformCountResponse(ctx, responseCountData)
+	if err != nil {
+		log.Warn(ctx, "transformation of response count data failed, distinct items count omitted", log.Data{"error": err.Error()})
+		return nil, warning
+	}
+
+	return &count, nil
+}
+
 func AddNlpToSearch(ctx context.Context, queryBuilder QueryBuilder, params url.Values, nlpSettings query.NlpSettings, clList *ClientList) *query.NlpCriteria {
 	var berlin *brModel.Berlin
 	var category *[]catModel.Category
@@ -939,7 +1275,9 @@ func AddNlpToSearch(ctx context.Context, queryBuilder QueryBuilder, params url.V
 	scrOpt := scrSdk.OptInit()
 
 	// If scrubber is down for any reason, we need to stop the NLP feature from interfering with regular dp-search-api resp
+	start := time.Now()
 	scrubber, err := clList.ScrubberClient.GetScrubber(ctx, scrOpt.Q(params.Get("q")))
+	clList.Metrics.recordNLPRequest("scrubber", start, err != nil)
 	if err != nil {
 		log.Error(ctx, "error making request to scrubber", err)
 		return nil
@@ -947,7 +1285,9 @@ func AddNlpToSearch(ctx context.Context, queryBuilder QueryBuilder, params url.V
 
 	brOpt := brlCli.OptInit()
 
+	start = time.Now()
 	berlin, err = clList.BerlinClient.GetBerlin(ctx, *brOpt.Q(scrubber.Query))
+	clList.Metrics.recordNLPRequest("berlin", start, err != nil)
 	if err != nil || berlin == nil {
 		log.Error(ctx, "error making request to berlin", err)
 		// If berlin isn't working or gives an empty response
@@ -959,7 +1299,9 @@ func AddNlpToSearch(ctx context.Context, queryBuilder QueryBuilder, params url.V
 
 	catOpt := catCli.OptInit()
 
+	start = time.Now()
 	category, err = clList.CategoryClient.GetCategory(ctx, *catOpt.Q(berlin.Query))
+	clList.Metrics.recordNLPRequest("category", start, err != nil)
 	if err != nil {
 		log.Error(ctx, "error making request to category", err)
 	}
@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
-	"strings"
 	"testing"
 
 	"github.com/ONSdigital/dp-api-clients-go/v2/nlp/berlin"
@@ -19,6 +18,8 @@ import (
 	catErr "github.com/ONSdigital/dp-api-clients-go/v2/nlp/category/errors"
 	catModels "github.com/ONSdigital/dp-api-clients-go/v2/nlp/category/models"
 	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	"github.com/ONSdigital/dp-search-api/apierrors"
 	"github.com/ONSdigital/dp-search-api/config"
 	"github.com/ONSdigital/dp-search-api/models"
 	"github.com/ONSdigital/dp-search-api/query"
@@ -291,6 +292,174 @@ func TestSearchHandlerFunc(t *testing.T) {
 		c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 0)
 	})
 
+	c.Convey("Should return BadRequest for an unknown facet", t, func() {
+		qbMock := newQueryBuilderMock(nil, nil)
+		esMock := newDpElasticSearcherMock(nil, nil)
+		trMock := newResponseTransformerMock(nil, nil)
+
+		searchHandler := SearchHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		req := httptest.NewRequest("GET", "http://localhost:8080/search?facets=topics,colours", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+		c.So(resp.Body.String(), c.ShouldContainSubstring, "facets parameter provided is invalid")
+		c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 0)
+		c.So(esMock.MultiSearchCalls(), c.ShouldHaveLength, 0)
+	})
+
+	c.Convey("Should return BadRequest for a facet size that is too high", t, func() {
+		qbMock := newQueryBuilderMock(nil, nil)
+		esMock := newDpElasticSearcherMock(nil, nil)
+		trMock := newResponseTransformerMock(nil, nil)
+
+		searchHandler := SearchHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		req := httptest.NewRequest("GET", "http://localhost:8080/search?facets=dimensions:1001", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+		c.So(resp.Body.String(), c.ShouldContainSubstring, "facet size for dimensions must be a number between 1 and 1000")
+		c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 0)
+	})
+
+	c.Convey("Should pass the requested facets on to the query builder", t, func() {
+		qbMock := newQueryBuilderMock(validQueryDocBytes, nil)
+		esMock := newDpElasticSearcherMock([]byte(validESResponse), nil)
+		trMock := newResponseTransformerMock([]byte(validTransformedResponse), nil)
+
+		searchHandler := SearchHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		req := httptest.NewRequest("GET", "http://localhost:8080/search?facets=topics,dimensions:50", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+		c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 1)
+		c.So(qbMock.BuildSearchQueryCalls()[0].Req.Facets, c.ShouldResemble, &query.Facets{
+			Topics:     &query.Facet{Size: 1000},
+			Dimensions: &query.Facet{Size: 50},
+		})
+	})
+
+	c.Convey("Should return BadRequest for an invalid histogram interval", t, func() {
+		qbMock := newQueryBuilderMock(nil, nil)
+		esMock := newDpElasticSearcherMock(nil, nil)
+		trMock := newResponseTransformerMock(nil, nil)
+
+		searchHandler := SearchHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		req := httptest.NewRequest("GET", "http://localhost:8080/search?histogram=week", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+		c.So(resp.Body.String(), c.ShouldContainSubstring, "histogram parameter provided is invalid")
+		c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 0)
+	})
+
+	c.Convey("Should pass the requested histogram interval on to the query builder", t, func() {
+		qbMock := newQueryBuilderMock(validQueryDocBytes, nil)
+		esMock := newDpElasticSearcherMock([]byte(validESResponse), nil)
+		trMock := newResponseTransformerMock([]byte(validTransformedResponse), nil)
+
+		searchHandler := SearchHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		req := httptest.NewRequest("GET", "http://localhost:8080/search?histogram=Month", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+		c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 1)
+		c.So(qbMock.BuildSearchQueryCalls()[0].Req.Histogram, c.ShouldEqual, "month")
+	})
+
+	c.Convey("Should return BadRequest for an unsupported lang parameter", t, func() {
+		qbMock := newQueryBuilderMock(nil, nil)
+		esMock := newDpElasticSearcherMock(nil, nil)
+		trMock := newResponseTransformerMock(nil, nil)
+
+		searchHandler := SearchHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		req := httptest.NewRequest("GET", "http://localhost:8080/search?lang=fr", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+		c.So(resp.Body.String(), c.ShouldContainSubstring, "lang parameter provided is invalid")
+		c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 0)
+	})
+
+	c.Convey("Should pass the requested language on to the search and count queries", t, func() {
+		qbMock := newQueryBuilderMock(validQueryDocBytes, nil)
+		esMock := newDpElasticSearcherMock([]byte(validESResponse), nil)
+		trMock := newResponseTransformerMock([]byte(validTransformedResponse), nil)
+
+		searchHandler := SearchHandlerFunc(validator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		req := httptest.NewRequest("GET", "http://localhost:8080/search?q=cyfrifiad&lang=cy", http.NoBody)
+		resp := httptest.NewRecorder()
+
+		searchHandler.ServeHTTP(resp, req)
+
+		c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+		c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 1)
+		c.So(qbMock.BuildSearchQueryCalls()[0].Req.Language, c.ShouldEqual, "cy")
+		c.So(qbMock.BuildCountQueryCalls(), c.ShouldHaveLength, 1)
+		c.So(qbMock.BuildCountQueryCalls()[0].Req.Language, c.ShouldEqual, "cy")
+	})
+
+	c.Convey("Should pass the requested boost profile on to the search query", t, func() {
+		profiles, err := query.ParseBoostProfiles([]byte(`{"recent": {"recency_decay": {"scale": "365d"}}}`))
+		c.So(err, c.ShouldBeNil)
+		profileValidator := query.NewSearchQueryParamValidator().WithBoostProfiles(profiles)
+
+		qbMock := newQueryBuilderMock(validQueryDocBytes, nil)
+		esMock := newDpElasticSearcherMock([]byte(validESResponse), nil)
+		trMock := newResponseTransformerMock([]byte(validTransformedResponse), nil)
+
+		searchHandler := SearchHandlerFunc(profileValidator, qbMock, cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		c.Convey("The default profile is used when none is requested", func() {
+			req := httptest.NewRequest("GET", "http://localhost:8080/search?q=census", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+			c.So(qbMock.BuildSearchQueryCalls()[0].Req.BoostProfile, c.ShouldEqual, profiles[query.DefaultBoostProfile])
+		})
+
+		c.Convey("The requested profile is used", func() {
+			req := httptest.NewRequest("GET", "http://localhost:8080/search?q=census&profile=recent", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+			c.So(qbMock.BuildSearchQueryCalls()[0].Req.BoostProfile, c.ShouldEqual, profiles["recent"])
+		})
+
+		c.Convey("An unknown profile is rejected", func() {
+			req := httptest.NewRequest("GET", "http://localhost:8080/search?q=census&profile=unknown", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+			c.So(resp.Body.String(), c.ShouldContainSubstring, "profile parameter provided is invalid: boost profile must be one of: default, recent")
+			c.So(qbMock.BuildSearchQueryCalls(), c.ShouldHaveLength, 0)
+		})
+	})
+
 	c.Convey("Should return BadRequest for invalid cdid params", t, func() {
 		qbMock := newQueryBuilderMock(nil, nil)
 		esMock := newDpElasticSearcherMock(nil, nil)
@@ -1155,6 +1324,74 @@ func TestSearchHandlerFunc(t *testing.T) {
 	})
 }
 
+func TestSearchHandlerFuncPartialFailure(t *testing.T) {
+	validQueryDocBytes, _ := json.Marshal([]client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"query":{}}`)}})
+	cfg := &config.Config{DefaultLimit: 10, DefaultMaximumLimit: 100, DefaultSort: "relevance"}
+
+	c.Convey("Given a search handler", t, func() {
+		esMock := newDpElasticSearcherMock([]byte(validESResponse), nil)
+		trMock := newResponseTransformerMock([]byte(validTransformedResponse), nil)
+		searchHandler := SearchHandlerFunc(query.NewSearchQueryParamValidator(), newQueryBuilderMock(validQueryDocBytes, nil), cfg, &ClientList{DpESClient: esMock}, trMock)
+
+		c.Convey("When the distinct items count query fails", func() {
+			esMock.CountFunc = func(ctx context.Context, count client.Count) ([]byte, error) {
+				return nil, errors.New("count failed")
+			}
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=a", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then the results are returned without the distinct items count, with a warning naming the count query", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Body.String(), c.ShouldNotContainSubstring, `"distinct_items_count":`)
+				var searchResp models.SearchResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &searchResp), c.ShouldBeNil)
+				c.So(searchResp.DistinctItemsCount, c.ShouldBeNil)
+				c.So(searchResp.Warnings, c.ShouldResemble, []models.Warning{
+					{Query: DistinctItemsCountQuery, Description: "distinct items count is missing as its query failed"},
+				})
+			})
+		})
+
+		c.Convey("When elasticsearch fails some of the facet count searches", func() {
+			esMock.MultiSearchFunc = func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
+				return []byte(`{"responses":[{"hits":{"total":0}},{"error":{"type":"too_many_buckets_exception"},"status":503},{},{},{"error":{"type":"search_phase_execution_exception"},"status":500}]}`), nil
+			}
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=a", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then the results are returned with a warning naming each failed facet", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				var searchResp models.SearchResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &searchResp), c.ShouldBeNil)
+				c.So(*searchResp.DistinctItemsCount, c.ShouldEqual, 0)
+				c.So(searchResp.Warnings, c.ShouldResemble, []models.Warning{
+					{Query: "topics", Description: "topics facet counts are missing as their search failed"},
+					{Query: "dimensions", Description: "dimensions facet counts are missing as their search failed"},
+				})
+			})
+		})
+
+		c.Convey("When elasticsearch fails the content search", func() {
+			esMock.MultiSearchFunc = func(ctx context.Context, searches []client.Search, params *client.QueryParams) ([]byte, error) {
+				return []byte(`{"responses":[{"error":{"type":"search_phase_execution_exception"},"status":500},{}]}`), nil
+			}
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search?q=a", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then a search failed error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusInternalServerError)
+				c.So(trMock.TransformSearchResponseCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+	})
+}
+
 func TestSearchURIsHandlerFunc(t *testing.T) {
 	searches := []client.Search{}
 	c.Convey("Test SearchURIsHandlerFunc", t, func() {
@@ -1656,6 +1893,9 @@ func TestLegacySearchHandlerFunc(t *testing.T) {
 func TestCreateSearchIndexHandlerFunc(t *testing.T) {
 	c.Convey("Given a Search API that is pointing to the Site Wide version of Elastic Search", t, func() {
 		dpESClient := newDpElasticSearcherMock(nil, nil)
+		dpESClient.GetIndexSettingsFunc = func(ctx context.Context, index string) ([]byte, error) {
+			return nil, esError.StatusError{Err: errors.New("index_not_found_exception"), Code: http.StatusNotFound}
+		}
 
 		searchAPI := &SearchAPI{
 			clList: &ClientList{
@@ -1688,9 +1928,47 @@ func TestCreateSearchIndexHandlerFunc(t *testing.T) {
 		})
 	})
 
+	c.Convey("Given a search index whose synonyms have been updated", t, func() {
+		dpESClient := newDpElasticSearcherMock(nil, nil)
+		dpESClient.GetIndexSettingsFunc = func(ctx context.Context, index string) ([]byte, error) {
+			return []byte(`{"ons1":{"settings":{"index":{"analysis":{"filter":{"ons_search_synonyms":{"type":"synonym_graph","synonyms":["cpi, consumer price index"]}}}}}}}`), nil
+		}
+
+		searchAPI := &SearchAPI{
+			clList: &ClientList{
+				DpESClient: dpESClient,
+			},
+		}
+
+		c.Convey("When a new elastic search index is created", func() {
+			req := httptest.NewRequest("POST", "http://localhost:23900/search", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.CreateSearchIndexHandlerFunc(resp, req)
+
+			c.Convey("Then the new index is created with the synonyms of the current index", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusCreated)
+				c.So(dpESClient.CreateIndexCalls(), c.ShouldHaveLength, 1)
+				var settings struct {
+					Settings struct {
+						Analysis struct {
+							Filter map[string]synonymsFilter `json:"filter"`
+						} `json:"analysis"`
+					} `json:"settings"`
+				}
+				c.So(json.Unmarshal(dpESClient.CreateIndexCalls()[0].IndexSettings, &settings), c.ShouldBeNil)
+				c.So(settings.Settings.Analysis.Filter["ons_search_synonyms"].Synonyms, c.ShouldResemble, []string{"cpi, consumer price index"})
+				c.So(settings.Settings.Analysis.Filter["ons_search_synonyms"].Updateable, c.ShouldBeTrue)
+			})
+		})
+	})
+
 	c.Convey("Given a Search API that is pointing to the old version of Elastic Search", t, func() {
 		// The new ES client will return an error if the Search API config is pointing at the old version of ES
 		dpESClient := newDpElasticSearcherMock(nil, errors.New("unexpected status code from api"))
+		dpESClient.GetIndexSettingsFunc = func(ctx context.Context, index string) ([]byte, error) {
+			return nil, errors.New("unexpected status code from api")
+		}
 
 		searchAPI := &SearchAPI{
 			clList: &ClientList{
@@ -1706,7 +1984,9 @@ func TestCreateSearchIndexHandlerFunc(t *testing.T) {
 
 			c.Convey("Then an internal server error is returned with status code 500", func() {
 				c.So(resp.Code, c.ShouldEqual, http.StatusInternalServerError)
-				c.So(strings.Trim(resp.Body.String(), "\n"), c.ShouldResemble, internalServerErrMsg)
+				var errResp models.ErrorResponse
+				c.So(json.Unmarshal(resp.Body.Bytes(), &errResp), c.ShouldBeNil)
+				c.So(errResp.Errors, c.ShouldResemble, []models.Error{{Code: apierrors.CodeInternalError, Description: internalServerErrMsg}})
 			})
 		})
 	})
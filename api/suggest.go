@@ -0,0 +1,108 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/query"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// CreateSuggestRequest validates the parameters of a suggest request and returns the corresponding SuggestRequest.
+// If any validation fails, an error for every invalid parameter is written and nil is returned.
+func CreateSuggestRequest(w http.ResponseWriter, req *http.Request, validator QueryParamValidator) *query.SuggestRequest {
+	ctx := req.Context()
+	params := req.URL.Query()
+	var errs paramErrors
+
+	sanitisedQuery, sanitiseErr := sanitiseAndValidateQuery(ctx, params)
+	if sanitiseErr != nil {
+		errs.add(ParamQ, params.Get(ParamQ), sanitiseErr)
+	} else if sanitisedQuery == "" {
+		log.Warn(ctx, "suggest request without a query", log.Data{"param": ParamQ})
+		errs.addCode(apierrors.CodeMissingParameter, ParamQ, "", "q parameter is required")
+	}
+
+	limit, limitErr := parseLimit(ctx, params, validator)
+	if limitErr != nil {
+		errs.add(ParamLimit, params.Get(ParamLimit), limitErr)
+	}
+
+	var contentTypes []string
+	if paramGet(params, ParamContentType, "") != "" {
+		var contentTypesErr error
+		contentTypes, contentTypesErr = parseAndValidateContentTypes(ctx, params)
+		if contentTypesErr != nil {
+			errs.add(ParamContentType, params.Get(ParamContentType), contentTypesErr)
+		}
+	}
+
+	topics, topicErr := parseTopics(ctx, params)
+	if topicErr != nil {
+		errs.add(ParamTopics, params.Get(ParamTopics), topicErr)
+	}
+
+	if len(errs) > 0 {
+		writeErrors(w, http.StatusBadRequest, errs)
+		return nil
+	}
+
+	return &query.SuggestRequest{
+		Term:   sanitisedQuery,
+		Size:   limit,
+		Types:  contentTypes,
+		Topics: topics,
+	}
+}
+
+// SearchSuggestHandlerFunc returns a http handler function returning title completions for a partial search term
+func SearchSuggestHandlerFunc(validator QueryParamValidator, builder SuggestQueryBuilder, searcher DpElasticSearcher, transformer SuggestResponseTransformer) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		ctx := req.Context()
+
+		suggestReq := CreateSuggestRequest(w, req, validator)
+		if suggestReq == nil {
+			return // error already handled
+		}
+
+		searches, err := builder.BuildSearchQuery(ctx, *suggestReq)
+		if err != nil {
+			log.Error(ctx, "creation of suggest query failed", err, log.Data{
+				ParamQ:     suggestReq.Term,
+				ParamLimit: suggestReq.Size,
+			})
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to create suggest query")
+			return
+		}
+
+		responseData, err := searcher.MultiSearch(ctx, searches, nil)
+		if err != nil {
+			log.Error(ctx, "elasticsearch query failed", err)
+			writeSearchFailed(w, searcher, "Failed to run suggest query")
+			return
+		}
+
+		if !json.Valid(responseData) {
+			log.Error(ctx, "elastic search returned invalid JSON for suggest query", errors.New("elastic search returned invalid JSON for suggest query"))
+			writeError(w, http.StatusInternalServerError, apierrors.CodeSearchFailed, "Failed to process suggest query")
+			return
+		}
+
+		responseData, err = transformer.TransformSuggestResponse(ctx, responseData)
+		if err != nil {
+			log.Error(ctx, "transformation of response data failed", err)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to transform suggest result")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		_, err = w.Write(responseData)
+		if err != nil {
+			log.Error(ctx, "writing response failed", err)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, "Failed to write http response")
+			return
+		}
+	}
+}
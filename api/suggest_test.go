@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ONSdigital/dp-elasticsearch/v3/client"
+	"github.com/ONSdigital/dp-search-api/query"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func newSuggestQueryBuilderMock(err error) *SuggestQueryBuilderMock {
+	return &SuggestQueryBuilderMock{
+		BuildSearchQueryFunc: func(ctx context.Context, req query.SuggestRequest) ([]client.Search, error) {
+			if err != nil {
+				return nil, err
+			}
+			return []client.Search{{Header: client.Header{Index: "ons"}, Query: []byte(`{"size":8}`)}}, nil
+		},
+	}
+}
+
+func TestSearchSuggestHandlerFunc(t *testing.T) {
+	validator := query.NewSearchQueryParamValidator()
+	transformer := &SuggestResponseTransformerMock{
+		TransformSuggestResponseFunc: func(ctx context.Context, responseData []byte) ([]byte, error) {
+			return []byte(`{"took":1,"suggestions":[{"title":"Inflation"}]}`), nil
+		},
+	}
+
+	c.Convey("Given a suggest handler", t, func() {
+		builder := newSuggestQueryBuilderMock(nil)
+		searcher := newDpElasticSearcherMock([]byte(`{"responses":[]}`), nil)
+		searchHandler := SearchSuggestHandlerFunc(validator, builder, searcher, transformer)
+
+		c.Convey("When title completions are requested, filtered by content type and topic", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/suggest?q=infl&limit=8&content_type=bulletin&topics=1234", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then the transformed completions are returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Body.String(), c.ShouldEqual, `{"took":1,"suggestions":[{"title":"Inflation"}]}`)
+				c.So(builder.BuildSearchQueryCalls(), c.ShouldHaveLength, 1)
+				c.So(builder.BuildSearchQueryCalls()[0].Req, c.ShouldResemble, query.SuggestRequest{
+					Term:   "infl",
+					Size:   8,
+					Types:  []string{"bulletin"},
+					Topics: []string{"1234"},
+				})
+				c.So(searcher.MultiSearchCalls(), c.ShouldHaveLength, 1)
+			})
+		})
+
+		c.Convey("When no content type is provided", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/suggest?q=infl", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then the completions are not filtered by content type", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(builder.BuildSearchQueryCalls()[0].Req.Types, c.ShouldBeNil)
+				c.So(builder.BuildSearchQueryCalls()[0].Req.Size, c.ShouldEqual, 10)
+			})
+		})
+
+		c.Convey("When no query is provided", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/suggest", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then a bad request error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "q parameter is required")
+				c.So(builder.BuildSearchQueryCalls(), c.ShouldHaveLength, 0)
+			})
+		})
+
+		c.Convey("When an unknown content type is provided", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/suggest?q=infl&content_type=unknown", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then a bad request error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "invalid content_type(s): unknown")
+			})
+		})
+	})
+
+	c.Convey("Given elasticsearch returns an error", t, func() {
+		builder := newSuggestQueryBuilderMock(nil)
+		searcher := newDpElasticSearcherMock(nil, errors.New("elasticsearch unavailable"))
+		searchHandler := SearchSuggestHandlerFunc(validator, builder, searcher, transformer)
+
+		c.Convey("When title completions are requested", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:8080/search/suggest?q=infl", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchHandler.ServeHTTP(resp, req)
+
+			c.Convey("Then an internal server error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusInternalServerError)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "Failed to run suggest query")
+			})
+		})
+	})
+}
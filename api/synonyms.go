@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	"github.com/ONSdigital/dp-search-api/apierrors"
+	"github.com/ONSdigital/dp-search-api/elasticsearch"
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// synonymsFilter is the definition of the updateable search synonyms filter
+type synonymsFilter struct {
+	Type       string   `json:"type"`
+	Updateable bool     `json:"updateable"`
+	Synonyms   []string `json:"synonyms"`
+}
+
+// esIndexSettings is an elasticsearch GET /{index}/_settings response, keyed by index name.
+// Elasticsearch returns every setting value as a string, so only the synonyms are read.
+type esIndexSettings map[string]struct {
+	Settings struct {
+		Index struct {
+			Analysis struct {
+				Filter map[string]struct {
+					Synonyms []string `json:"synonyms"`
+				} `json:"filter"`
+			} `json:"analysis"`
+		} `json:"index"`
+	} `json:"settings"`
+}
+
+// GetSearchSynonymsHandlerFunc returns the synonyms currently applied to search queries
+func (a SearchAPI) GetSearchSynonymsHandlerFunc(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	synonyms, err := a.getSearchSynonyms(ctx)
+	if err != nil {
+		if esError.ErrorStatus(err) == http.StatusNotFound {
+			log.Warn(ctx, "no search index found to get synonyms from", log.Data{"alias": searchAlias})
+			writeError(w, http.StatusNotFound, apierrors.CodeNotFound, "search index not found")
+			return
+		}
+		log.Error(ctx, "getting synonyms failed with this error", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	writeJSON(w, req, http.StatusOK, models.Synonyms{Synonyms: synonyms})
+}
+
+// UpdateSearchSynonymsHandlerFunc replaces the synonyms applied to search queries on every index holding the search
+// alias. Synonyms are only used by the search analysers, so the indexes are updated in place and their search analysers
+// reloaded, without rebuilding them. The synonyms are stored in the index settings, which new indexes carry over.
+func (a SearchAPI) UpdateSearchSynonymsHandlerFunc(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+
+	var synonyms models.Synonyms
+	if err := json.NewDecoder(req.Body).Decode(&synonyms); err != nil {
+		log.Warn(ctx, "invalid synonyms request body", log.Data{"error": err.Error()})
+		writeError(w, http.StatusBadRequest, apierrors.CodeInvalidBody, "invalid request body")
+		return
+	}
+
+	if err := validateSynonyms(synonyms.Synonyms); err != nil {
+		log.Warn(ctx, "invalid synonyms provided", log.Data{"error": err.Error()})
+		writeError(w, http.StatusBadRequest, apierrors.CodeInvalidBody, err.Error())
+		return
+	}
+	if synonyms.Synonyms == nil {
+		synonyms.Synonyms = []string{}
+	}
+
+	holders, err := a.getAliasHolders(req)
+	if err != nil {
+		log.Error(ctx, "getting aliases failed with this error", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+	if len(holders) == 0 {
+		log.Warn(ctx, "no search index found to update synonyms on", log.Data{"alias": searchAlias})
+		writeError(w, http.StatusNotFound, apierrors.CodeNotFound, "search index not found")
+		return
+	}
+
+	settings, err := json.Marshal(map[string]interface{}{
+		"analysis": map[string]interface{}{
+			"filter": map[string]synonymsFilter{
+				elasticsearch.SearchSynonymsFilter: {Type: "synonym_graph", Updateable: true, Synonyms: synonyms.Synonyms},
+			},
+		},
+	})
+	if err != nil {
+		log.Error(ctx, "marshalling synonyms settings failed", err)
+		writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+		return
+	}
+
+	// updates are serialised, so that one update cannot reopen an index that another is still updating
+	a.synonymsUpdate.Lock()
+	defer a.synonymsUpdate.Unlock()
+
+	// the update outlives the request, so that every index is updated alike if the client disconnects
+	updateCtx := context.WithoutCancel(ctx)
+	for _, index := range holders {
+		logData := log.Data{"index_name": index, "synonyms": len(synonyms.Synonyms)}
+		if err = a.clList.DpESClient.UpdateAnalysisSettings(updateCtx, index, settings); err != nil {
+			if esError.ErrorStatus(err) == http.StatusBadRequest {
+				log.Warn(ctx, "synonyms rejected by elasticsearch", log.Data{"index_name": index, "error": err.Error()})
+				writeError(w, http.StatusBadRequest, apierrors.CodeInvalidBody, "synonyms rejected by elasticsearch")
+				return
+			}
+			log.Error(ctx, "updating synonyms failed with this error", err, logData)
+			writeError(w, http.StatusInternalServerError, apierrors.CodeInternalError, serverErrorMessage)
+			return
+		}
+		log.Info(ctx, "search synonyms updated", logData)
+	}
+
+	purgeCache(ctx, a.clList.ResponseCache, "search synonyms updated")
+	writeJSON(w, req, http.StatusOK, synonyms)
+}
+
+// getSearchSynonyms returns the synonyms of the newest index holding the search alias
+func (a SearchAPI) getSearchSynonyms(ctx context.Context) ([]string, error) {
+	responseData, err := a.clList.DpESClient.GetIndexSettings(ctx, searchAlias)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings esIndexSettings
+	if err = json.Unmarshal(responseData, &settings); err != nil {
+		return nil, errors.New("failed to unmarshal elasticsearch index settings response")
+	}
+	if len(settings) == 0 {
+		return nil, esError.StatusError{Err: errors.New("no index holds the search alias"), Code: http.StatusNotFound}
+	}
+
+	indexes := make([]string, 0, len(settings))
+	for index := range settings {
+		indexes = append(indexes, index)
+	}
+	sort.Strings(indexes)
+
+	filter, ok := settings[indexes[len(indexes)-1]].Settings.Index.Analysis.Filter[elasticsearch.SearchSynonymsFilter]
+	if !ok {
+		return nil, fmt.Errorf("search index has no %s filter", elasticsearch.SearchSynonymsFilter)
+	}
+	if filter.Synonyms == nil {
+		return []string{}, nil
+	}
+
+	return filter.Synonyms, nil
+}
+
+// validateSynonyms checks that each rule is either a comma separated list of equivalent terms,
+// e.g. "cpi, consumer price index", or an explicit mapping, e.g. "psf => public sector finance"
+func validateSynonyms(rules []string) error {
+	for _, rule := range rules {
+		sides := strings.Split(rule, "=>")
+		if len(sides) > 2 {
+			return fmt.Errorf("invalid synonym rule %q: a rule can only contain one =>", rule)
+		}
+
+		for _, side := range sides {
+			for _, term := range strings.Split(side, ",") {
+				if strings.TrimSpace(term) == "" {
+					return fmt.Errorf("invalid synonym rule %q: terms cannot be empty", rule)
+				}
+			}
+		}
+
+		if len(sides) == 1 && len(strings.Split(rule, ",")) < 2 {
+			return fmt.Errorf("invalid synonym rule %q: a rule needs at least two terms", rule)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	"github.com/ONSdigital/dp-search-api/models"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+const testIndexSettingsResponse = `{
+	"ons1700000000000000": {"settings": {"index": {"analysis": {"filter": {
+		"ons_search_synonyms": {"type": "synonym_graph", "updateable": "true", "synonyms": ["cpi, consumer price index", "psf => public sector finance"]}
+	}}}}}
+}`
+
+func newSynonymsESClientMock() *DpElasticSearcherMock {
+	dpESClient := newIndexesESClientMock()
+	dpESClient.GetIndexSettingsFunc = func(ctx context.Context, index string) ([]byte, error) {
+		return []byte(testIndexSettingsResponse), nil
+	}
+	dpESClient.UpdateAnalysisSettingsFunc = func(ctx context.Context, index string, settings []byte) error {
+		return nil
+	}
+	return dpESClient
+}
+
+func TestGetSearchSynonymsHandlerFunc(t *testing.T) {
+	c.Convey("Given a search index with synonyms", t, func() {
+		dpESClient := newSynonymsESClientMock()
+		searchAPI := newIndexesSearchAPI(dpESClient).RegisterSearchSynonyms()
+
+		c.Convey("When the synonyms are requested", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/synonyms", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then the synonyms of the index holding the search alias are returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(dpESClient.GetIndexSettingsCalls()[0].Index, c.ShouldEqual, "ons")
+				var synonyms models.Synonyms
+				c.So(json.Unmarshal(resp.Body.Bytes(), &synonyms), c.ShouldBeNil)
+				c.So(synonyms.Synonyms, c.ShouldResemble, []string{"cpi, consumer price index", "psf => public sector finance"})
+			})
+		})
+
+		c.Convey("When no index holds the search alias", func() {
+			dpESClient.GetIndexSettingsFunc = func(ctx context.Context, index string) ([]byte, error) {
+				return nil, esError.StatusError{Err: errors.New("index_not_found_exception"), Code: http.StatusNotFound}
+			}
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/search/synonyms", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			searchAPI.Router.ServeHTTP(resp, req)
+
+			c.Convey("Then a not found error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusNotFound)
+			})
+		})
+	})
+}
+
+func TestUpdateSearchSynonymsHandlerFunc(t *testing.T) {
+	c.Convey("Given a search index with synonyms", t, func() {
+		dpESClient := newSynonymsESClientMock()
+		searchAPI := newIndexesSearchAPI(dpESClient).RegisterSearchSynonyms()
+
+		put := func(req *http.Request) *httptest.ResponseRecorder {
+			resp := httptest.NewRecorder()
+			searchAPI.Router.ServeHTTP(resp, req)
+			return resp
+		}
+		newRequest := func(body string) *http.Request {
+			return httptest.NewRequest(http.MethodPut, "http://localhost:23900/search/synonyms", strings.NewReader(body))
+		}
+
+		c.Convey("When the synonyms are replaced", func() {
+			resp := put(newRequest(`{"synonyms": ["gdp, gross domestic product", "wellbeing => well being"]}`))
+
+			c.Convey("Then the search synonyms filter of the index holding the search alias is updated in place", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(dpESClient.CreateIndexCalls(), c.ShouldBeEmpty)
+				c.So(dpESClient.UpdateAliasesCalls(), c.ShouldBeEmpty)
+				c.So(dpESClient.UpdateAnalysisSettingsCalls(), c.ShouldHaveLength, 1)
+				c.So(dpESClient.UpdateAnalysisSettingsCalls()[0].Index, c.ShouldEqual, "ons1700000000000000")
+				var settings struct {
+					Analysis struct {
+						Filter map[string]synonymsFilter `json:"filter"`
+					} `json:"analysis"`
+				}
+				c.So(json.Unmarshal(dpESClient.UpdateAnalysisSettingsCalls()[0].Settings, &settings), c.ShouldBeNil)
+				c.So(settings.Analysis.Filter["ons_search_synonyms"], c.ShouldResemble, synonymsFilter{
+					Type:       "synonym_graph",
+					Updateable: true,
+					Synonyms:   []string{"gdp, gross domestic product", "wellbeing => well being"},
+				})
+
+				var synonyms models.Synonyms
+				c.So(json.Unmarshal(resp.Body.Bytes(), &synonyms), c.ShouldBeNil)
+				c.So(synonyms.Synonyms, c.ShouldResemble, []string{"gdp, gross domestic product", "wellbeing => well being"})
+			})
+		})
+
+		c.Convey("When the request is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			var updateCtxErr error
+			dpESClient.UpdateAnalysisSettingsFunc = func(ctx context.Context, index string, settings []byte) error {
+				updateCtxErr = ctx.Err()
+				return nil
+			}
+			resp := put(newRequest(`{"synonyms": ["a, b"]}`).WithContext(ctx))
+
+			c.Convey("Then the index is still updated", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(dpESClient.UpdateAnalysisSettingsCalls(), c.ShouldHaveLength, 1)
+				c.So(updateCtxErr, c.ShouldBeNil)
+			})
+		})
+
+		c.Convey("When invalid synonym rules are provided", func() {
+			for _, body := range []string{`{"synonyms": ["gdp"]}`, `{"synonyms": ["a => b => c"]}`, `{"synonyms": ["a, , b"]}`, `not json`} {
+				c.So(put(newRequest(body)).Code, c.ShouldEqual, http.StatusBadRequest)
+			}
+
+			c.Convey("Then the index is not updated", func() {
+				c.So(dpESClient.UpdateAnalysisSettingsCalls(), c.ShouldBeEmpty)
+			})
+		})
+
+		c.Convey("When elasticsearch rejects the synonyms", func() {
+			dpESClient.UpdateAnalysisSettingsFunc = func(ctx context.Context, index string, settings []byte) error {
+				return esError.StatusError{Err: errors.New("illegal_argument_exception"), Code: http.StatusBadRequest}
+			}
+			resp := put(newRequest(`{"synonyms": ["a, b"]}`))
+
+			c.Convey("Then a bad request error is returned", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusBadRequest)
+				c.So(resp.Body.String(), c.ShouldContainSubstring, "synonyms rejected by elasticsearch")
+			})
+		})
+	})
+}
@@ -8,3 +8,30 @@ var (
 	ErrUnmarshallingJSON = errors.New("failed to parse json body")
 	ErrMarshallingQuery  = errors.New("failed to marshal query to bytes for request body to send to elastic")
 )
+
+// A list of the codes of the errors returned in Search API error responses, which clients can rely on not changing
+
+const (
+	// CodeInvalidParameter is returned for each query parameter with an invalid value
+	CodeInvalidParameter = "invalid_parameter"
+	// CodeMissingParameter is returned for each required query parameter that is not provided
+	CodeMissingParameter = "missing_parameter"
+	// CodeConflictingParameters is returned when query parameters are valid on their own but not together
+	CodeConflictingParameters = "conflicting_parameters"
+	// CodeInvalidBody is returned when the request body cannot be parsed or holds invalid values
+	CodeInvalidBody = "invalid_body"
+	// CodeNotFound is returned when the resource does not exist
+	CodeNotFound = "not_found"
+	// CodeConflict is returned when the request conflicts with the state of the resource
+	CodeConflict = "conflict"
+	// CodeNotImplemented is returned when the feature behind the endpoint is not enabled
+	CodeNotImplemented = "not_implemented"
+	// CodeSearchFailed is returned when elasticsearch fails to run the search, or returns an invalid response
+	CodeSearchFailed = "search_failed"
+	// CodeRateLimited is returned when the client has made more requests than its rate limit allows
+	CodeRateLimited = "rate_limited"
+	// CodeServiceUnavailable is returned while elasticsearch is failing and searches fail fast
+	CodeServiceUnavailable = "service_unavailable"
+	// CodeInternalError is returned for any other failure
+	CodeInternalError = "internal_error"
+)
@@ -10,25 +10,49 @@ import (
 // Config is the search API handler config
 type Config struct {
 	AWS                        AWS
+	AutoCorrectMinScore        float64       `envconfig:"AUTO_CORRECT_MIN_SCORE"`
 	BerlinAPIURL               string        `envconfig:"BERLIN_URL"`
+	BoostProfiles              string        `envconfig:"BOOST_PROFILES"`
+	BoostProfilesFile          string        `envconfig:"BOOST_PROFILES_FILE"`
 	CategoryAPIURL             string        `envconfig:"CATEGORY_URL"`
 	BindAddr                   string        `envconfig:"BIND_ADDR"`
+	CursorKeepAlive            time.Duration `envconfig:"CURSOR_KEEP_ALIVE"`
 	DebugMode                  bool          `envconfig:"ENABLE_DEBUG"`
 	DefaultLimit               int           `envconfig:"DEFAULT_LIMIT"`
 	DefaultMaximumLimit        int           `envconfig:"DEFAULT_MAXIMUM_LIMIT"`
 	DefaultOffset              int           `envconfig:"DEFAULT_OFFSET"`
 	DefaultSort                string        `envconfig:"DEFAULT_SORT"`
 	ElasticSearchAPIURL        string        `envconfig:"ELASTIC_SEARCH_URL"`
+	ElasticSearchBreakerRate   float64       `envconfig:"ELASTIC_SEARCH_BREAKER_RATE"`
+	ElasticSearchBreakerMin    int           `envconfig:"ELASTIC_SEARCH_BREAKER_MIN"`
+	ElasticSearchBreakerOpen   time.Duration `envconfig:"ELASTIC_SEARCH_BREAKER_OPEN"`
+	ElasticSearchErrorWindow   time.Duration `envconfig:"ELASTIC_SEARCH_ERROR_WINDOW"`
+	ElasticSearchMaxRetries    int           `envconfig:"ELASTIC_SEARCH_MAX_RETRIES"`
+	ElasticSearchRetryBackoff  time.Duration `envconfig:"ELASTIC_SEARCH_RETRY_BACKOFF"`
+	ElasticSearchTimeout       time.Duration `envconfig:"ELASTIC_SEARCH_TIMEOUT"`
+	ExperimentHeader           string        `envconfig:"EXPERIMENT_HEADER"`
+	Experiments                string        `envconfig:"EXPERIMENTS"`
+	ExportColumns              string        `envconfig:"EXPORT_COLUMNS"`
+	ExportPageSize             int           `envconfig:"EXPORT_PAGE_SIZE"`
+	ExportTimeout              time.Duration `envconfig:"EXPORT_TIMEOUT"`
+	FeedbackFile               string        `envconfig:"FEEDBACK_FILE"`
 	GracefulShutdownTimeout    time.Duration `envconfig:"GRACEFUL_SHUTDOWN_TIMEOUT"`
 	HealthCheckCriticalTimeout time.Duration `envconfig:"HEALTHCHECK_CRITICAL_TIMEOUT"`
 	HealthCheckInterval        time.Duration `envconfig:"HEALTHCHECK_INTERVAL"`
 	NLPSettings                string        `envconfig:"NLP_SETTINGS"`
 	EnableNLPWeighting         bool          `envconfig:"ENABLE_NLP_WEIGHTING"`
+	RateLimitAPIKeys           []string      `envconfig:"RATE_LIMIT_API_KEYS"        json:"-"`
+	RateLimitKeyHeader         string        `envconfig:"RATE_LIMIT_KEY_HEADER"`
+	RateLimitTrustedProxies    int           `envconfig:"RATE_LIMIT_TRUSTED_PROXIES"`
+	RateLimits                 string        `envconfig:"RATE_LIMITS"`
+	ResponseCacheSize          int           `envconfig:"RESPONSE_CACHE_SIZE"`
+	ResponseCacheTTL           time.Duration `envconfig:"RESPONSE_CACHE_TTL"`
 	ScrubberAPIURL             string        `envconfig:"SCRUBBER_URL"`
 	OTBatchTimeout             time.Duration `encconfig:"OTEL_BATCH_TIMEOUT"`
 	OTServiceName              string        `envconfig:"OTEL_SERVICE_NAME"`
 	OTExporterOTLPEndpoint     string        `envconfig:"OTEL_EXPORTER_OTLP_ENDPOINT"`
 	OtelEnabled                bool          `envconfig:"OTEL_ENABLED"`
+	WebsiteURL                 string        `envconfig:"WEBSITE_URL"`
 	ZebedeeURL                 string        `envconfig:"ZEBEDEE_URL"`
 }
 
@@ -51,24 +75,48 @@ func Get() (*Config, error) {
 
 	cfg = &Config{
 		BindAddr:                   ":23900",
+		AutoCorrectMinScore:        0.001,
 		BerlinAPIURL:               "http://localhost:28900",
+		BoostProfiles:              "",
+		BoostProfilesFile:          "",
 		CategoryAPIURL:             "http://localhost:28800",
+		CursorKeepAlive:            time.Minute,
 		DebugMode:                  false,
 		DefaultLimit:               10,
 		DefaultMaximumLimit:        100,
 		DefaultOffset:              0,
 		DefaultSort:                "relevance",
 		ElasticSearchAPIURL:        "http://localhost:11200",
+		ElasticSearchBreakerRate:   0.5,
+		ElasticSearchBreakerMin:    20,
+		ElasticSearchBreakerOpen:   30 * time.Second,
+		ElasticSearchErrorWindow:   30 * time.Second,
+		ElasticSearchMaxRetries:    2,
+		ElasticSearchRetryBackoff:  100 * time.Millisecond,
+		ElasticSearchTimeout:       10 * time.Second,
+		ExperimentHeader:           "X-Session-Id",
+		Experiments:                "",
+		ExportColumns:              "uri,type,title,release_date,summary",
+		ExportPageSize:             500,
+		ExportTimeout:              10 * time.Minute,
+		FeedbackFile:               "",
 		GracefulShutdownTimeout:    5 * time.Second,
 		HealthCheckCriticalTimeout: 90 * time.Second,
 		HealthCheckInterval:        30 * time.Second,
 		NLPSettings:                "{\"category_weighting\": 100000000.0, \"category_limit\": 100, \"default_state\": \"gb\"}",
 		EnableNLPWeighting:         false,
+		RateLimitAPIKeys:           nil,
+		RateLimitKeyHeader:         "X-API-Key",
+		RateLimitTrustedProxies:    1,
+		RateLimits:                 "",
+		ResponseCacheSize:          1000,
+		ResponseCacheTTL:           30 * time.Second,
 		ScrubberAPIURL:             "http://localhost:28700",
 		OTBatchTimeout:             5 * time.Second,
 		OTExporterOTLPEndpoint:     "localhost:4317",
 		OTServiceName:              "dp-search-api",
 		OtelEnabled:                false,
+		WebsiteURL:                 "https://www.ons.gov.uk",
 		ZebedeeURL:                 "http://localhost:8082",
 	}
 
@@ -22,16 +22,40 @@ func TestSpec(t *testing.T) {
 				c.So(cfg.AWS.Region, c.ShouldEqual, "eu-west-2")
 				c.So(cfg.AWS.Service, c.ShouldEqual, "es")
 				c.So(cfg.AWS.TLSInsecureSkipVerify, c.ShouldEqual, false)
+				c.So(cfg.AutoCorrectMinScore, c.ShouldEqual, 0.001)
 				c.So(cfg.BindAddr, c.ShouldEqual, ":23900")
+				c.So(cfg.BoostProfiles, c.ShouldEqual, "")
+				c.So(cfg.BoostProfilesFile, c.ShouldEqual, "")
 				c.So(cfg.ElasticSearchAPIURL, c.ShouldEqual, "http://localhost:11200")
+				c.So(cfg.ElasticSearchBreakerRate, c.ShouldEqual, 0.5)
+				c.So(cfg.ElasticSearchBreakerMin, c.ShouldEqual, 20)
+				c.So(cfg.ElasticSearchBreakerOpen, c.ShouldEqual, 30*time.Second)
+				c.So(cfg.ElasticSearchErrorWindow, c.ShouldEqual, 30*time.Second)
+				c.So(cfg.ElasticSearchMaxRetries, c.ShouldEqual, 2)
+				c.So(cfg.ElasticSearchRetryBackoff, c.ShouldEqual, 100*time.Millisecond)
+				c.So(cfg.ElasticSearchTimeout, c.ShouldEqual, 10*time.Second)
+				c.So(cfg.Experiments, c.ShouldEqual, "")
+				c.So(cfg.ExportColumns, c.ShouldEqual, "uri,type,title,release_date,summary")
+				c.So(cfg.ExportPageSize, c.ShouldEqual, 500)
+				c.So(cfg.ExportTimeout, c.ShouldEqual, 10*time.Minute)
+				c.So(cfg.FeedbackFile, c.ShouldEqual, "")
 				c.So(cfg.BerlinAPIURL, c.ShouldEqual, "http://localhost:28900")
 				c.So(cfg.CategoryAPIURL, c.ShouldEqual, "http://localhost:28800")
+				c.So(cfg.CursorKeepAlive, c.ShouldEqual, time.Minute)
 				c.So(cfg.ScrubberAPIURL, c.ShouldEqual, "http://localhost:28700")
+				c.So(cfg.ExperimentHeader, c.ShouldEqual, "X-Session-Id")
 				c.So(cfg.GracefulShutdownTimeout, c.ShouldEqual, 5*time.Second)
 				c.So(cfg.HealthCheckCriticalTimeout, c.ShouldEqual, 90*time.Second)
 				c.So(cfg.HealthCheckInterval, c.ShouldEqual, 30*time.Second)
 				c.So(cfg.NLPSettings, c.ShouldEqual, "{\"category_weighting\": 100000000.0, \"category_limit\": 100, \"default_state\": \"gb\"}")
 				c.So(cfg.EnableNLPWeighting, c.ShouldEqual, false)
+				c.So(cfg.RateLimitAPIKeys, c.ShouldBeEmpty)
+				c.So(cfg.RateLimitKeyHeader, c.ShouldEqual, "X-API-Key")
+				c.So(cfg.RateLimitTrustedProxies, c.ShouldEqual, 1)
+				c.So(cfg.RateLimits, c.ShouldEqual, "")
+				c.So(cfg.WebsiteURL, c.ShouldEqual, "https://www.ons.gov.uk")
+				c.So(cfg.ResponseCacheSize, c.ShouldEqual, 1000)
+				c.So(cfg.ResponseCacheTTL, c.ShouldEqual, 30*time.Second)
 				c.So(cfg.DefaultLimit, c.ShouldEqual, 10)
 				c.So(cfg.DefaultMaximumLimit, c.ShouldEqual, 100)
 				c.So(cfg.DefaultOffset, c.ShouldEqual, 0)
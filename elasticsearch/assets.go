@@ -1,6 +1,13 @@
 package elasticsearch
 
-import _ "embed"
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+)
+
+// SearchSynonymsFilter is the name of the updateable synonym_graph filter used by the search analysers
+const SearchSynonymsFilter = "ons_search_synonyms"
 
 //go:embed search-index-settings.json
 var searchIndexSettingsJSON []byte
@@ -8,3 +15,32 @@ var searchIndexSettingsJSON []byte
 func GetSearchIndexSettings() []byte {
 	return searchIndexSettingsJSON
 }
+
+// GetSearchIndexSettingsWithSynonyms returns the search index settings with the provided synonym rules
+// in place of the default ones
+func GetSearchIndexSettingsWithSynonyms(synonyms []string) ([]byte, error) {
+	var settings map[string]interface{}
+	if err := json.Unmarshal(searchIndexSettingsJSON, &settings); err != nil {
+		return nil, err
+	}
+
+	filter, ok := lookup(settings, "settings", "analysis", "filter", SearchSynonymsFilter)
+	if !ok {
+		return nil, errors.New("search index settings have no " + SearchSynonymsFilter + " filter")
+	}
+	filter["synonyms"] = synonyms
+
+	return json.Marshal(settings)
+}
+
+// lookup returns the JSON object found by following the provided keys
+func lookup(object map[string]interface{}, keys ...string) (map[string]interface{}, bool) {
+	for _, key := range keys {
+		child, ok := object[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		object = child
+	}
+	return object, true
+}
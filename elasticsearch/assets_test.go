@@ -18,3 +18,33 @@ func TestGetDefaultMappings_ValidJson(t *testing.T) {
 		})
 	})
 }
+
+func TestGetSearchIndexSettingsWithSynonyms(t *testing.T) {
+	c.Convey("Given a set of synonym rules", t, func() {
+		synonyms := []string{"cpi, consumer price index"}
+
+		c.Convey("When the search index settings are requested with them", func() {
+			settingsJSON, err := elasticsearch.GetSearchIndexSettingsWithSynonyms(synonyms)
+
+			c.Convey("Then the search synonyms filter holds the provided rules", func() {
+				c.So(err, c.ShouldBeNil)
+				var settings struct {
+					Settings struct {
+						Analysis struct {
+							Filter map[string]struct {
+								Type       string   `json:"type"`
+								Updateable bool     `json:"updateable"`
+								Synonyms   []string `json:"synonyms"`
+							} `json:"filter"`
+						} `json:"analysis"`
+					} `json:"settings"`
+				}
+				c.So(json.Unmarshal(settingsJSON, &settings), c.ShouldBeNil)
+				filter := settings.Settings.Analysis.Filter[elasticsearch.SearchSynonymsFilter]
+				c.So(filter.Type, c.ShouldEqual, "synonym_graph")
+				c.So(filter.Updateable, c.ShouldBeTrue)
+				c.So(filter.Synonyms, c.ShouldResemble, synonyms)
+			})
+		})
+	})
+}
@@ -0,0 +1,332 @@
+package elasticsearch
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	dpEsClient "github.com/ONSdigital/dp-elasticsearch/v3/client"
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
+)
+
+// halfOpenRetryAfter is how long callers are asked to wait while a trial call decides whether to close the breaker
+const halfOpenRetryAfter = time.Second
+
+// Searcher is the elasticsearch client wrapped by a ResilientClient
+type Searcher interface {
+	dpEsClient.Client
+	OpenPointInTime(ctx context.Context, indices []string, keepAlive string) (string, error)
+	ClosePointInTime(ctx context.Context, id string) error
+	PointInTimeSearch(ctx context.Context, query []byte) ([]byte, error)
+	GetIndexSettings(ctx context.Context, index string) ([]byte, error)
+	UpdateAnalysisSettings(ctx context.Context, index string, settings []byte) error
+}
+
+// ResilienceConfig configures the timeouts, retries and circuit breaker of a ResilientClient
+type ResilienceConfig struct {
+	// Timeout of each call to elasticsearch, or none if zero
+	Timeout time.Duration
+	// MaxRetries of a call that elasticsearch rejects with 429 Too Many Requests or 503 Service Unavailable
+	MaxRetries int
+	// RetryBackoff is the longest wait before the first retry, doubling for every further retry
+	RetryBackoff time.Duration
+	// BreakerErrorRate is the proportion of failed calls within a window that trips the breaker, or never if zero
+	BreakerErrorRate float64
+	// BreakerMinRequests is the number of calls within a window before the error rate can trip the breaker
+	BreakerMinRequests int
+	// BreakerWindow is the period over which the error rate is measured
+	BreakerWindow time.Duration
+	// BreakerOpenDuration is how long calls fail fast once the breaker has tripped, before a trial call is made
+	BreakerOpenDuration time.Duration
+}
+
+// CircuitOpenError is returned instead of calling elasticsearch while the circuit breaker is open
+type CircuitOpenError struct {
+	RetryAfter time.Duration
+}
+
+func (e CircuitOpenError) Error() string {
+	return fmt.Sprintf("elasticsearch circuit breaker open, retry after %s", e.RetryAfter)
+}
+
+// Status returns 503 Service Unavailable, consistent with the status errors of the elasticsearch client
+func (e CircuitOpenError) Status() int {
+	return http.StatusServiceUnavailable
+}
+
+// ResilientClient decorates the search calls of an elasticsearch client with a timeout, retries of calls that
+// elasticsearch rejects while overloaded, and a circuit breaker that fails fast once too many calls have failed.
+// Calls that manage indexes and synonyms are passed straight through.
+type ResilientClient struct {
+	Searcher
+	cfg     ResilienceConfig
+	breaker *circuitBreaker
+	random  func() float64
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// NewResilientClient wraps the provided elasticsearch client
+func NewResilientClient(searcher Searcher, cfg ResilienceConfig) *ResilientClient {
+	return &ResilientClient{
+		Searcher: searcher,
+		cfg:      cfg,
+		breaker:  newCircuitBreaker(cfg, time.Now),
+		random:   rand.Float64, //nolint:gosec // jitter does not need a secure random number
+		sleep:    sleep,
+	}
+}
+
+// MultiSearch runs the searches through the circuit breaker
+func (rc *ResilientClient) MultiSearch(ctx context.Context, searches []dpEsClient.Search, params *dpEsClient.QueryParams) (data []byte, err error) {
+	err = rc.call(ctx, func(callCtx context.Context) (callErr error) {
+		data, callErr = rc.Searcher.MultiSearch(callCtx, searches, params)
+		return callErr
+	})
+	return data, err
+}
+
+// Count runs the count through the circuit breaker
+func (rc *ResilientClient) Count(ctx context.Context, count dpEsClient.Count) (data []byte, err error) {
+	err = rc.call(ctx, func(callCtx context.Context) (callErr error) {
+		data, callErr = rc.Searcher.Count(callCtx, count)
+		return callErr
+	})
+	return data, err
+}
+
+// OpenPointInTime opens the point in time through the circuit breaker
+func (rc *ResilientClient) OpenPointInTime(ctx context.Context, indices []string, keepAlive string) (id string, err error) {
+	err = rc.call(ctx, func(callCtx context.Context) (callErr error) {
+		id, callErr = rc.Searcher.OpenPointInTime(callCtx, indices, keepAlive)
+		return callErr
+	})
+	return id, err
+}
+
+// PointInTimeSearch runs the search through the circuit breaker
+func (rc *ResilientClient) PointInTimeSearch(ctx context.Context, query []byte) (data []byte, err error) {
+	err = rc.call(ctx, func(callCtx context.Context) (callErr error) {
+		data, callErr = rc.Searcher.PointInTimeSearch(callCtx, query)
+		return callErr
+	})
+	return data, err
+}
+
+// Tripped returns whether calls are currently failing fast, and how long until elasticsearch will be tried again
+func (rc *ResilientClient) Tripped() (retryAfter time.Duration, tripped bool) {
+	return rc.breaker.tripped()
+}
+
+// CircuitBreakerChecker reports a warning while the circuit breaker is not closed
+func (rc *ResilientClient) CircuitBreakerChecker(ctx context.Context, state *health.CheckState) error {
+	switch retryAfter, status := rc.breaker.status(); status {
+	case breakerOpen:
+		return state.Update(health.StatusWarning, fmt.Sprintf("circuit breaker open, elasticsearch calls failing fast for %s", retryAfter.Round(time.Second)), 0)
+	case breakerHalfOpen:
+		return state.Update(health.StatusWarning, "circuit breaker half open, trialling elasticsearch", 0)
+	default:
+		return state.Update(health.StatusOK, "circuit breaker closed", 0)
+	}
+}
+
+// call makes the call with the configured timeout, retrying it with a jittered backoff while elasticsearch rejects
+// it as overloaded, and records every attempt with the circuit breaker
+func (rc *ResilientClient) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	for attempt := 0; ; attempt++ {
+		if err := rc.breaker.allow(); err != nil {
+			return err
+		}
+
+		callCtx, cancel := ctx, context.CancelFunc(func() {})
+		if rc.cfg.Timeout > 0 {
+			callCtx, cancel = context.WithTimeout(ctx, rc.cfg.Timeout)
+		}
+		err := fn(callCtx)
+		cancel()
+
+		// calls abandoned by the caller say nothing about the health of elasticsearch
+		if ctx.Err() != nil {
+			rc.breaker.abandon()
+			return err
+		}
+		rc.breaker.record(isFailure(err))
+
+		if err == nil || attempt >= rc.cfg.MaxRetries || !isRetryable(err) {
+			return err
+		}
+		if sleepErr := rc.sleep(ctx, rc.backoff(attempt)); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// backoff returns the wait before the retry following the attempt, between half and all of the doubled backoff
+func (rc *ResilientClient) backoff(attempt int) time.Duration {
+	backoff := rc.cfg.RetryBackoff << attempt
+	return backoff/2 + time.Duration(rc.random()*float64(backoff/2))
+}
+
+// isFailure returns whether the error means elasticsearch is unavailable or failing, rather than the call being invalid
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	status := esError.ErrorStatus(err)
+	return status == 0 || status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// isRetryable returns whether elasticsearch rejected the call as it is overloaded
+func isRetryable(err error) bool {
+	status := esError.ErrorStatus(err)
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker trips open once the proportion of failed calls within a window crosses the error rate. Calls then
+// fail fast until the open duration has passed, when a single trial call decides whether to close or reopen it.
+type circuitBreaker struct {
+	mutex       sync.Mutex
+	cfg         ResilienceConfig
+	now         func() time.Time
+	state       breakerState
+	reopenAt    time.Time
+	trialling   bool
+	windowStart time.Time
+	requests    int
+	failures    int
+}
+
+func newCircuitBreaker(cfg ResilienceConfig, now func() time.Time) *circuitBreaker {
+	return &circuitBreaker{
+		cfg: cfg,
+		now: now,
+	}
+}
+
+// allow returns a CircuitOpenError if the call must fail fast, moving an open breaker to half open once it is time
+// for a trial call
+func (cb *circuitBreaker) allow() error {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.state == breakerOpen && !cb.now().Before(cb.reopenAt) {
+		cb.state = breakerHalfOpen
+	}
+
+	switch cb.state {
+	case breakerOpen:
+		return CircuitOpenError{RetryAfter: cb.reopenAt.Sub(cb.now())}
+	case breakerHalfOpen:
+		if cb.trialling {
+			return CircuitOpenError{RetryAfter: halfOpenRetryAfter}
+		}
+		cb.trialling = true
+	}
+	return nil
+}
+
+// record records the outcome of an allowed call
+func (cb *circuitBreaker) record(failed bool) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.now()
+	switch cb.state {
+	case breakerHalfOpen:
+		cb.trialling = false
+		if failed {
+			cb.open(now)
+			return
+		}
+		cb.close(now)
+	case breakerClosed:
+		if now.Sub(cb.windowStart) >= cb.cfg.BreakerWindow {
+			cb.resetWindow(now)
+		}
+		cb.requests++
+		if failed {
+			cb.failures++
+		}
+		if cb.cfg.BreakerErrorRate > 0 && cb.failures > 0 && cb.requests >= cb.cfg.BreakerMinRequests &&
+			float64(cb.failures)/float64(cb.requests) >= cb.cfg.BreakerErrorRate {
+			cb.open(now)
+		}
+	}
+}
+
+// abandon releases the trial of a half open breaker if its call was abandoned by the caller
+func (cb *circuitBreaker) abandon() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.trialling = false
+}
+
+// tripped returns whether calls are failing fast, and for how long
+func (cb *circuitBreaker) tripped() (time.Duration, bool) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := cb.now()
+	switch {
+	case cb.state == breakerOpen && now.Before(cb.reopenAt):
+		return cb.reopenAt.Sub(now), true
+	case cb.state == breakerHalfOpen && cb.trialling:
+		return halfOpenRetryAfter, true
+	default:
+		return 0, false
+	}
+}
+
+// status returns the state of the breaker, and how long until a trial call if it is open
+func (cb *circuitBreaker) status() (time.Duration, breakerState) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.state != breakerOpen {
+		return 0, cb.state
+	}
+	if retryAfter := cb.reopenAt.Sub(cb.now()); retryAfter > 0 {
+		return retryAfter, breakerOpen
+	}
+	return 0, breakerHalfOpen
+}
+
+func (cb *circuitBreaker) open(now time.Time) {
+	cb.state = breakerOpen
+	cb.reopenAt = now.Add(cb.cfg.BreakerOpenDuration)
+}
+
+func (cb *circuitBreaker) close(now time.Time) {
+	cb.state = breakerClosed
+	cb.resetWindow(now)
+}
+
+func (cb *circuitBreaker) resetWindow(now time.Time) {
+	cb.windowStart = now
+	cb.requests = 0
+	cb.failures = 0
+}
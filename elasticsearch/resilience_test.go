@@ -0,0 +1,204 @@
+package elasticsearch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	dpEsClient "github.com/ONSdigital/dp-elasticsearch/v3/client"
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+// fakeSearcher returns the next of its errors from every multi search, then succeeds
+type fakeSearcher struct {
+	Searcher
+	errs     []error
+	calls    int
+	deadline bool
+}
+
+func (fs *fakeSearcher) MultiSearch(ctx context.Context, searches []dpEsClient.Search, params *dpEsClient.QueryParams) ([]byte, error) {
+	_, fs.deadline = ctx.Deadline()
+	fs.calls++
+	if len(fs.errs) > 0 {
+		err := fs.errs[0]
+		fs.errs = fs.errs[1:]
+		return nil, err
+	}
+	return []byte(`{"responses":[]}`), nil
+}
+
+func statusErr(code int) error {
+	return esError.StatusError{Err: errors.New(http.StatusText(code)), Code: code}
+}
+
+func newTestResilientClient(searcher *fakeSearcher, now *time.Time) (*ResilientClient, *[]time.Duration) {
+	rc := NewResilientClient(searcher, ResilienceConfig{
+		Timeout:             time.Second,
+		MaxRetries:          2,
+		RetryBackoff:        100 * time.Millisecond,
+		BreakerErrorRate:    0.5,
+		BreakerMinRequests:  4,
+		BreakerWindow:       time.Minute,
+		BreakerOpenDuration: 30 * time.Second,
+	})
+	rc.breaker.now = func() time.Time { return *now }
+	rc.random = func() float64 { return 0.5 }
+
+	sleeps := &[]time.Duration{}
+	rc.sleep = func(ctx context.Context, d time.Duration) error {
+		*sleeps = append(*sleeps, d)
+		return nil
+	}
+	return rc, sleeps
+}
+
+func TestResilientClientRetries(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	c.Convey("Given a resilient client", t, func() {
+		searcher := &fakeSearcher{}
+		rc, sleeps := newTestResilientClient(searcher, &now)
+
+		c.Convey("When elasticsearch rejects a search as overloaded before succeeding", func() {
+			searcher.errs = []error{statusErr(http.StatusTooManyRequests), statusErr(http.StatusServiceUnavailable)}
+			data, err := rc.MultiSearch(context.Background(), nil, nil)
+
+			c.Convey("Then the search is retried with a jittered, doubling backoff", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(string(data), c.ShouldEqual, `{"responses":[]}`)
+				c.So(searcher.calls, c.ShouldEqual, 3)
+				c.So(*sleeps, c.ShouldResemble, []time.Duration{75 * time.Millisecond, 150 * time.Millisecond})
+			})
+
+			c.Convey("And every call is made with a timeout", func() {
+				c.So(searcher.deadline, c.ShouldBeTrue)
+			})
+		})
+
+		c.Convey("When elasticsearch keeps rejecting a search as overloaded", func() {
+			searcher.errs = []error{statusErr(http.StatusServiceUnavailable), statusErr(http.StatusServiceUnavailable), statusErr(http.StatusServiceUnavailable), statusErr(http.StatusServiceUnavailable)}
+			_, err := rc.MultiSearch(context.Background(), nil, nil)
+
+			c.Convey("Then the search is retried at most the maximum number of times", func() {
+				c.So(esError.ErrorStatus(err), c.ShouldEqual, http.StatusServiceUnavailable)
+				c.So(searcher.calls, c.ShouldEqual, 3)
+			})
+		})
+
+		c.Convey("When elasticsearch fails a search for another reason", func() {
+			searcher.errs = []error{statusErr(http.StatusBadRequest)}
+			_, err := rc.MultiSearch(context.Background(), nil, nil)
+
+			c.Convey("Then the search is not retried", func() {
+				c.So(esError.ErrorStatus(err), c.ShouldEqual, http.StatusBadRequest)
+				c.So(searcher.calls, c.ShouldEqual, 1)
+				c.So(*sleeps, c.ShouldBeEmpty)
+			})
+		})
+	})
+}
+
+func TestResilientClientCircuitBreaker(t *testing.T) {
+	c.Convey("Given a resilient client", t, func() {
+		now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		searcher := &fakeSearcher{}
+		rc, _ := newTestResilientClient(searcher, &now)
+		state := health.NewCheckState("Elasticsearch circuit breaker")
+
+		c.Convey("When fewer searches than the minimum have failed", func() {
+			searcher.errs = []error{statusErr(http.StatusInternalServerError), statusErr(http.StatusInternalServerError), statusErr(http.StatusInternalServerError)}
+			for i := 0; i < 3; i++ {
+				_, _ = rc.MultiSearch(context.Background(), nil, nil)
+			}
+
+			c.Convey("Then the breaker stays closed", func() {
+				_, tripped := rc.Tripped()
+				c.So(tripped, c.ShouldBeFalse)
+				c.So(rc.CircuitBreakerChecker(context.Background(), state), c.ShouldBeNil)
+				c.So(state.Status(), c.ShouldEqual, health.StatusOK)
+			})
+		})
+
+		c.Convey("When the error rate crosses the threshold", func() {
+			searcher.errs = []error{statusErr(http.StatusInternalServerError), nil, errors.New("connection refused"), statusErr(http.StatusBadRequest)}
+			for i := 0; i < 4; i++ {
+				_, _ = rc.MultiSearch(context.Background(), nil, nil)
+			}
+
+			c.Convey("Then searches fail fast without calling elasticsearch", func() {
+				_, err := rc.MultiSearch(context.Background(), nil, nil)
+				var openErr CircuitOpenError
+				c.So(errors.As(err, &openErr), c.ShouldBeTrue)
+				c.So(openErr.RetryAfter, c.ShouldEqual, 30*time.Second)
+				c.So(searcher.calls, c.ShouldEqual, 4)
+
+				retryAfter, tripped := rc.Tripped()
+				c.So(tripped, c.ShouldBeTrue)
+				c.So(retryAfter, c.ShouldEqual, 30*time.Second)
+			})
+
+			c.Convey("And the health check reports a warning", func() {
+				c.So(rc.CircuitBreakerChecker(context.Background(), state), c.ShouldBeNil)
+				c.So(state.Status(), c.ShouldEqual, health.StatusWarning)
+				c.So(state.Message(), c.ShouldEqual, "circuit breaker open, elasticsearch calls failing fast for 30s")
+			})
+
+			c.Convey("And once the open duration has passed, a successful trial search closes the breaker", func() {
+				now = now.Add(30 * time.Second)
+				_, err := rc.MultiSearch(context.Background(), nil, nil)
+				c.So(err, c.ShouldBeNil)
+
+				_, tripped := rc.Tripped()
+				c.So(tripped, c.ShouldBeFalse)
+				c.So(rc.CircuitBreakerChecker(context.Background(), state), c.ShouldBeNil)
+				c.So(state.Status(), c.ShouldEqual, health.StatusOK)
+			})
+
+			c.Convey("And once the open duration has passed, a failed trial search reopens the breaker", func() {
+				now = now.Add(30 * time.Second)
+				searcher.errs = []error{statusErr(http.StatusBadGateway)}
+				_, err := rc.MultiSearch(context.Background(), nil, nil)
+				c.So(esError.ErrorStatus(err), c.ShouldEqual, http.StatusBadGateway)
+
+				retryAfter, tripped := rc.Tripped()
+				c.So(tripped, c.ShouldBeTrue)
+				c.So(retryAfter, c.ShouldEqual, 30*time.Second)
+			})
+		})
+
+		c.Convey("When the error rate crosses the threshold only across windows", func() {
+			searcher.errs = []error{statusErr(http.StatusInternalServerError), statusErr(http.StatusInternalServerError), nil, nil}
+			for i := 0; i < 2; i++ {
+				_, _ = rc.MultiSearch(context.Background(), nil, nil)
+			}
+			now = now.Add(time.Minute)
+			for i := 0; i < 4; i++ {
+				_, _ = rc.MultiSearch(context.Background(), nil, nil)
+			}
+
+			c.Convey("Then the breaker stays closed", func() {
+				_, tripped := rc.Tripped()
+				c.So(tripped, c.ShouldBeFalse)
+			})
+		})
+
+		c.Convey("When searches are cancelled by the caller", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			searcher.errs = []error{context.Canceled, context.Canceled, context.Canceled, context.Canceled}
+			for i := 0; i < 4; i++ {
+				_, _ = rc.MultiSearch(ctx, nil, nil)
+			}
+
+			c.Convey("Then they are not counted as failures", func() {
+				_, tripped := rc.Tripped()
+				c.So(tripped, c.ShouldBeFalse)
+			})
+		})
+	})
+}
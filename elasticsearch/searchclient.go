@@ -0,0 +1,252 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	dpEsClient "github.com/ONSdigital/dp-elasticsearch/v3/client"
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	es710 "github.com/elastic/go-elasticsearch/v7"
+	"github.com/elastic/go-elasticsearch/v7/esapi"
+)
+
+// reopenIndexTimeout bounds reopening an index closed to update its analysis settings, and reloading its analysers
+const reopenIndexTimeout = time.Minute
+
+// SearchClient extends the dp-elasticsearch client with the elasticsearch APIs that it does not expose,
+// talking to the same cluster through the same transport. Searches and counts are also sent by this client, as
+// the dp-elasticsearch client retries them without backoff and ignores the context of counts, which would multiply
+// the retries made by a ResilientClient and escape its timeouts.
+type SearchClient struct {
+	dpEsClient.Client
+	esClient *es710.Client
+}
+
+// NewSearchClient wraps the provided dp-elasticsearch client, creating a go-elasticsearch client
+// for the same address and transport to serve the additional APIs
+func NewSearchClient(client dpEsClient.Client, esURL string, transport http.RoundTripper) (*SearchClient, error) {
+	parsedURL, err := url.ParseRequestURI(esURL)
+	if err != nil {
+		return nil, errors.New("failed to specify valid elasticsearch url")
+	}
+
+	esClient, err := es710.NewClient(es710.Config{
+		Addresses:    []string{parsedURL.String()},
+		Transport:    transport,
+		DisableRetry: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchClient{
+		Client:   client,
+		esClient: esClient,
+	}, nil
+}
+
+// MultiSearch runs the searches in a single request, returning total hits as an integer if requested
+func (cli *SearchClient) MultiSearch(ctx context.Context, searches []dpEsClient.Search, params *dpEsClient.QueryParams) ([]byte, error) {
+	var body []byte
+	for _, search := range searches {
+		header, err := json.Marshal(search.Header)
+		if err != nil {
+			return nil, err
+		}
+		body = append(body, header...)
+		body = append(body, '\n')
+		body = append(body, search.Query...)
+		body = append(body, '\n')
+	}
+
+	opts := []func(*esapi.MsearchRequest){
+		cli.esClient.Msearch.WithContext(ctx),
+	}
+	if params != nil && params.EnableTotalHitsCounter != nil {
+		opts = append(opts, cli.esClient.Msearch.WithRestTotalHitsAsInt(*params.EnableTotalHitsCounter))
+	}
+
+	res, err := cli.esClient.Msearch(bytes.NewReader(body), opts...)
+	return readResponse(res, err, "multi search documents")
+}
+
+// Count returns the number of documents matching the query
+func (cli *SearchClient) Count(ctx context.Context, count dpEsClient.Count) ([]byte, error) {
+	res, err := cli.esClient.Count(
+		cli.esClient.Count.WithContext(ctx),
+		cli.esClient.Count.WithBody(bytes.NewReader(count.Query)),
+	)
+	return readResponse(res, err, "count documents")
+}
+
+// OpenPointInTime opens a point in time on the provided indices, kept alive for the provided duration (e.g. "1m"),
+// and returns its ID
+func (cli *SearchClient) OpenPointInTime(ctx context.Context, indices []string, keepAlive string) (string, error) {
+	res, err := cli.esClient.OpenPointInTime(
+		cli.esClient.OpenPointInTime.WithContext(ctx),
+		cli.esClient.OpenPointInTime.WithIndex(indices...),
+		cli.esClient.OpenPointInTime.WithKeepAlive(keepAlive),
+	)
+	data, err := readResponse(res, err, "open point in time")
+	if err != nil {
+		return "", err
+	}
+
+	var pit struct {
+		ID string `json:"id"`
+	}
+	if err = json.Unmarshal(data, &pit); err != nil {
+		return "", esError.StatusError{Err: fmt.Errorf("failed to decode point in time response: %w", err), Code: res.StatusCode}
+	}
+
+	return pit.ID, nil
+}
+
+// ClosePointInTime releases the resources held by the point in time with the provided ID
+func (cli *SearchClient) ClosePointInTime(ctx context.Context, id string) error {
+	body, err := json.Marshal(map[string]string{"id": id})
+	if err != nil {
+		return err
+	}
+
+	res, err := cli.esClient.ClosePointInTime(
+		cli.esClient.ClosePointInTime.WithContext(ctx),
+		cli.esClient.ClosePointInTime.WithBody(bytes.NewReader(body)),
+	)
+	_, err = readResponse(res, err, "close point in time")
+	return err
+}
+
+// PointInTimeSearch runs a search query that contains a point in time. No index is given,
+// as elasticsearch resolves it from the point in time. Total hits are returned as an integer,
+// consistent with the multi search responses
+func (cli *SearchClient) PointInTimeSearch(ctx context.Context, query []byte) ([]byte, error) {
+	res, err := cli.esClient.Search(
+		cli.esClient.Search.WithContext(ctx),
+		cli.esClient.Search.WithBody(bytes.NewReader(query)),
+		cli.esClient.Search.WithTrackTotalHits(true),
+		cli.esClient.Search.WithRestTotalHitsAsInt(true),
+	)
+	return readResponse(res, err, "run point in time search")
+}
+
+// GetIndexSettings returns the settings of the provided index (or alias), keyed by index name
+func (cli *SearchClient) GetIndexSettings(ctx context.Context, index string) ([]byte, error) {
+	res, err := cli.esClient.Indices.GetSettings(
+		cli.esClient.Indices.GetSettings.WithContext(ctx),
+		cli.esClient.Indices.GetSettings.WithIndex(index),
+	)
+	return readResponse(res, err, "get index settings")
+}
+
+// aliasAction is an action of an elasticsearch _aliases request, applying an alias to, or removing it from, an index
+type aliasAction struct {
+	Index string `json:"index"`
+	Alias string `json:"alias"`
+}
+
+// UpdateAliases removes the alias from the indexes to remove and adds it to the indexes to add in a single, atomic
+// update. It replaces the dp-elasticsearch implementation, which joins several indexes into a single index name and
+// ignores elasticsearch errors, with one action per index.
+func (cli *SearchClient) UpdateAliases(ctx context.Context, alias string, removeIndices, addIndices []string) error {
+	actions := make([]map[string]aliasAction, 0, len(removeIndices)+len(addIndices))
+	for _, index := range removeIndices {
+		actions = append(actions, map[string]aliasAction{"remove": {Index: index, Alias: alias}})
+	}
+	for _, index := range addIndices {
+		actions = append(actions, map[string]aliasAction{"add": {Index: index, Alias: alias}})
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return err
+	}
+
+	res, err := cli.esClient.Indices.UpdateAliases(
+		bytes.NewReader(body),
+		cli.esClient.Indices.UpdateAliases.WithContext(ctx),
+	)
+	_, err = readResponse(res, err, "update aliases")
+	return err
+}
+
+// UpdateAnalysisSettings applies the provided analysis settings to an index. Analysis settings can only be changed
+// while an index is closed, so the index is closed for the update and always reopened afterwards, even if the provided
+// context is cancelled in between. Search analysers are then reloaded, so that updateable filters pick up their new
+// configuration.
+func (cli *SearchClient) UpdateAnalysisSettings(ctx context.Context, index string, settings []byte) (err error) {
+	res, err := cli.esClient.Indices.Close(
+		[]string{index},
+		cli.esClient.Indices.Close.WithContext(ctx),
+	)
+	if _, err = readResponse(res, err, "close index"); err != nil {
+		return err
+	}
+
+	defer func() {
+		reopenCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reopenIndexTimeout)
+		defer cancel()
+
+		res, openErr := cli.esClient.Indices.Open(
+			[]string{index},
+			cli.esClient.Indices.Open.WithContext(reopenCtx),
+			cli.esClient.Indices.Open.WithWaitForActiveShards("1"),
+		)
+		if _, openErr = readResponse(res, openErr, "open index"); openErr != nil && err == nil {
+			err = openErr
+		}
+		if err != nil {
+			return
+		}
+
+		res, err = cli.esClient.Indices.ReloadSearchAnalyzers(
+			[]string{index},
+			cli.esClient.Indices.ReloadSearchAnalyzers.WithContext(reopenCtx),
+		)
+		_, err = readResponse(res, err, "reload search analyzers")
+	}()
+
+	res, err = cli.esClient.Indices.PutSettings(
+		bytes.NewReader(settings),
+		cli.esClient.Indices.PutSettings.WithContext(ctx),
+		cli.esClient.Indices.PutSettings.WithIndex(index),
+	)
+	_, err = readResponse(res, err, "update index settings")
+	return err
+}
+
+// readResponse returns the body of a successful elasticsearch response, or a StatusError describing the failure
+func readResponse(res *esapi.Response, err error, action string) ([]byte, error) {
+	if err != nil {
+		return nil, esError.StatusError{Err: err, Code: statusCode(res)}
+	}
+	defer res.Body.Close()
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, esError.StatusError{Err: err, Code: res.StatusCode}
+	}
+
+	if res.IsError() {
+		return nil, esError.StatusError{
+			Err:  fmt.Errorf("error occurred while trying to %s: %s", action, string(data)),
+			Code: res.StatusCode,
+		}
+	}
+
+	return data, nil
+}
+
+func statusCode(res *esapi.Response) int {
+	if res == nil {
+		return 0
+	}
+	return res.StatusCode
+}
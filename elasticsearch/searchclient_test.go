@@ -0,0 +1,277 @@
+package elasticsearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	dpEsClient "github.com/ONSdigital/dp-elasticsearch/v3/client"
+	esError "github.com/ONSdigital/dp-elasticsearch/v3/errors"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newTestSearchClient(status int, body string, requests *[]*http.Request) *SearchClient {
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		*requests = append(*requests, req)
+		return &http.Response{
+			StatusCode: status,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	})
+
+	client, err := NewSearchClient(nil, "http://localhost:999", transport)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+func TestMultiSearchAndCount(t *testing.T) {
+	c.Convey("Given a search client", t, func() {
+		var requests []*http.Request
+
+		c.Convey("When a multi search is run", func() {
+			client := newTestSearchClient(http.StatusOK, `{"responses":[]}`, &requests)
+			enabled := true
+
+			res, err := client.MultiSearch(context.Background(), []dpEsClient.Search{
+				{Header: dpEsClient.Header{Index: "ons"}, Query: []byte(`{"query":{}}`)},
+			}, &dpEsClient.QueryParams{EnableTotalHitsCounter: &enabled})
+
+			c.Convey("Then the searches are sent as newline delimited JSON and the response is returned", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(string(res), c.ShouldEqual, `{"responses":[]}`)
+				c.So(requests, c.ShouldHaveLength, 1)
+				c.So(requests[0].URL.Path, c.ShouldEqual, "/_msearch")
+				c.So(requests[0].URL.Query().Get("rest_total_hits_as_int"), c.ShouldEqual, "true")
+				body, readErr := io.ReadAll(requests[0].Body)
+				c.So(readErr, c.ShouldBeNil)
+				c.So(string(body), c.ShouldEqual, "{\"index\":\"ons\"}\n{\"query\":{}}\n")
+			})
+		})
+
+		c.Convey("When a count is run", func() {
+			client := newTestSearchClient(http.StatusOK, `{"count":3}`, &requests)
+
+			res, err := client.Count(context.Background(), dpEsClient.Count{Query: []byte(`{"query":{}}`)})
+
+			c.Convey("Then the query is sent and the response is returned", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(string(res), c.ShouldEqual, `{"count":3}`)
+				c.So(requests, c.ShouldHaveLength, 1)
+				c.So(requests[0].URL.Path, c.ShouldEqual, "/_count")
+			})
+		})
+
+		c.Convey("When elasticsearch is unavailable", func() {
+			client := newTestSearchClient(http.StatusServiceUnavailable, `{"error":"unavailable"}`, &requests)
+
+			_, err := client.MultiSearch(context.Background(), []dpEsClient.Search{{Query: []byte(`{}`)}}, nil)
+
+			c.Convey("Then the search is not retried, leaving retries to the resilient client", func() {
+				c.So(esError.ErrorStatus(err), c.ShouldEqual, http.StatusServiceUnavailable)
+				c.So(requests, c.ShouldHaveLength, 1)
+			})
+		})
+	})
+}
+
+func TestPointInTime(t *testing.T) {
+	c.Convey("Given a search client", t, func() {
+		var requests []*http.Request
+
+		c.Convey("When a point in time is opened", func() {
+			client := newTestSearchClient(http.StatusOK, `{"id":"pit-id"}`, &requests)
+
+			id, err := client.OpenPointInTime(context.Background(), []string{"ons"}, "1m")
+
+			c.Convey("Then the point in time ID is returned", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(id, c.ShouldEqual, "pit-id")
+				c.So(requests, c.ShouldHaveLength, 1)
+				c.So(requests[0].Method, c.ShouldEqual, http.MethodPost)
+				c.So(requests[0].URL.Path, c.ShouldEqual, "/ons/_pit")
+				c.So(requests[0].URL.Query().Get("keep_alive"), c.ShouldEqual, "1m")
+			})
+		})
+
+		c.Convey("When a point in time is closed", func() {
+			client := newTestSearchClient(http.StatusOK, `{"succeeded":true}`, &requests)
+
+			err := client.ClosePointInTime(context.Background(), "pit-id")
+
+			c.Convey("Then the point in time ID is sent in the body", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(requests, c.ShouldHaveLength, 1)
+				c.So(requests[0].Method, c.ShouldEqual, http.MethodDelete)
+				c.So(requests[0].URL.Path, c.ShouldEqual, "/_pit")
+				body, readErr := io.ReadAll(requests[0].Body)
+				c.So(readErr, c.ShouldBeNil)
+				c.So(string(body), c.ShouldEqual, `{"id":"pit-id"}`)
+			})
+		})
+
+		c.Convey("When a point in time search is run", func() {
+			client := newTestSearchClient(http.StatusOK, `{"pit_id":"pit-id","hits":{"total":1}}`, &requests)
+
+			res, err := client.PointInTimeSearch(context.Background(), []byte(`{"pit":{"id":"pit-id"}}`))
+
+			c.Convey("Then the query is sent without an index and the response is returned", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(string(res), c.ShouldEqual, `{"pit_id":"pit-id","hits":{"total":1}}`)
+				c.So(requests, c.ShouldHaveLength, 1)
+				c.So(requests[0].URL.Path, c.ShouldEqual, "/_search")
+				c.So(requests[0].URL.Query().Get("rest_total_hits_as_int"), c.ShouldEqual, "true")
+			})
+		})
+
+		c.Convey("When elasticsearch responds with an error", func() {
+			client := newTestSearchClient(http.StatusNotFound, `{"error":"search_context_missing_exception"}`, &requests)
+
+			_, err := client.PointInTimeSearch(context.Background(), []byte(`{}`))
+
+			c.Convey("Then a status error is returned", func() {
+				c.So(err, c.ShouldNotBeNil)
+				c.So(esError.ErrorStatus(err), c.ShouldEqual, http.StatusNotFound)
+			})
+		})
+	})
+}
+
+func TestIndexSettings(t *testing.T) {
+	c.Convey("Given a search client", t, func() {
+		var requests []*http.Request
+
+		c.Convey("When the settings of an index are requested", func() {
+			client := newTestSearchClient(http.StatusOK, `{"ons1":{"settings":{}}}`, &requests)
+
+			res, err := client.GetIndexSettings(context.Background(), "ons")
+
+			c.Convey("Then the settings are returned", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(string(res), c.ShouldEqual, `{"ons1":{"settings":{}}}`)
+				c.So(requests, c.ShouldHaveLength, 1)
+				c.So(requests[0].Method, c.ShouldEqual, http.MethodGet)
+				c.So(requests[0].URL.Path, c.ShouldEqual, "/ons/_settings")
+			})
+		})
+
+		c.Convey("When the analysis settings of an index are updated", func() {
+			client := newTestSearchClient(http.StatusOK, `{"acknowledged":true}`, &requests)
+
+			err := client.UpdateAnalysisSettings(context.Background(), "ons1", []byte(`{"analysis":{}}`))
+
+			c.Convey("Then the index is closed, updated, reopened and its search analysers reloaded", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(requests, c.ShouldHaveLength, 4)
+				c.So(requests[0].URL.Path, c.ShouldEqual, "/ons1/_close")
+				c.So(requests[1].Method, c.ShouldEqual, http.MethodPut)
+				c.So(requests[1].URL.Path, c.ShouldEqual, "/ons1/_settings")
+				c.So(requests[2].URL.Path, c.ShouldEqual, "/ons1/_open")
+				c.So(requests[3].URL.Path, c.ShouldEqual, "/ons1/_reload_search_analyzers")
+			})
+		})
+
+		c.Convey("When elasticsearch rejects the analysis settings", func() {
+			transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				requests = append(requests, req)
+				status := http.StatusOK
+				if req.Method == http.MethodPut {
+					status = http.StatusBadRequest
+				}
+				return &http.Response{
+					StatusCode: status,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{}`)),
+				}, nil
+			})
+			client, err := NewSearchClient(nil, "http://localhost:999", transport)
+			c.So(err, c.ShouldBeNil)
+
+			err = client.UpdateAnalysisSettings(context.Background(), "ons1", []byte(`{"analysis":{}}`))
+
+			c.Convey("Then the error is returned and the index is still reopened", func() {
+				c.So(esError.ErrorStatus(err), c.ShouldEqual, http.StatusBadRequest)
+				c.So(requests, c.ShouldHaveLength, 3)
+				c.So(requests[2].URL.Path, c.ShouldEqual, "/ons1/_open")
+			})
+		})
+
+		c.Convey("When the context is cancelled once the index is closed", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			var reopenCtxErr error
+			transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				requests = append(requests, req)
+				switch req.URL.Path {
+				case "/ons1/_close":
+					cancel()
+				case "/ons1/_open":
+					reopenCtxErr = req.Context().Err()
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{}`)),
+				}, nil
+			})
+			client, err := NewSearchClient(nil, "http://localhost:999", transport)
+			c.So(err, c.ShouldBeNil)
+
+			_ = client.UpdateAnalysisSettings(ctx, "ons1", []byte(`{"analysis":{}}`))
+
+			c.Convey("Then the index is still reopened", func() {
+				var paths []string
+				for _, req := range requests {
+					paths = append(paths, req.URL.Path)
+				}
+				c.So(paths, c.ShouldContain, "/ons1/_open")
+				c.So(reopenCtxErr, c.ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestUpdateAliases(t *testing.T) {
+	c.Convey("Given a search client", t, func() {
+		var requests []*http.Request
+
+		c.Convey("When an alias held by two indexes is moved to another index", func() {
+			client := newTestSearchClient(http.StatusOK, `{"acknowledged":true}`, &requests)
+
+			err := client.UpdateAliases(context.Background(), "ons", []string{"ons1", "ons2"}, []string{"ons3"})
+
+			c.Convey("Then the alias is removed from each index and added to the other in a single update", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(requests, c.ShouldHaveLength, 1)
+				c.So(requests[0].Method, c.ShouldEqual, http.MethodPost)
+				c.So(requests[0].URL.Path, c.ShouldEqual, "/_aliases")
+				body, readErr := io.ReadAll(requests[0].Body)
+				c.So(readErr, c.ShouldBeNil)
+				c.So(string(body), c.ShouldEqual, `{"actions":[`+
+					`{"remove":{"index":"ons1","alias":"ons"}},`+
+					`{"remove":{"index":"ons2","alias":"ons"}},`+
+					`{"add":{"index":"ons3","alias":"ons"}}]}`)
+			})
+		})
+
+		c.Convey("When elasticsearch rejects the update", func() {
+			client := newTestSearchClient(http.StatusNotFound, `{"error":{"type":"index_not_found_exception"}}`, &requests)
+
+			err := client.UpdateAliases(context.Background(), "ons", []string{"ons1"}, []string{"ons3"})
+
+			c.Convey("Then the error is returned", func() {
+				c.So(esError.ErrorStatus(err), c.ShouldEqual, http.StatusNotFound)
+			})
+		})
+	})
+}
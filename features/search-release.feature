@@ -36,4 +36,4 @@ Feature: search/releases endpoint should return data for various combinations of
     And elasticsearch returns internal server error
     When I GET "/search/releases?q=Education+in+Scotland&response=2020-01-01&toDate=2020-12-31&release-type=type-published"
     Then the HTTP status code should be "500"
-    And the response header "Content-Type" should be "text/plain; charset=utf-8"
+    And the response header "Content-Type" should be "application/json;charset=utf-8"
@@ -23,10 +23,10 @@ Feature: Search by URIs endpoint should return data for requested search paramet
       }
       """
     Then the HTTP status code should be "400"
-    And the response header "Content-Type" should be "text/plain; charset=utf-8"
-    And I should receive the following response:
+    And the response header "Content-Type" should be "application/json;charset=utf-8"
+    And I should receive the following JSON response:
       """
-        No URIs provided
+      {"errors": [{"code": "invalid_body", "description": "No URIs provided"}]}
       """
 
   Scenario: When searching with invalid URI format, I get a bad request response
@@ -39,10 +39,10 @@ Feature: Search by URIs endpoint should return data for requested search paramet
       }
       """
     Then the HTTP status code should be "400"
-    And the response header "Content-Type" should be "text/plain; charset=utf-8"
-    And I should receive the following response:
+    And the response header "Content-Type" should be "application/json;charset=utf-8"
+    And I should receive the following JSON response:
       """
-        Invalid URI: URI cannot be empty
+      {"errors": [{"code": "invalid_body", "description": "Invalid URI: URI cannot be empty"}]}
       """
 
   Scenario: When Elasticsearch returns an error, I get an internal server error
@@ -54,8 +54,8 @@ Feature: Search by URIs endpoint should return data for requested search paramet
       }
       """
     Then the HTTP status code should be "500"
-    And the response header "Content-Type" should be "text/plain; charset=utf-8"
-    And I should receive the following response:
+    And the response header "Content-Type" should be "application/json;charset=utf-8"
+    And I should receive the following JSON response:
       """
-        call to elastic multisearch api failed
+      {"errors": [{"code": "search_failed", "description": "call to elastic multisearch api failed"}]}
       """
@@ -20,19 +20,19 @@ Feature: Search endpoint should return data for requested cdid parameter
         Given elasticsearch is healthy
         When I GET "/search?cdids=INVALID"
         Then the HTTP status code should be "400"
-        And the response header "Content-Type" should be "text/plain; charset=utf-8"
-        And I should receive the following response:
+        And the response header "Content-Type" should be "application/json;charset=utf-8"
+        And I should receive the following JSON response:
             """
-            invalid cdid(s) found
+            {"errors": [{"code": "invalid_parameter", "description": "invalid cdid(s) found", "param": "cdids", "value": "INVALID"}]}
             """
     Scenario: When Searching with multiple invalid cdids I get a bad request response
         Given elasticsearch is healthy
         When I GET "/search?cdids=INVALID1,INVALID2"
         Then the HTTP status code should be "400"
-        And the response header "Content-Type" should be "text/plain; charset=utf-8"
-        And I should receive the following response:
+        And the response header "Content-Type" should be "application/json;charset=utf-8"
+        And I should receive the following JSON response:
             """
-            invalid cdid(s) found
+            {"errors": [{"code": "invalid_parameter", "description": "invalid cdid(s) found", "param": "cdids", "value": "INVALID1,INVALID2"}]}
             """
 
     Scenario: When Searching with a valid cdid but no matches I get zero results
@@ -12,10 +12,10 @@ Feature: Search endpoint should return data for requested search parameter
           Given elasticsearch is healthy
           When I GET "/search?dataset_ids=q"
           Then the HTTP status code should be "400"
-          And the response header "Content-Type" should be "text/plain; charset=utf-8"
-          And I should receive the following response:
+          And the response header "Content-Type" should be "application/json;charset=utf-8"
+          And I should receive the following JSON response:
             """
-            invalid dataset_ids: q
+            {"errors": [{"code": "invalid_parameter", "description": "invalid dataset_ids: q", "param": "dataset_ids", "value": "q"}]}
             """
 
 
@@ -23,10 +23,10 @@ Feature: Search endpoint should return data for requested search parameter
           Given elasticsearch is healthy
           When I GET "/search?dataset_ids=TS056,d"
           Then the HTTP status code should be "400"
-          And the response header "Content-Type" should be "text/plain; charset=utf-8"
-          And I should receive the following response:
+          And the response header "Content-Type" should be "application/json;charset=utf-8"
+          And I should receive the following JSON response:
             """
-            invalid dataset_ids: d
+            {"errors": [{"code": "invalid_parameter", "description": "invalid dataset_ids: d", "param": "dataset_ids", "value": "TS056,d"}]}
             """
 
 
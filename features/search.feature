@@ -28,16 +28,16 @@ Feature: Search endpoint should return data for requested search parameter
         And elasticsearch returns internal server error
         When I GET "/search?q=CPI"
         Then the HTTP status code should be "500"
-        And the response header "Content-Type" should be "text/plain; charset=utf-8"
+        And the response header "Content-Type" should be "application/json;charset=utf-8"
 
     Scenario: When Searching with invalid characters I get a bad request response
         Given elasticsearch is healthy
         When I GET "/search?q=tiktok%E6%80%8E%E4%B9%88%E5%BC%80%E9"
         Then the HTTP status code should be "400"
-        And the response header "Content-Type" should be "text/plain; charset=utf-8"
-        And I should receive the following response:
+        And the response header "Content-Type" should be "application/json;charset=utf-8"
+        And I should receive the following JSON response:
             """
-            invalid characters in query
+            {"errors": [{"code": "invalid_parameter", "description": "invalid characters in query", "param": "q", "value": "tiktok怎么开\ufffd"}]}
             """
 
     Scenario: When Searching with a valid uri_prefix I get multiple results
@@ -52,10 +52,10 @@ Feature: Search endpoint should return data for requested search parameter
         Given elasticsearch is healthy
         When I GET "/search?uri_prefix=economy"
         Then the HTTP status code should be "400"
-        And the response header "Content-Type" should be "text/plain; charset=utf-8"
-        And I should receive the following response:
+        And the response header "Content-Type" should be "application/json;charset=utf-8"
+        And I should receive the following JSON response:
             """
-            invalid URI prefix parameter
+            {"errors": [{"code": "invalid_parameter", "description": "invalid URI prefix parameter", "param": "uri_prefix", "value": "economy"}]}
             """
 
     Scenario: When Searching with whitelisted special characters I get the expected results
@@ -0,0 +1,169 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The media types of the feed formats
+const (
+	AtomContentType = "application/atom+xml"
+	RSSContentType  = "application/rss+xml"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+// Feed is a feed of entries, which can be marshalled as Atom (RFC 4287) or RSS 2.0
+type Feed struct {
+	// ID is the permanent, unique identifier of the feed, which is also its self link
+	ID    string
+	Title string
+	// Link is the web page that the feed is an alternative to
+	Link string
+	// Updated is the last time any entry was updated
+	Updated time.Time
+	Entries []Entry
+}
+
+// Entry is an entry in a feed
+type Entry struct {
+	// ID is the permanent, unique identifier of the entry, which is also its link
+	ID        string
+	Title     string
+	Summary   string
+	Published time.Time
+	Updated   time.Time
+	// Categories are the content type, topic or other terms that the entry is filed under
+	Categories []string
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// Atom returns the feed as an Atom document
+func (f *Feed) Atom() ([]byte, error) {
+	feed := atomFeed{
+		Xmlns:   atomNamespace,
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.ID, Rel: "self", Type: AtomContentType},
+			{Href: f.Link, Rel: "alternate", Type: "text/html"},
+		},
+		Entries: make([]atomEntry, len(f.Entries)),
+	}
+
+	for i := range f.Entries {
+		entry := &f.Entries[i]
+		feed.Entries[i] = atomEntry{
+			ID:      entry.ID,
+			Title:   entry.Title,
+			Link:    atomLink{Href: entry.ID, Rel: "alternate"},
+			Updated: entry.Updated.UTC().Format(time.RFC3339),
+			Summary: entry.Summary,
+		}
+		if !entry.Published.IsZero() {
+			feed.Entries[i].Published = entry.Published.UTC().Format(time.RFC3339)
+		}
+		for _, category := range entry.Categories {
+			feed.Entries[i].Categories = append(feed.Entries[i].Categories, atomCategory{Term: category})
+		}
+	}
+
+	return marshal(feed)
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description,omitempty"`
+	PubDate     string   `xml:"pubDate"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS returns the feed as an RSS 2.0 document. RSS items only have a publication date, which is set to the time the
+// entry was updated so that feed readers notice changes.
+func (f *Feed) RSS() ([]byte, error) {
+	channel := rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Title,
+		LastBuildDate: f.Updated.UTC().Format(time.RFC1123Z),
+		Self:          atomLink{Href: f.ID, Rel: "self", Type: RSSContentType},
+		Items:         make([]rssItem, len(f.Entries)),
+	}
+
+	for i := range f.Entries {
+		entry := &f.Entries[i]
+		channel.Items[i] = rssItem{
+			Title:       entry.Title,
+			Link:        entry.ID,
+			GUID:        rssGUID{IsPermaLink: true, Value: entry.ID},
+			Description: entry.Summary,
+			PubDate:     entry.Updated.UTC().Format(time.RFC1123Z),
+			Categories:  entry.Categories,
+		}
+	}
+
+	return marshal(rssDocument{Version: "2.0", Atom: atomNamespace, Channel: channel})
+}
+
+func marshal(document interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal feed")
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,88 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func testFeed() *Feed {
+	return &Feed{
+		ID:      "http://localhost:23900/search/feed?q=inflation",
+		Title:   `ONS search results for "inflation"`,
+		Link:    "https://www.ons.gov.uk/search?q=inflation",
+		Updated: time.Date(2024, 1, 17, 7, 0, 0, 0, time.UTC),
+		Entries: []Entry{{
+			ID:         "https://www.ons.gov.uk/economy/inflationandpriceindices/bulletins/consumerpriceinflation/december2023",
+			Title:      "Consumer price inflation, UK: December 2023",
+			Summary:    "Price indices & percentage changes",
+			Published:  time.Date(2024, 1, 17, 7, 0, 0, 0, time.UTC),
+			Updated:    time.Date(2024, 1, 17, 7, 0, 0, 0, time.UTC),
+			Categories: []string{"bulletin"},
+		}},
+	}
+}
+
+func TestAtom(t *testing.T) {
+	c.Convey("Given a feed with an entry", t, func() {
+		f := testFeed()
+
+		c.Convey("When it is marshalled as Atom", func() {
+			data, err := f.Atom()
+
+			c.Convey("Then an Atom document is returned, with its text escaped", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(string(data), c.ShouldEqual, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <id>http://localhost:23900/search/feed?q=inflation</id>
+  <title>ONS search results for &#34;inflation&#34;</title>
+  <updated>2024-01-17T07:00:00Z</updated>
+  <link href="http://localhost:23900/search/feed?q=inflation" rel="self" type="application/atom+xml"></link>
+  <link href="https://www.ons.gov.uk/search?q=inflation" rel="alternate" type="text/html"></link>
+  <entry>
+    <id>https://www.ons.gov.uk/economy/inflationandpriceindices/bulletins/consumerpriceinflation/december2023</id>
+    <title>Consumer price inflation, UK: December 2023</title>
+    <link href="https://www.ons.gov.uk/economy/inflationandpriceindices/bulletins/consumerpriceinflation/december2023" rel="alternate"></link>
+    <published>2024-01-17T07:00:00Z</published>
+    <updated>2024-01-17T07:00:00Z</updated>
+    <summary>Price indices &amp; percentage changes</summary>
+    <category term="bulletin"></category>
+  </entry>
+</feed>`)
+			})
+		})
+	})
+}
+
+func TestRSS(t *testing.T) {
+	c.Convey("Given a feed with an entry", t, func() {
+		f := testFeed()
+
+		c.Convey("When it is marshalled as RSS", func() {
+			data, err := f.RSS()
+
+			c.Convey("Then an RSS document is returned, with items published when they were updated", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(string(data), c.ShouldEqual, `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>ONS search results for &#34;inflation&#34;</title>
+    <link>https://www.ons.gov.uk/search?q=inflation</link>
+    <description>ONS search results for &#34;inflation&#34;</description>
+    <lastBuildDate>Wed, 17 Jan 2024 07:00:00 +0000</lastBuildDate>
+    <atom:link href="http://localhost:23900/search/feed?q=inflation" rel="self" type="application/rss+xml"></atom:link>
+    <item>
+      <title>Consumer price inflation, UK: December 2023</title>
+      <link>https://www.ons.gov.uk/economy/inflationandpriceindices/bulletins/consumerpriceinflation/december2023</link>
+      <guid isPermaLink="true">https://www.ons.gov.uk/economy/inflationandpriceindices/bulletins/consumerpriceinflation/december2023</guid>
+      <description>Price indices &amp; percentage changes</description>
+      <pubDate>Wed, 17 Jan 2024 07:00:00 +0000</pubDate>
+      <category>bulletin</category>
+    </item>
+  </channel>
+</rss>`)
+			})
+		})
+	})
+}
@@ -0,0 +1,86 @@
+package feedback
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/ONSdigital/dp-search-api/models"
+	"github.com/pkg/errors"
+)
+
+// Sink stores search feedback events
+type Sink interface {
+	Record(ctx context.Context, event *models.FeedbackEvent) error
+	Close(ctx context.Context) error
+}
+
+// FileSink appends feedback events to a file as newline delimited JSON
+type FileSink struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewFileSink opens the file at the provided path, creating it if it does not exist, to append feedback events to
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open feedback file")
+	}
+
+	return &FileSink{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// Record appends the event to the file as a line of JSON
+func (s *FileSink) Record(_ context.Context, event *models.FeedbackEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.encoder.Encode(event); err != nil {
+		return errors.Wrap(err, "failed to write feedback event")
+	}
+	return nil
+}
+
+// Close closes the file
+func (s *FileSink) Close(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// MemorySink holds feedback events in memory, for use in tests
+type MemorySink struct {
+	mu     sync.Mutex
+	events []models.FeedbackEvent
+}
+
+// NewMemorySink returns an empty in-memory sink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+// Record stores a copy of the event
+func (s *MemorySink) Record(_ context.Context, event *models.FeedbackEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, *event)
+	return nil
+}
+
+// Close does nothing, as there are no resources to release
+func (s *MemorySink) Close(_ context.Context) error {
+	return nil
+}
+
+// Events returns the events recorded so far, in the order they were recorded
+func (s *MemorySink) Events() []models.FeedbackEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]models.FeedbackEvent{}, s.events...)
+}
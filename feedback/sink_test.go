@@ -0,0 +1,60 @@
+package feedback
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ONSdigital/dp-search-api/models"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFileSink(t *testing.T) {
+	c.Convey("Given a file sink", t, func() {
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "feedback.ndjson")
+		sink, err := NewFileSink(path)
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("When events are recorded", func() {
+			timestamp := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+			c.So(sink.Record(ctx, &models.FeedbackEvent{Type: models.FeedbackImpression, RequestID: "r1", Query: "cpi", URIs: []string{"/a", "/b"}, Position: 1, Timestamp: timestamp}), c.ShouldBeNil)
+			c.So(sink.Record(ctx, &models.FeedbackEvent{Type: models.FeedbackClick, RequestID: "r1", Query: "cpi", URI: "/b", Position: 2, Timestamp: timestamp}), c.ShouldBeNil)
+			c.So(sink.Close(ctx), c.ShouldBeNil)
+
+			c.Convey("Then each event is appended to the file as a line of JSON", func() {
+				data, err := os.ReadFile(path)
+				c.So(err, c.ShouldBeNil)
+				lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+				c.So(lines, c.ShouldHaveLength, 2)
+
+				var click models.FeedbackEvent
+				c.So(json.Unmarshal([]byte(lines[1]), &click), c.ShouldBeNil)
+				c.So(click, c.ShouldResemble, models.FeedbackEvent{Type: "click", RequestID: "r1", Query: "cpi", URI: "/b", Position: 2, Timestamp: timestamp})
+			})
+
+			c.Convey("And events recorded by a new sink are appended to the existing events", func() {
+				sink, err = NewFileSink(path)
+				c.So(err, c.ShouldBeNil)
+				c.So(sink.Record(ctx, &models.FeedbackEvent{Type: models.FeedbackClick, RequestID: "r2"}), c.ShouldBeNil)
+				c.So(sink.Close(ctx), c.ShouldBeNil)
+
+				data, err := os.ReadFile(path)
+				c.So(err, c.ShouldBeNil)
+				c.So(strings.Count(string(data), "\n"), c.ShouldEqual, 3)
+			})
+		})
+	})
+
+	c.Convey("Given a file in a directory that does not exist", t, func() {
+		_, err := NewFileSink(filepath.Join(t.TempDir(), "missing", "feedback.ndjson"))
+
+		c.Convey("Then the sink cannot be created", func() {
+			c.So(err, c.ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,115 @@
+package icalendar
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of an iCalendar object
+const ContentType = "text/calendar"
+
+// maxLineLength is the length in octets, excluding the line break, that longer content lines are folded at
+const maxLineLength = 75
+
+// dateTimeFormat is the format of a date and time in UTC
+const dateTimeFormat = "20060102T150405Z"
+
+// The statuses of an event
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar is an iCalendar object (RFC 5545) publishing a set of events
+type Calendar struct {
+	// ProdID identifies the product that created the calendar
+	ProdID string
+	// Name is the name that calendar clients show for the calendar, if set
+	Name   string
+	Events []Event
+}
+
+// Event is a calendar event. Clients update an event they already have with the same UID, rather than adding
+// another, if its Sequence has increased.
+type Event struct {
+	UID         string
+	Stamp       time.Time
+	Start       time.Time
+	Summary     string
+	Description string
+	URL         string
+	Status      string
+	Sequence    int
+}
+
+// Marshal returns the calendar in the iCalendar format
+func (c *Calendar) Marshal() []byte {
+	var buf bytes.Buffer
+
+	writeLine(&buf, "BEGIN", "VCALENDAR")
+	writeLine(&buf, "VERSION", "2.0")
+	writeLine(&buf, "PRODID", c.ProdID)
+	writeLine(&buf, "CALSCALE", "GREGORIAN")
+	writeLine(&buf, "METHOD", "PUBLISH")
+	if c.Name != "" {
+		writeLine(&buf, "X-WR-CALNAME", escapeText(c.Name))
+	}
+
+	for i := range c.Events {
+		c.Events[i].write(&buf)
+	}
+
+	writeLine(&buf, "END", "VCALENDAR")
+	return buf.Bytes()
+}
+
+func (e *Event) write(buf *bytes.Buffer) {
+	writeLine(buf, "BEGIN", "VEVENT")
+	writeLine(buf, "UID", e.UID)
+	writeLine(buf, "DTSTAMP", e.Stamp.UTC().Format(dateTimeFormat))
+	writeLine(buf, "DTSTART", e.Start.UTC().Format(dateTimeFormat))
+	writeLine(buf, "SEQUENCE", strconv.Itoa(e.Sequence))
+	writeLine(buf, "SUMMARY", escapeText(e.Summary))
+	if e.Description != "" {
+		writeLine(buf, "DESCRIPTION", escapeText(e.Description))
+	}
+	if e.URL != "" {
+		writeLine(buf, "URL", e.URL)
+	}
+	if e.Status != "" {
+		writeLine(buf, "STATUS", e.Status)
+	}
+	writeLine(buf, "END", "VEVENT")
+}
+
+// textEscaper escapes the characters that have a meaning in text property values
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeText(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// writeLine writes a content line, folding it onto continuation lines that start with a space once it is longer than
+// the maximum line length, without splitting a UTF-8 character
+func writeLine(buf *bytes.Buffer, name, value string) {
+	line := name + ":" + value
+
+	limit := maxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space, which counts towards their length
+		limit = maxLineLength - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -0,0 +1,77 @@
+package icalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMarshal(t *testing.T) {
+	c.Convey("Given a calendar with an event", t, func() {
+		calendar := &Calendar{
+			ProdID: "-//ONS//dp-search-api//EN",
+			Name:   "ONS releases",
+			Events: []Event{{
+				UID:         "1234@ons.gov.uk",
+				Stamp:       time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+				Start:       time.Date(2024, 1, 16, 7, 0, 0, 0, time.UTC),
+				Summary:     "Labour market overview, UK; January 2024",
+				Description: "Estimates of employment,\nunemployment",
+				URL:         "https://www.ons.gov.uk/releases/labourmarket",
+				Status:      StatusConfirmed,
+				Sequence:    1,
+			}},
+		}
+
+		c.Convey("When it is marshalled", func() {
+			data := string(calendar.Marshal())
+
+			c.Convey("Then it is written in the iCalendar format, with text values escaped", func() {
+				c.So(data, c.ShouldEqual, strings.Join([]string{
+					"BEGIN:VCALENDAR",
+					"VERSION:2.0",
+					"PRODID:-//ONS//dp-search-api//EN",
+					"CALSCALE:GREGORIAN",
+					"METHOD:PUBLISH",
+					"X-WR-CALNAME:ONS releases",
+					"BEGIN:VEVENT",
+					"UID:1234@ons.gov.uk",
+					"DTSTAMP:20240102T090000Z",
+					"DTSTART:20240116T070000Z",
+					"SEQUENCE:1",
+					`SUMMARY:Labour market overview\, UK\; January 2024`,
+					`DESCRIPTION:Estimates of employment\,\nunemployment`,
+					"URL:https://www.ons.gov.uk/releases/labourmarket",
+					"STATUS:CONFIRMED",
+					"END:VEVENT",
+					"END:VCALENDAR",
+					"",
+				}, "\r\n"))
+			})
+		})
+	})
+
+	c.Convey("Given an event with a long summary", t, func() {
+		calendar := &Calendar{Events: []Event{{Summary: strings.Repeat("é", 80)}}}
+
+		c.Convey("When it is marshalled", func() {
+			data := string(calendar.Marshal())
+
+			c.Convey("Then the line is folded without splitting a character", func() {
+				start := strings.Index(data, "SUMMARY:")
+				end := strings.Index(data, "END:VEVENT")
+				lines := strings.Split(strings.TrimSuffix(data[start:end], "\r\n"), "\r\n")
+				c.So(lines, c.ShouldHaveLength, 3)
+				for i, line := range lines {
+					c.So(len(line), c.ShouldBeLessThanOrEqualTo, 75)
+					if i > 0 {
+						c.So(line, c.ShouldStartWith, " ")
+					}
+				}
+				c.So(strings.ReplaceAll(data[start:end], "\r\n ", ""), c.ShouldEqual, "SUMMARY:"+strings.Repeat("é", 80)+"\r\n")
+			})
+		})
+	})
+}
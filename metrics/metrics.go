@@ -0,0 +1,260 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+// contentType is the content type of the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds of histogram buckets suited to request latencies, in seconds
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// labelSeparator joins label values into the key of a series, and cannot appear in a valid label value
+const labelSeparator = "\xff"
+
+// Registry holds the metrics of the service and serves them in the Prometheus text exposition format
+type Registry struct {
+	mutex   sync.Mutex
+	metrics map[string]metric
+}
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{metrics: map[string]metric{}}
+}
+
+// NewCounter registers a counter with the provided name and label names. Registering a counter with the name of an
+// existing counter returns the existing counter, so that handlers registered more than once share their metrics.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existing, ok := r.metrics[name].(*Counter); ok {
+		return existing
+	}
+
+	counter := &Counter{name: name, help: help, labels: labels, values: map[string]float64{}}
+	r.metrics[name] = counter
+	return counter
+}
+
+// NewHistogram registers a histogram with the provided name, bucket upper bounds, in increasing order, and label names.
+// Registering a histogram with the name of an existing histogram returns the existing histogram.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if existing, ok := r.metrics[name].(*Histogram); ok {
+		return existing
+	}
+
+	histogram := &Histogram{name: name, help: help, buckets: buckets, labels: labels, series: map[string]*histogramSeries{}}
+	r.metrics[name] = histogram
+	return histogram
+}
+
+// ServeHTTP writes every metric, in name order
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mutex.Lock()
+	names := make([]string, 0, len(r.metrics))
+	for name := range r.metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	metrics := make([]metric, len(names))
+	for i, name := range names {
+		metrics[i] = r.metrics[name]
+	}
+	r.mutex.Unlock()
+
+	w.Header().Set("Content-Type", contentType)
+	buf := bufio.NewWriter(w)
+	for _, m := range metrics {
+		m.write(buf)
+	}
+	if err := buf.Flush(); err != nil {
+		log.Error(req.Context(), "writing metrics failed", err)
+	}
+}
+
+// Counter is a metric that only increases, with a value for every combination of its label values
+type Counter struct {
+	mutex  sync.Mutex
+	name   string
+	help   string
+	labels []string
+	values map[string]float64
+}
+
+// Inc adds one to the counter with the label values, which are given in the order of the counter's label names
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds the value to the counter with the label values. Negative values are ignored, as counters cannot decrease,
+// and nothing is recorded by a nil counter, so that metrics are optional.
+func (c *Counter) Add(value float64, labelValues ...string) {
+	if c == nil || value < 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.values[strings.Join(labelValues, labelSeparator)] += value
+}
+
+// Value returns the value of the counter with the label values
+func (c *Counter) Value(labelValues ...string) float64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.values[strings.Join(labelValues, labelSeparator)]
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	writeHeader(w, c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, key), formatValue(c.values[key]))
+	}
+}
+
+// Histogram is a metric that counts observed values in buckets, with a series for every combination of its label values
+type Histogram struct {
+	mutex   sync.Mutex
+	name    string
+	help    string
+	buckets []float64
+	labels  []string
+	series  map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	// counts holds the number of observations in each bucket, followed by those above the last bucket
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Observe records the value in the histogram with the label values. Nothing is recorded by a nil histogram.
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	if h == nil {
+		return
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	key := strings.Join(labelValues, labelSeparator)
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{counts: make([]uint64, len(h.buckets)+1)}
+		h.series[key] = series
+	}
+
+	series.counts[sort.SearchFloat64s(h.buckets, value)]++
+	series.sum += value
+	series.count++
+}
+
+// Count returns the number of values observed by the histogram with the label values
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if series, ok := h.series[strings.Join(labelValues, labelSeparator)]; ok {
+		return series.count
+	}
+	return 0
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	writeHeader(w, h.name, h.help, "histogram")
+	for _, key := range keys {
+		series := h.series[key]
+		pairs := labelPairs(h.labels, key)
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += series.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, joinLabels(append(pairs, fmt.Sprintf(`le="%s"`, formatValue(bound)))), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, joinLabels(append(pairs, `le="+Inf"`)), series.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, joinLabels(pairs), formatValue(series.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, joinLabels(pairs), series.count)
+	}
+}
+
+func writeHeader(w *bufio.Writer, name, help, metricType string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+}
+
+// formatLabels returns the label set of a series from the label names and the key of the series
+func formatLabels(names []string, key string) string {
+	return joinLabels(labelPairs(names, key))
+}
+
+// labelPairs returns the name="value" pairs of a series from the label names and the key of the series
+func labelPairs(names []string, key string) []string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	values := strings.Split(key, labelSeparator)
+	escaper := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, escaper.Replace(value))
+	}
+	return pairs
+}
+
+func joinLabels(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func sortedKeys(values map[string]float64) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
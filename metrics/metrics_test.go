@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRegistry(t *testing.T) {
+	c.Convey("Given a registry with counters", t, func() {
+		registry := NewRegistry()
+		requests := registry.NewCounter("requests_total", "Requests made", "route", "status")
+		errs := registry.NewCounter("errors_total", "Errors\nraised")
+
+		requests.Inc("/search", "200")
+		requests.Inc("/search", "200")
+		requests.Add(3, "/search/releases", `5"00`)
+		requests.Add(-1, "/search", "200")
+		errs.Inc()
+
+		c.Convey("When the metrics are requested", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/metrics", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			registry.ServeHTTP(resp, req)
+
+			c.Convey("Then every metric is written in the Prometheus text format, in name order", func() {
+				c.So(resp.Code, c.ShouldEqual, http.StatusOK)
+				c.So(resp.Header().Get("Content-Type"), c.ShouldEqual, "text/plain; version=0.0.4; charset=utf-8")
+				c.So(resp.Body.String(), c.ShouldEqual, `# HELP errors_total Errors\nraised
+# TYPE errors_total counter
+errors_total 1
+# HELP requests_total Requests made
+# TYPE requests_total counter
+requests_total{route="/search/releases",status="5\"00"} 3
+requests_total{route="/search",status="200"} 2
+`)
+			})
+		})
+
+		c.Convey("When a counter with the same name is registered", func() {
+			again := registry.NewCounter("requests_total", "Requests made", "route", "status")
+
+			c.Convey("Then the existing counter is returned", func() {
+				c.So(again, c.ShouldEqual, requests)
+				c.So(again.Value("/search", "200"), c.ShouldEqual, 2)
+			})
+		})
+	})
+
+	c.Convey("Given a nil counter", t, func() {
+		var counter *Counter
+
+		c.Convey("Then nothing is recorded", func() {
+			c.So(func() { counter.Inc("a") }, c.ShouldNotPanic)
+		})
+	})
+}
+
+func TestHistogram(t *testing.T) {
+	c.Convey("Given a registry with a histogram", t, func() {
+		registry := NewRegistry()
+		duration := registry.NewHistogram("duration_seconds", "Time taken", []float64{0.1, 1}, "route")
+
+		duration.Observe(0.05, "/search")
+		duration.Observe(0.1, "/search")
+		duration.Observe(0.5, "/search")
+		duration.Observe(2, "/search")
+
+		c.Convey("When the metrics are requested", func() {
+			req := httptest.NewRequest(http.MethodGet, "http://localhost:23900/metrics", http.NoBody)
+			resp := httptest.NewRecorder()
+
+			registry.ServeHTTP(resp, req)
+
+			c.Convey("Then the cumulative bucket counts, sum and count of each series are written", func() {
+				c.So(resp.Body.String(), c.ShouldEqual, `# HELP duration_seconds Time taken
+# TYPE duration_seconds histogram
+duration_seconds_bucket{route="/search",le="0.1"} 2
+duration_seconds_bucket{route="/search",le="1"} 3
+duration_seconds_bucket{route="/search",le="+Inf"} 4
+duration_seconds_sum{route="/search"} 2.65
+duration_seconds_count{route="/search"} 4
+`)
+				c.So(duration.Count("/search"), c.ShouldEqual, 4)
+				c.So(duration.Count("/search/releases"), c.ShouldEqual, 0)
+			})
+		})
+	})
+}
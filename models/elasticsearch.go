@@ -43,11 +43,21 @@ type ESResponseHit struct {
 }
 
 type ESResponseAggregations struct {
-	ContentTypes       ESDocCounts `json:"content_types"`
-	Topic              ESDocCounts `json:"topic"`
-	PopulationType     ESDocCounts `json:"population_type"`
-	Dimensions         ESDocCounts `json:"dimensions"`
-	DistinctTopicCount CountValue  `json:"distinct_topics_count"`
+	ContentTypes         ESDocCounts     `json:"content_types"`
+	Topic                ESDocCounts     `json:"topic"`
+	PopulationType       ESDocCounts     `json:"population_type"`
+	Dimensions           ESDocCounts     `json:"dimensions"`
+	DistinctTopicCount   CountValue      `json:"distinct_topics_count"`
+	ReleaseDateHistogram ESDateHistogram `json:"release_date_histogram"`
+}
+
+type ESDateHistogram struct {
+	Buckets []ESDateBucket `json:"buckets"`
+}
+
+type ESDateBucket struct {
+	Key   string `json:"key_as_string"`
+	Count int    `json:"doc_count"`
 }
 
 type ESDocCounts struct {
@@ -112,6 +122,12 @@ type FilterCount struct {
 	Count int    `json:"count"`
 }
 
+// HistogramCount is the number of results released in the period starting on the date in Key
+type HistogramCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
 type ESPopulationType struct {
 	Name  string `json:"name"`
 	Label string `json:"label"`
@@ -153,16 +169,30 @@ type Item struct {
 }
 
 type SearchResponse struct {
-	Count               int           `json:"count"`
-	Took                int           `json:"took"`
-	DistinctItemsCount  int           `json:"distinct_items_count"`
-	Topics              []FilterCount `json:"topics"`
-	ContentTypes        []FilterCount `json:"content_types"`
-	Items               []Item        `json:"items"`
-	Suggestions         []string      `json:"suggestions,omitempty"`
-	AdditionSuggestions []string      `json:"additional_suggestions,omitempty"`
-	Dimensions          []FilterCount `json:"dimensions,omitempty"`
-	PopulationType      []FilterCount `json:"population_type,omitempty"`
+	Count                int              `json:"count"`
+	Took                 int              `json:"took"`
+	DistinctItemsCount   *int             `json:"distinct_items_count,omitempty"`
+	Topics               []FilterCount    `json:"topics"`
+	ContentTypes         []FilterCount    `json:"content_types"`
+	Items                []Item           `json:"items"`
+	Suggestions          []string         `json:"suggestions,omitempty"`
+	AdditionSuggestions  []string         `json:"additional_suggestions,omitempty"`
+	Dimensions           []FilterCount    `json:"dimensions,omitempty"`
+	PopulationType       []FilterCount    `json:"population_type,omitempty"`
+	Cursor               string           `json:"cursor,omitempty"`
+	ReleaseDateHistogram []HistogramCount `json:"release_date_histogram,omitempty"`
+	CorrectedQuery       string           `json:"corrected_query,omitempty"`
+	OriginalQuery        string           `json:"original_query,omitempty"`
+	Experiment           string           `json:"experiment,omitempty"`
+	Variant              string           `json:"variant,omitempty"`
+	RequestID            string           `json:"request_id,omitempty"`
+	Warnings             []Warning        `json:"warnings,omitempty"`
+}
+
+// Warning names a sub-query that failed, whose results are missing from an otherwise successful response
+type Warning struct {
+	Query       string `json:"query"`
+	Description string `json:"description"`
 }
 
 // ReleaseDateChange represent a date change of a release
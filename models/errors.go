@@ -0,0 +1,15 @@
+package models
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Errors []Error `json:"errors"`
+}
+
+// Error describes something that went wrong with a request. The code identifies the kind of error and does not
+// change, and the param and value are those of the query parameter that caused the error, if any.
+type Error struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Param       string `json:"param,omitempty"`
+	Value       string `json:"value,omitempty"`
+}
@@ -4,6 +4,30 @@ type CreateIndexResponse struct {
 	IndexName string `json:"index_name"`
 }
 
+// IndexesResponse lists the search indexes and the alias used to query them
+type IndexesResponse struct {
+	Alias   string  `json:"alias"`
+	Indexes []Index `json:"indexes"`
+}
+
+type Index struct {
+	Name      string   `json:"name"`
+	Aliases   []string `json:"aliases"`
+	CreatedAt string   `json:"created_at,omitempty"`
+}
+
+type UpdateAliasResponse struct {
+	Alias           string   `json:"alias"`
+	IndexName       string   `json:"index_name"`
+	PreviousIndexes []string `json:"previous_indexes,omitempty"`
+}
+
+// Synonyms holds the synonym rules applied to search queries, in the elasticsearch synonym format,
+// e.g. "cpi, consumer price index" or "psf => public sector finance"
+type Synonyms struct {
+	Synonyms []string `json:"synonyms"`
+}
+
 // Structs representing the transformed response
 type SearchResponseLegacy struct {
 	Count               int                 `json:"count"`
@@ -136,3 +160,16 @@ type ESSearchSuggestLegacy struct {
 type ESSearchSuggestOptionsLegacy struct {
 	Text string `json:"text"`
 }
+
+// SuggestResponse holds the title completions for a partial search term
+type SuggestResponse struct {
+	Took        int          `json:"took"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// Suggestion is a title completion, along with the page that has the title
+type Suggestion struct {
+	Title string `json:"title"`
+	URI   string `json:"uri"`
+	Type  string `json:"type"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Feedback event types
+const (
+	FeedbackImpression = "impression"
+	FeedbackClick      = "click"
+)
+
+// FeedbackEvent records the results shown for a search (an impression) or a result that was clicked (a click).
+// Clicks are joined to impressions by request ID, to evaluate and learn the relevance of search results.
+type FeedbackEvent struct {
+	Type      string    `json:"type"`
+	RequestID string    `json:"request_id"`
+	Query     string    `json:"query"`
+	URI       string    `json:"uri,omitempty"`
+	URIs      []string  `json:"uris,omitempty"`
+	Position  int       `json:"position"`
+	Variant   string    `json:"variant,omitempty"`
+	Session   string    `json:"session,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Feedback is a click on a search result, as sent to the search feedback endpoint.
+// Position is the position of the clicked result in the search results, starting at 1.
+type Feedback struct {
+	RequestID string `json:"request_id"`
+	Query     string `json:"query"`
+	URI       string `json:"uri"`
+	Position  int    `json:"position"`
+	Variant   string `json:"variant"`
+	Session   string `json:"session"`
+}
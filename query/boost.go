@@ -0,0 +1,167 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultBoostProfile is the name of the boost profile used when none is requested
+const DefaultBoostProfile = "default"
+
+var (
+	// boostValuePattern restricts the content types and topics that can be boosted, as they are written into the query
+	boostValuePattern = regexp.MustCompile(`^[A-Za-z0-9_\-]+$`)
+	// decayDistancePattern matches the elasticsearch time units accepted for the scale and offset of a recency decay
+	decayDistancePattern = regexp.MustCompile(`^\d+(ms|s|m|h|d|w)$`)
+)
+
+// BoostProfiles are the boost profiles that can be selected by name
+type BoostProfiles map[string]*BoostProfile
+
+// BoostProfile weights the relevance score of the content query. The weights of every boost that a result
+// matches are multiplied together, along with its recency decay.
+type BoostProfile struct {
+	ContentTypes []Boost       `json:"content_types,omitempty"`
+	Topics       []Boost       `json:"topics,omitempty"`
+	RecencyDecay *RecencyDecay `json:"recency_decay,omitempty"`
+}
+
+// Boost is the weight applied to results that have any of the values
+type Boost struct {
+	Values []string `json:"values"`
+	Weight float64  `json:"weight"`
+}
+
+// RecencyDecay reduces the score of results the further their release date is from now: results released within
+// the offset are not reduced, and results released a further scale ago are multiplied by the decay
+type RecencyDecay struct {
+	Scale  string  `json:"scale"`
+	Offset string  `json:"offset,omitempty"`
+	Decay  float64 `json:"decay,omitempty"`
+}
+
+// BoostFunction is a function_score weight applied to the results with any of the values in the field
+type BoostFunction struct {
+	Field  string
+	Values []string
+	Weight float64
+}
+
+// DefaultBoostProfiles returns the boost profiles used when none are configured
+func DefaultBoostProfiles() BoostProfiles {
+	return BoostProfiles{DefaultBoostProfile: defaultBoostProfile()}
+}
+
+func defaultBoostProfile() *BoostProfile {
+	return &BoostProfile{
+		ContentTypes: []Boost{
+			{Values: []string{"bulletin"}, Weight: 100},
+			{Values: []string{"dataset_landing_page"}, Weight: 70},
+			{Values: []string{"article", "statistical_article", "compendium_landing_page", "article_download"}, Weight: 50},
+			{Values: []string{"static_adhoc"}, Weight: 30},
+			{Values: []string{"timeseries"}, Weight: 10},
+		},
+	}
+}
+
+// LoadBoostProfiles returns the boost profiles defined in the provided file, or else in the provided JSON,
+// or else the default boost profiles. The default profile is added if the definitions do not include it.
+func LoadBoostProfiles(profilesJSON, profilesFile string) (BoostProfiles, error) {
+	data := []byte(profilesJSON)
+	if profilesFile != "" {
+		var err error
+		if data, err = os.ReadFile(profilesFile); err != nil {
+			return nil, fmt.Errorf("failed to read boost profiles file: %w", err)
+		}
+	}
+
+	if len(data) == 0 {
+		return DefaultBoostProfiles(), nil
+	}
+
+	return ParseBoostProfiles(data)
+}
+
+// ParseBoostProfiles parses and validates boost profiles defined as a JSON object, keyed by profile name
+func ParseBoostProfiles(data []byte) (BoostProfiles, error) {
+	var profiles BoostProfiles
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse boost profiles: %w", err)
+	}
+
+	for name, profile := range profiles {
+		if profile == nil {
+			return nil, fmt.Errorf("boost profile %s is empty", name)
+		}
+		if err := profile.validate(); err != nil {
+			return nil, fmt.Errorf("boost profile %s is invalid: %w", name, err)
+		}
+	}
+
+	if _, ok := profiles[DefaultBoostProfile]; !ok {
+		profiles[DefaultBoostProfile] = defaultBoostProfile()
+	}
+
+	return profiles, nil
+}
+
+// Get returns the boost profile with the provided name
+func (bps BoostProfiles) Get(name string) (*BoostProfile, error) {
+	if profile, ok := bps[name]; ok {
+		return profile, nil
+	}
+
+	names := make([]string, 0, len(bps))
+	for profileName := range bps {
+		names = append(names, profileName)
+	}
+	sort.Strings(names)
+
+	return nil, fmt.Errorf("boost profile must be one of: %s", strings.Join(names, ", "))
+}
+
+// Functions returns the weights of the profile as function_score functions, content types first
+func (bp *BoostProfile) Functions() []BoostFunction {
+	functions := make([]BoostFunction, 0, len(bp.ContentTypes)+len(bp.Topics))
+	for _, boost := range bp.ContentTypes {
+		functions = append(functions, BoostFunction{Field: "type", Values: boost.Values, Weight: boost.Weight})
+	}
+	for _, boost := range bp.Topics {
+		functions = append(functions, BoostFunction{Field: "topics", Values: boost.Values, Weight: boost.Weight})
+	}
+	return functions
+}
+
+func (bp *BoostProfile) validate() error {
+	for _, boost := range append(append([]Boost{}, bp.ContentTypes...), bp.Topics...) {
+		if len(boost.Values) == 0 {
+			return fmt.Errorf("boost with weight %v has no values", boost.Weight)
+		}
+		if boost.Weight <= 0 {
+			return fmt.Errorf("weight for %s must be greater than 0", strings.Join(boost.Values, ","))
+		}
+		for _, value := range boost.Values {
+			if !boostValuePattern.MatchString(value) {
+				return fmt.Errorf("invalid boost value %q", value)
+			}
+		}
+	}
+
+	if decay := bp.RecencyDecay; decay != nil {
+		if !decayDistancePattern.MatchString(decay.Scale) {
+			return fmt.Errorf("recency decay scale %q must be a time unit such as 365d", decay.Scale)
+		}
+		if decay.Offset != "" && !decayDistancePattern.MatchString(decay.Offset) {
+			return fmt.Errorf("recency decay offset %q must be a time unit such as 30d", decay.Offset)
+		}
+		if decay.Decay < 0 || decay.Decay >= 1 {
+			return fmt.Errorf("recency decay must be between 0 and 1")
+		}
+	}
+
+	return nil
+}
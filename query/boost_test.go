@@ -0,0 +1,113 @@
+package query
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+const testBoostProfiles = `{
+	"recent": {
+		"content_types": [{"values": ["bulletin", "article"], "weight": 20}],
+		"topics": [{"values": ["1234"], "weight": 5}],
+		"recency_decay": {"scale": "365d", "offset": "30d", "decay": 0.25}
+	}
+}`
+
+func TestParseBoostProfiles(t *testing.T) {
+	c.Convey("Given boost profiles without a default profile", t, func() {
+		profiles, err := ParseBoostProfiles([]byte(testBoostProfiles))
+
+		c.Convey("Then the profiles are parsed and the built-in default profile is added", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(profiles, c.ShouldHaveLength, 2)
+			c.So(profiles[DefaultBoostProfile], c.ShouldResemble, defaultBoostProfile())
+			c.So(profiles["recent"].RecencyDecay, c.ShouldResemble, &RecencyDecay{Scale: "365d", Offset: "30d", Decay: 0.25})
+			c.So(profiles["recent"].Functions(), c.ShouldResemble, []BoostFunction{
+				{Field: "type", Values: []string{"bulletin", "article"}, Weight: 20},
+				{Field: "topics", Values: []string{"1234"}, Weight: 5},
+			})
+		})
+	})
+
+	c.Convey("Given invalid boost profiles", t, func() {
+		for data, expectedErr := range map[string]string{
+			`not json`:    "failed to parse boost profiles",
+			`{"a": null}`: "boost profile a is empty",
+			`{"a": {"content_types": [{"values": ["bulletin"], "weight": 0}]}}`: "boost profile a is invalid: weight for bulletin must be greater than 0",
+			`{"a": {"topics": [{"values": [], "weight": 2}]}}`:                  "boost profile a is invalid: boost with weight 2 has no values",
+			`{"a": {"topics": [{"values": ["\"}"], "weight": 2}]}}`:             `boost profile a is invalid: invalid boost value "\"}"`,
+			`{"a": {"recency_decay": {"scale": "1 year"}}}`:                     `boost profile a is invalid: recency decay scale "1 year" must be a time unit such as 365d`,
+			`{"a": {"recency_decay": {"scale": "365d", "offset": "soon"}}}`:     `boost profile a is invalid: recency decay offset "soon" must be a time unit such as 30d`,
+			`{"a": {"recency_decay": {"scale": "365d", "decay": 1}}}`:           "boost profile a is invalid: recency decay must be between 0 and 1",
+		} {
+			_, err := ParseBoostProfiles([]byte(data))
+			c.So(err, c.ShouldNotBeNil)
+			c.So(err.Error(), c.ShouldStartWith, expectedErr)
+		}
+	})
+}
+
+func TestLoadBoostProfiles(t *testing.T) {
+	c.Convey("Given no boost profiles are configured", t, func() {
+		profiles, err := LoadBoostProfiles("", "")
+
+		c.Convey("Then the default boost profiles are returned", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(profiles, c.ShouldResemble, DefaultBoostProfiles())
+		})
+	})
+
+	c.Convey("Given boost profiles in a file and as JSON", t, func() {
+		file := filepath.Join(t.TempDir(), "profiles.json")
+		c.So(os.WriteFile(file, []byte(testBoostProfiles), 0o600), c.ShouldBeNil)
+
+		profiles, err := LoadBoostProfiles(`{"other": {}}`, file)
+
+		c.Convey("Then the profiles in the file are used", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(profiles, c.ShouldContainKey, "recent")
+			c.So(profiles, c.ShouldNotContainKey, "other")
+		})
+	})
+
+	c.Convey("Given a boost profiles file that does not exist", t, func() {
+		_, err := LoadBoostProfiles("", filepath.Join(t.TempDir(), "missing.json"))
+
+		c.Convey("Then an error is returned", func() {
+			c.So(err, c.ShouldNotBeNil)
+		})
+	})
+}
+
+func TestBuildSearchQueryBoostProfile(t *testing.T) {
+	c.Convey("Given a Query builder and a boost profile with topics and recency decay", t, func() {
+		qb, err := NewQueryBuilder()
+		c.So(err, c.ShouldBeNil)
+		profiles, err := ParseBoostProfiles([]byte(testBoostProfiles))
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("Then the content query is weighted by the profile", func() {
+			query, err := qb.BuildSearchQuery(context.Background(), &SearchRequest{Term: "census", BoostProfile: profiles["recent"]}, true)
+			c.So(err, c.ShouldBeNil)
+
+			searches := unmarshal(query)
+			c.So(string(searches[0].Query), c.ShouldContainSubstring, `"functions":[`+
+				`{"filter":{"terms":{"type":["bulletin","article"]}},"weight":20},`+
+				`{"filter":{"term":{"topics":"1234"}},"weight":5},`+
+				`{"gauss":{"release_date":{"origin":"now","scale":"365d","offset":"30d","decay":0.25}}}]`)
+		})
+
+		c.Convey("Then a profile with only a recency decay is a valid function score", func() {
+			profile := &BoostProfile{RecencyDecay: &RecencyDecay{Scale: "90d"}}
+			query, err := qb.BuildSearchQuery(context.Background(), &SearchRequest{Term: "census", BoostProfile: profile}, true)
+			c.So(err, c.ShouldBeNil)
+
+			searches := unmarshal(query)
+			c.So(string(searches[0].Query), c.ShouldContainSubstring, `"functions":[{"gauss":{"release_date":{"origin":"now","scale":"90d"}}}]`)
+		})
+	})
+}
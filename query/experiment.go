@@ -0,0 +1,122 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// experimentBuckets is the number of buckets that requests are spread across, so traffic is allocated in percentages
+const experimentBuckets = 100
+
+// Experiments are the search experiments that requests can be assigned to. Every variant of every experiment
+// takes its own share of the buckets, so a request is assigned to at most one variant.
+type Experiments []*Experiment
+
+// Experiment trials alternative ranking strategies, as variants, against the default search
+type Experiment struct {
+	Name     string     `json:"name"`
+	Variants []*Variant `json:"variants"`
+}
+
+// Variant is a ranking strategy that receives a percentage of the search traffic. Its boost profile is used
+// unless one is requested, and the templates in its templates directory replace the default templates of the same name.
+type Variant struct {
+	Name         string `json:"name"`
+	Traffic      int    `json:"traffic"`
+	BoostProfile string `json:"boost_profile,omitempty"`
+	Templates    string `json:"templates,omitempty"`
+	Experiment   string `json:"-"`
+}
+
+// ParseExperiments parses and validates experiments defined as a JSON array. Variant names must be unique across
+// experiments, their boost profiles must be one of the provided profiles and their traffic must total at most 100%.
+func ParseExperiments(data []byte, profiles BoostProfiles) (Experiments, error) {
+	if len(data) == 0 {
+		return Experiments{}, nil
+	}
+
+	var experiments Experiments
+	if err := json.Unmarshal(data, &experiments); err != nil {
+		return nil, fmt.Errorf("failed to parse experiments: %w", err)
+	}
+
+	traffic := 0
+	names := map[string]bool{}
+	for _, experiment := range experiments {
+		if experiment == nil || experiment.Name == "" {
+			return nil, fmt.Errorf("experiments must have a name")
+		}
+		if len(experiment.Variants) == 0 {
+			return nil, fmt.Errorf("experiment %s has no variants", experiment.Name)
+		}
+
+		for _, variant := range experiment.Variants {
+			if variant == nil || variant.Name == "" {
+				return nil, fmt.Errorf("experiment %s has a variant without a name", experiment.Name)
+			}
+			if names[variant.Name] {
+				return nil, fmt.Errorf("variant %s is defined more than once", variant.Name)
+			}
+			if variant.Traffic < 0 {
+				return nil, fmt.Errorf("traffic for variant %s cannot be negative", variant.Name)
+			}
+			if variant.BoostProfile != "" {
+				if _, err := profiles.Get(variant.BoostProfile); err != nil {
+					return nil, fmt.Errorf("variant %s is invalid: %w", variant.Name, err)
+				}
+			}
+
+			names[variant.Name] = true
+			traffic += variant.Traffic
+			variant.Experiment = experiment.Name
+		}
+	}
+
+	if traffic > experimentBuckets {
+		return nil, fmt.Errorf("experiment traffic totals %d%%, which is more than 100%%", traffic)
+	}
+
+	return experiments, nil
+}
+
+// Assign deterministically assigns the request with the provided session ID to a variant, so that every request
+// in a session is assigned to the same variant. Nil is returned if the ID falls outside of the experiment traffic.
+func (e Experiments) Assign(id string) *Variant {
+	if id == "" {
+		return nil
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(id))
+	bucket := int(hash.Sum32() % experimentBuckets)
+
+	for _, experiment := range e {
+		for _, variant := range experiment.Variants {
+			if bucket < variant.Traffic {
+				return variant
+			}
+			bucket -= variant.Traffic
+		}
+	}
+
+	return nil
+}
+
+// Get returns the variant with the provided name
+func (e Experiments) Get(name string) (*Variant, error) {
+	names := []string{}
+	for _, experiment := range e {
+		for _, variant := range experiment.Variants {
+			if variant.Name == name {
+				return variant, nil
+			}
+			names = append(names, variant.Name)
+		}
+	}
+	sort.Strings(names)
+
+	return nil, fmt.Errorf("variant must be one of: %s", strings.Join(names, ", "))
+}
@@ -0,0 +1,146 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+const testExperiments = `[
+	{"name": "recency", "variants": [
+		{"name": "recent", "traffic": 20, "boost_profile": "recent"},
+		{"name": "recent-title", "traffic": 10, "templates": "/templates/recent-title"}
+	]},
+	{"name": "synonyms", "variants": [{"name": "no-synonyms", "traffic": 5}]}
+]`
+
+func TestParseExperiments(t *testing.T) {
+	profiles, err := ParseBoostProfiles([]byte(testBoostProfiles))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Convey("Given valid experiments", t, func() {
+		experiments, err := ParseExperiments([]byte(testExperiments), profiles)
+
+		c.Convey("Then the experiments are parsed and each variant knows its experiment", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(experiments, c.ShouldHaveLength, 2)
+			c.So(experiments[0].Variants[0], c.ShouldResemble, &Variant{Name: "recent", Traffic: 20, BoostProfile: "recent", Experiment: "recency"})
+			c.So(experiments[1].Variants[0].Experiment, c.ShouldEqual, "synonyms")
+		})
+	})
+
+	c.Convey("Given no experiments", t, func() {
+		experiments, err := ParseExperiments(nil, profiles)
+
+		c.Convey("Then no experiments are returned", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(experiments, c.ShouldBeEmpty)
+		})
+	})
+
+	c.Convey("Given invalid experiments", t, func() {
+		for data, expectedErr := range map[string]string{
+			`{}`:                                "failed to parse experiments",
+			`[{"variants": [{"name": "a"}]}]`:   "experiments must have a name",
+			`[{"name": "e"}]`:                   "experiment e has no variants",
+			`[{"name": "e", "variants": [{}]}]`: "experiment e has a variant without a name",
+			`[{"name": "e", "variants": [{"name": "a"}]}, {"name": "f", "variants": [{"name": "a"}]}]`:  "variant a is defined more than once",
+			`[{"name": "e", "variants": [{"name": "a", "traffic": -1}]}]`:                               "traffic for variant a cannot be negative",
+			`[{"name": "e", "variants": [{"name": "a", "boost_profile": "unknown"}]}]`:                  "variant a is invalid: boost profile must be one of: default, recent",
+			`[{"name": "e", "variants": [{"name": "a", "traffic": 60}, {"name": "b", "traffic": 41}]}]`: "experiment traffic totals 101%, which is more than 100%",
+		} {
+			_, err := ParseExperiments([]byte(data), profiles)
+			c.So(err, c.ShouldNotBeNil)
+			c.So(err.Error(), c.ShouldStartWith, expectedErr)
+		}
+	})
+}
+
+func TestExperimentsAssign(t *testing.T) {
+	c.Convey("Given experiments taking 35% of the traffic", t, func() {
+		profiles, err := ParseBoostProfiles([]byte(testBoostProfiles))
+		c.So(err, c.ShouldBeNil)
+		experiments, err := ParseExperiments([]byte(testExperiments), profiles)
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("When many sessions are assigned", func() {
+			assigned := map[string]int{}
+			for i := 0; i < 10000; i++ {
+				if variant := experiments.Assign(fmt.Sprintf("session-%d", i)); variant != nil {
+					assigned[variant.Name]++
+				}
+			}
+
+			c.Convey("Then each variant receives roughly its share of the sessions", func() {
+				c.So(assigned["recent"], c.ShouldBeBetween, 1800, 2200)
+				c.So(assigned["recent-title"], c.ShouldBeBetween, 800, 1200)
+				c.So(assigned["no-synonyms"], c.ShouldBeBetween, 350, 650)
+			})
+		})
+
+		c.Convey("Then a session is always assigned to the same variant", func() {
+			for i := 0; i < 100; i++ {
+				id := fmt.Sprintf("session-%d", i)
+				c.So(experiments.Assign(id), c.ShouldEqual, experiments.Assign(id))
+			}
+		})
+
+		c.Convey("Then a request without a session ID is not assigned", func() {
+			c.So(experiments.Assign(""), c.ShouldBeNil)
+		})
+
+		c.Convey("Then variants can be requested by name", func() {
+			variant, err := experiments.Get("no-synonyms")
+			c.So(err, c.ShouldBeNil)
+			c.So(variant.Experiment, c.ShouldEqual, "synonyms")
+
+			_, err = experiments.Get("unknown")
+			c.So(err.Error(), c.ShouldEqual, "variant must be one of: no-synonyms, recent, recent-title")
+		})
+	})
+}
+
+func TestAddTemplateSet(t *testing.T) {
+	c.Convey("Given a Query builder and a directory with a replacement template", t, func() {
+		qb, err := NewQueryBuilder()
+		c.So(err, c.ShouldBeNil)
+		dir := t.TempDir()
+		c.So(os.WriteFile(filepath.Join(dir, "coreQuery.tmpl"), []byte(`"match": {"title": "{{.Term}}"}`), 0o600), c.ShouldBeNil)
+
+		c.Convey("When the template set is added", func() {
+			c.So(qb.AddTemplateSet("title-only", dir), c.ShouldBeNil)
+
+			c.Convey("Then queries for the template set use the replacement template", func() {
+				query, err := qb.BuildSearchQuery(context.Background(), &SearchRequest{Term: "census", TemplateSet: "title-only"}, true)
+				c.So(err, c.ShouldBeNil)
+				c.So(string(unmarshal(query)[0].Query), c.ShouldContainSubstring, `"query":{"match":{"title":"census"}}`)
+
+				countQuery, err := qb.BuildCountQuery(context.Background(), &CountRequest{Term: "census", CountEnable: true, TemplateSet: "title-only"})
+				c.So(err, c.ShouldBeNil)
+				c.So(string(countQuery), c.ShouldContainSubstring, `"match": {"title": "census"}`)
+			})
+
+			c.Convey("Then other queries use the default templates", func() {
+				query, err := qb.BuildSearchQuery(context.Background(), &SearchRequest{Term: "census"}, true)
+				c.So(err, c.ShouldBeNil)
+				c.So(string(unmarshal(query)[0].Query), c.ShouldNotContainSubstring, `"query":{"match":{"title":"census"}}`)
+			})
+		})
+
+		c.Convey("Then queries for an unknown template set fail", func() {
+			_, err := qb.BuildSearchQuery(context.Background(), &SearchRequest{Term: "census", TemplateSet: "unknown"}, true)
+			c.So(err, c.ShouldNotBeNil)
+		})
+
+		c.Convey("Then a directory with an invalid template is rejected", func() {
+			c.So(os.WriteFile(filepath.Join(dir, "weightedQuery.tmpl"), []byte(`{{.Term`), 0o600), c.ShouldBeNil)
+			c.So(qb.AddTemplateSet("invalid", dir), c.ShouldNotBeNil)
+		})
+	})
+}
@@ -3,6 +3,7 @@ package query
 import (
 	"bytes"
 	"encoding/json"
+	"os"
 	"regexp"
 	"text/template"
 
@@ -17,6 +18,13 @@ type Builder struct {
 	nlpCriteria     *NlpCriteria
 	searchTemplates *template.Template
 	countTemplates  *template.Template
+	templateSets    map[string]*templateSet
+}
+
+// templateSet is a copy of the search and count templates with some of them replaced
+type templateSet struct {
+	searchTemplates *template.Template
+	countTemplates  *template.Template
 }
 
 type NlpCriteriaCategory struct {
@@ -60,6 +68,46 @@ func NewQueryBuilder() (*Builder, error) {
 	}, nil
 }
 
+// AddTemplateSet loads the templates in the provided directory over a copy of the search and count templates,
+// replacing the templates of the same name. The templates are used by requests for the named template set.
+func (sb *Builder) AddTemplateSet(name, dir string) error {
+	searchTemplates, err := overrideTemplates(sb.searchTemplates, dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load search templates for template set %s", name)
+	}
+
+	countTemplates, err := overrideTemplates(sb.countTemplates, dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load count templates for template set %s", name)
+	}
+
+	if sb.templateSets == nil {
+		sb.templateSets = map[string]*templateSet{}
+	}
+	sb.templateSets[name] = &templateSet{searchTemplates: searchTemplates, countTemplates: countTemplates}
+	return nil
+}
+
+// overrideTemplates returns a copy of the templates with the templates in the directory added or replaced
+func overrideTemplates(templates *template.Template, dir string) (*template.Template, error) {
+	clone, err := templates.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return clone.ParseFS(os.DirFS(dir), "*.tmpl")
+}
+
+// getTemplateSet returns the named template set, or the default templates if no name is provided
+func (sb *Builder) getTemplateSet(name string) (*templateSet, error) {
+	if name == "" {
+		return &templateSet{searchTemplates: sb.searchTemplates, countTemplates: sb.countTemplates}, nil
+	}
+	if set, ok := sb.templateSets[name]; ok {
+		return set, nil
+	}
+	return nil, errors.Errorf("unknown template set %s", name)
+}
+
 // FormatMultiQuery minifies and reformats an elasticsearch MultiQuery
 func FormatMultiQuery(rawQuery []byte) ([]byte, error) {
 	// Is minify thread Safe? can I put this as a global?
@@ -0,0 +1,47 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	esClient "github.com/ONSdigital/dp-elasticsearch/v3/client"
+)
+
+// MaxCalendarDays is the most days that a release calendar can span, which bounds the number of buckets counted
+const MaxCalendarDays = 366
+
+// calendarIntervals are the calendar intervals by which releases can be counted on their release date
+var calendarIntervals = []string{"day", "week"}
+
+// ReleaseCalendarRequest requests the number of releases of each type and subtype released in each interval of
+// a calendar
+type ReleaseCalendarRequest struct {
+	ReleasedAfter  Date
+	ReleasedBefore Date
+	Interval       string
+	Census         bool
+	Language       string   // language code of the releases to count, releases in any language are counted if empty
+	Topic          []string // topics of the releases to count, as their canonical topic or one of their topics
+}
+
+// ParseCalendarInterval validates a release calendar interval
+func ParseCalendarInterval(param string) (string, error) {
+	for _, interval := range calendarIntervals {
+		if strings.EqualFold(param, interval) {
+			return interval, nil
+		}
+	}
+	return "", fmt.Errorf("calendar interval must be one of: %s", strings.Join(calendarIntervals, ", "))
+}
+
+// Now returns today's date as a quoted elasticsearch date, which splits unpublished releases into upcoming and outdated
+func (cr ReleaseCalendarRequest) Now() string {
+	return fmt.Sprintf("%q", time.Now().Format(dateFormat))
+}
+
+// BuildCalendarQuery builds an elastic search query counting releases by type, subtype and release date
+func (rb *ReleaseBuilder) BuildCalendarQuery(_ context.Context, calendarRequest ReleaseCalendarRequest) ([]esClient.Search, error) {
+	return buildSearches(rb.calendarTemplates, calendarRequest)
+}
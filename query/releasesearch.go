@@ -160,7 +160,8 @@ func (rt ReleaseType) String() string {
 }
 
 type ReleaseBuilder struct {
-	searchTemplates *template.Template
+	searchTemplates   *template.Template
+	calendarTemplates *template.Template
 }
 
 func NewReleaseBuilder() (*ReleaseBuilder, error) {
@@ -171,23 +172,47 @@ func NewReleaseBuilder() (*ReleaseBuilder, error) {
 
 	searchTemplate, err = template.ParseFS(releaseFS,
 		"templates/releasecalendar/search.tmpl",
+		"templates/releasecalendar/releaseTypes.tmpl",
 		"templates/releasecalendar/query.tmpl",
 		"templates/releasecalendar/simplequery.tmpl",
-		"templates/search/v710/coreQuery.tmpl")
+		"templates/search/v710/coreQuery.tmpl",
+		"templates/search/v710/coreQueryWelsh.tmpl",
+		"templates/search/v710/languageFilter.tmpl",
+		"templates/search/v710/topicFilters.tmpl",
+		"templates/search/v710/canonicalFilters.tmpl",
+		"templates/search/v710/subTopicsFilters.tmpl")
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to load search template: %w", err)
 	}
 
+	calendarTemplate, err := template.ParseFS(releaseFS,
+		"templates/releasecalendar/calendar.tmpl",
+		"templates/releasecalendar/releaseTypes.tmpl",
+		"templates/search/v710/languageFilter.tmpl",
+		"templates/search/v710/topicFilters.tmpl",
+		"templates/search/v710/canonicalFilters.tmpl",
+		"templates/search/v710/subTopicsFilters.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load calendar template: %w", err)
+	}
+
 	return &ReleaseBuilder{
-		searchTemplates: searchTemplate,
+		searchTemplates:   searchTemplate,
+		calendarTemplates: calendarTemplate,
 	}, nil
 }
 
 // BuildSearchQuery builds an elastic search query from the provided search parameters for Release Calendars
 func (rb *ReleaseBuilder) BuildSearchQuery(_ context.Context, searchRequest interface{}) ([]esClient.Search, error) {
+	return buildSearches(rb.searchTemplates, searchRequest)
+}
+
+// buildSearches executes templates that output each search as the index to search on one line,
+// followed by the query on the next line, and returns the searches
+func buildSearches(searchTemplates *template.Template, searchRequest interface{}) ([]esClient.Search, error) {
 	var doc bytes.Buffer
-	err := rb.searchTemplates.Execute(&doc, searchRequest)
+	err := searchTemplates.Execute(&doc, searchRequest)
 	if err != nil {
 		return nil, fmt.Errorf("creation of search from template failed: %w", err)
 	}
@@ -226,6 +251,12 @@ type ReleaseSearchRequest struct {
 	Postponed      bool
 	Census         bool
 	Highlight      bool
+	Language       string   // language code of the releases to return, releases in any language are returned if empty
+	Topic          []string // topics of the releases to return, as their canonical topic or one of their topics
+	// RescheduledSince selects releases rescheduled since it. Date changes do not record when they were made, so
+	// their previous date stands in for it: releases are rescheduled before the date they are moved from, so every
+	// release rescheduled since then has a date change from a date on or after it.
+	RescheduledSince Date
 }
 
 const (
@@ -316,6 +347,18 @@ func (sr ReleaseSearchRequest) CensusClause() string {
 	return EmptyClause
 }
 
+// RescheduledClause returns the query clause to select releases rescheduled since RescheduledSince, using the previous
+// date of their date changes as a stand-in for when they were made. Date changes are nested documents, so they are
+// queried with a nested query.
+func (sr ReleaseSearchRequest) RescheduledClause() string {
+	if !sr.RescheduledSince.Set() {
+		return EmptyClause
+	}
+
+	return fmt.Sprintf(`{"nested": {"path": "date_changes", "query": {"range": {"date_changes.previous_date": {"gte": %s}}}}}`,
+		sr.RescheduledSince.ESString())
+}
+
 func (sr ReleaseSearchRequest) HighlightClause() string {
 	if sr.Highlight {
 		return `
@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
@@ -242,6 +243,94 @@ func TestBuildSearchReleaseQuery(t *testing.T) {
 	})
 }
 
+func TestBuildSearchReleaseQueryLanguage(t *testing.T) {
+	t.Parallel()
+	c.Convey("Given a release query builder", t, func() {
+		qb, err := NewReleaseBuilder()
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("Then a Welsh release search queries the Welsh-analysed fields and is filtered on language", func() {
+			searches, err := qb.BuildSearchQuery(context.Background(), ReleaseSearchRequest{
+				Term: "cyfrifiad", Template: "s", Size: 10, SortBy: Relevance, Type: Published, Language: "cy",
+			})
+			c.So(err, c.ShouldBeNil)
+			c.So(searches, c.ShouldHaveLength, 2)
+			for _, search := range searches {
+				c.So(string(search.Query), c.ShouldContainSubstring, `"title.title_cy"`)
+				c.So(string(search.Query), c.ShouldContainSubstring, `{"match":{"language":"cy"}}`)
+			}
+		})
+
+		c.Convey("Then a release search without a language is not filtered on language", func() {
+			searches, err := qb.BuildSearchQuery(context.Background(), ReleaseSearchRequest{
+				Term: "census", Size: 10, SortBy: Relevance, Type: Published,
+			})
+			c.So(err, c.ShouldBeNil)
+			c.So(string(searches[0].Query), c.ShouldNotContainSubstring, `"language"`)
+		})
+	})
+}
+
+func TestBuildSearchReleaseQueryTopics(t *testing.T) {
+	t.Parallel()
+	c.Convey("Given a release query builder", t, func() {
+		qb, err := NewReleaseBuilder()
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("Then a release search for topics is filtered on them, as canonical topics or topics, in both searches", func() {
+			searches, err := qb.BuildSearchQuery(context.Background(), ReleaseSearchRequest{
+				Size: 10, SortBy: RelDateAsc, Type: Upcoming, Topic: []string{"1234", "5678"},
+			})
+			c.So(err, c.ShouldBeNil)
+			c.So(searches, c.ShouldHaveLength, 2)
+			c.So(json.Valid(searches[0].Query), c.ShouldBeTrue)
+			for _, search := range searches {
+				c.So(string(search.Query), c.ShouldContainSubstring,
+					`{"bool":{"should":[{"match":{"canonical_topic":"1234"}},{"match":{"canonical_topic":"5678"}},{"match":{"topics":"1234"}},{"match":{"topics":"5678"}}]}}`)
+			}
+		})
+
+		c.Convey("Then a release search counts its releases by topic", func() {
+			searches, err := qb.BuildSearchQuery(context.Background(), ReleaseSearchRequest{
+				Size: 10, SortBy: RelDateAsc, Type: Published,
+			})
+			c.So(err, c.ShouldBeNil)
+			c.So(json.Valid(searches[0].Query), c.ShouldBeTrue)
+			c.So(string(searches[0].Query), c.ShouldContainSubstring, `"topics":{"terms":{"field":"topics","size":1000}}`)
+			c.So(string(searches[0].Query), c.ShouldNotContainSubstring, `"canonical_topic"`)
+		})
+	})
+}
+
+func TestBuildSearchReleaseQueryRescheduledSince(t *testing.T) {
+	t.Parallel()
+	c.Convey("Given a release query builder", t, func() {
+		qb, err := NewReleaseBuilder()
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("Then a search for releases rescheduled since a date is filtered on their date changes, in both searches", func() {
+			searches, err := qb.BuildSearchQuery(context.Background(), ReleaseSearchRequest{
+				Size: 10, SortBy: RelDateAsc, Type: Upcoming, RescheduledSince: MustParseDate("2024-01-01"),
+			})
+			c.So(err, c.ShouldBeNil)
+			c.So(searches, c.ShouldHaveLength, 2)
+			c.So(json.Valid(searches[0].Query), c.ShouldBeTrue)
+			for _, search := range searches {
+				c.So(string(search.Query), c.ShouldContainSubstring,
+					`{"nested":{"path":"date_changes","query":{"range":{"date_changes.previous_date":{"gte":"2024-01-01"}}}}}`)
+			}
+		})
+
+		c.Convey("Then a search without a rescheduled date is not filtered on date changes", func() {
+			searches, err := qb.BuildSearchQuery(context.Background(), ReleaseSearchRequest{
+				Size: 10, SortBy: RelDateAsc, Type: Upcoming,
+			})
+			c.So(err, c.ShouldBeNil)
+			c.So(string(searches[0].Query), c.ShouldNotContainSubstring, `"nested"`)
+		})
+	})
+}
+
 func createReleaseQueryBuilderForTemplate(rawTemplate string) *ReleaseBuilder {
 	temp, err := template.New("search.tmpl").Parse(rawTemplate)
 	c.So(err, c.ShouldBeNil)
@@ -249,3 +338,63 @@ func createReleaseQueryBuilderForTemplate(rawTemplate string) *ReleaseBuilder {
 		searchTemplates: temp,
 	}
 }
+
+func TestBuildCalendarQuery(t *testing.T) {
+	t.Parallel()
+	c.Convey("Given a release query builder", t, func() {
+		qb, err := NewReleaseBuilder()
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("Then a release calendar query counts releases by type, subtype and week, over the whole calendar", func() {
+			searches, err := qb.BuildCalendarQuery(context.Background(), ReleaseCalendarRequest{
+				ReleasedAfter: MustParseDate("2024-01-01"), ReleasedBefore: MustParseDate("2024-01-31"), Interval: "week", Topic: []string{"1234"},
+			})
+			c.So(err, c.ShouldBeNil)
+			c.So(searches, c.ShouldHaveLength, 1)
+			c.So(searches[0].Header.Index, c.ShouldEqual, "ons")
+			c.So(json.Valid(searches[0].Query), c.ShouldBeTrue)
+			q := string(searches[0].Query)
+			c.So(q, c.ShouldContainSubstring, `"size":0`)
+			c.So(q, c.ShouldContainSubstring, `{"match":{"canonical_topic":"1234"}}`)
+			c.So(q, c.ShouldContainSubstring, `"release_types":{"filters":{"other_bucket_key":"cancelled"`)
+			c.So(q, c.ShouldContainSubstring, `"breakdown":{"filters":{"other_bucket_key":"confirmed"`)
+			c.So(q, c.ShouldContainSubstring, `"release_dates":{"date_histogram":{"field":"release_date","calendar_interval":"week"`)
+			c.So(q, c.ShouldContainSubstring, `"extended_bounds":{"min":"2024-01-01","max":"2024-01-31"}`)
+
+			c.Convey("And the date histogram is nested in the subtype breakdown shared with release searches", func() {
+				var calendarQuery struct {
+					Aggs struct {
+						ReleaseTypes struct {
+							Aggs struct {
+								Breakdown struct {
+									Aggs map[string]json.RawMessage `json:"aggs"`
+								} `json:"breakdown"`
+							} `json:"aggs"`
+						} `json:"release_types"`
+					} `json:"aggs"`
+				}
+				c.So(json.Unmarshal(searches[0].Query, &calendarQuery), c.ShouldBeNil)
+				c.So(calendarQuery.Aggs.ReleaseTypes.Aggs.Breakdown.Aggs, c.ShouldContainKey, "release_dates")
+			})
+		})
+
+		c.Convey("Then a release search does not count releases by date", func() {
+			searches, err := qb.BuildSearchQuery(context.Background(), ReleaseSearchRequest{Size: 10, SortBy: RelDateAsc, Type: Upcoming})
+			c.So(err, c.ShouldBeNil)
+			c.So(string(searches[1].Query), c.ShouldContainSubstring, `"breakdown":{"filters":{"other_bucket_key":"confirmed"`)
+			c.So(string(searches[1].Query), c.ShouldNotContainSubstring, `"release_dates"`)
+		})
+	})
+}
+
+func TestParseCalendarInterval(t *testing.T) {
+	t.Parallel()
+	c.Convey("Calendar intervals are parsed regardless of case", t, func() {
+		interval, err := ParseCalendarInterval("Week")
+		c.So(err, c.ShouldBeNil)
+		c.So(interval, c.ShouldEqual, "week")
+
+		_, err = ParseCalendarInterval("month")
+		c.So(err, c.ShouldNotBeNil)
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"fmt"
+	"strconv"
+	"strings"
 	"text/template"
 
 	"github.com/pkg/errors"
@@ -15,8 +18,24 @@ var searchFS embed.FS
 
 const (
 	legacyAggregationField = "_type"
+
+	// MaxFacetSize is the maximum number of buckets that can be requested for a facet, and the number returned by default
+	MaxFacetSize = 1000
+	// NoFacets is the facets parameter value that requests no facet counts
+	NoFacets = "none"
+	// ContentSearch is the name of the content query, the first search sent in a multi search
+	ContentSearch = "content"
 )
 
+// facetNames are the names by which each facet can be requested
+var facetNames = []string{"topics", "content_types", "population_types", "dimensions"}
+
+// languages are the codes of the languages content can be searched in: English and Welsh
+var languages = []string{"en", "cy"}
+
+// histogramIntervals are the calendar intervals by which results can be counted on their release date
+var histogramIntervals = []string{"month", "quarter", "year"}
+
 var es710AggregationField = &AggregationFields{
 	Topics:          "topics",
 	ContentTypes:    "type",
@@ -49,6 +68,101 @@ type SearchRequest struct {
 	DatasetIDs          []string
 	CDIDs               []string
 	URIs                []string
+	PointInTime         *PointInTime
+	Facets              *Facets
+	Histogram           string // calendar interval of the release date histogram, no histogram is returned if empty
+	Language            string // language code of the content to return, content in any language is returned if empty
+	BoostProfile        *BoostProfile
+	TemplateSet         string // name of the template set to build the query with, the default templates are used if empty
+}
+
+// Facets selects the aggregation (count) searches that are run alongside the content query.
+// Facets that are nil are not requested, so their searches are left out of the multi search.
+type Facets struct {
+	Topics          *Facet
+	ContentTypes    *Facet
+	PopulationTypes *Facet
+	Dimensions      *Facet
+}
+
+// Facet holds the maximum number of buckets returned for a facet
+type Facet struct {
+	Size int
+}
+
+// AllFacets returns the facets that are requested when none are specified: all of them, with the maximum size
+func AllFacets() *Facets {
+	return &Facets{
+		Topics:          &Facet{Size: MaxFacetSize},
+		ContentTypes:    &Facet{Size: MaxFacetSize},
+		PopulationTypes: &Facet{Size: MaxFacetSize},
+		Dimensions:      &Facet{Size: MaxFacetSize},
+	}
+}
+
+// Searches returns the names of the searches built for the request, in the order that search.tmpl sends them in the
+// multi search: the content query, followed by the count search of each requested facet, named as it is requested
+func (sr *SearchRequest) Searches() []string {
+	searches := []string{ContentSearch}
+	if sr.PointInTime != nil {
+		return searches
+	}
+
+	facets := sr.Facets
+	if facets == nil {
+		facets = AllFacets()
+	}
+	for i, facet := range []*Facet{facets.Topics, facets.ContentTypes, facets.PopulationTypes, facets.Dimensions} {
+		if facet != nil {
+			searches = append(searches, facetNames[i])
+		}
+	}
+	return searches
+}
+
+// ParseFacets parses a comma separated list of facet names, each optionally followed by a colon and the number
+// of buckets to return (e.g. "topics,dimensions:50"), or "none" to request no facets
+func ParseFacets(param string) (*Facets, error) {
+	facets := &Facets{}
+	if param == NoFacets {
+		return facets, nil
+	}
+
+	for _, item := range strings.Split(param, ",") {
+		name, sizeParam, hasSize := strings.Cut(strings.TrimSpace(item), ":")
+
+		size := MaxFacetSize
+		if hasSize {
+			var err error
+			if size, err = strconv.Atoi(sizeParam); err != nil || size < 1 || size > MaxFacetSize {
+				return nil, fmt.Errorf("facet size for %s must be a number between 1 and %d", name, MaxFacetSize)
+			}
+		}
+
+		facet := &Facet{Size: size}
+		switch name {
+		case "topics":
+			facets.Topics = facet
+		case "content_types":
+			facets.ContentTypes = facet
+		case "population_types":
+			facets.PopulationTypes = facet
+		case "dimensions":
+			facets.Dimensions = facet
+		default:
+			return nil, fmt.Errorf("unknown facet %q, facets must be one of: %s or %s", name, strings.Join(facetNames, ", "), NoFacets)
+		}
+	}
+
+	return facets, nil
+}
+
+// PointInTime pins the content query to an elasticsearch point in time, so that deep result sets can be paged
+// through with search_after instead of from/size. Only the content query is built when it is set.
+type PointInTime struct {
+	ID          string
+	KeepAlive   string
+	SearchAfter string // JSON array of the sort values of the last hit on the previous page
 }
 
 type PopulationTypeRequest struct {
@@ -77,6 +191,8 @@ type AggregationFields struct {
 type CountRequest struct {
 	Term        string
 	CountEnable bool
+	Language    string
+	TemplateSet string
 }
 
 func (sb *Builder) AddNlpCategorySearch(nlpCriteria *NlpCriteria, category, subCategory string, categoryWeighting float32) *NlpCriteria {
@@ -169,6 +285,7 @@ func SetupV710Search() (*template.Template, error) {
 		"templates/search/v710/countDimensionsQuery.tmpl",
 		"templates/search/v710/countDimensionsFilters.tmpl",
 		"templates/search/v710/coreQuery.tmpl",
+		"templates/search/v710/coreQueryWelsh.tmpl",
 		"templates/search/v710/weightedQuery.tmpl",
 		"templates/search/v710/contentTypeFilter.tmpl",
 		"templates/search/v710/contentFilters.tmpl",
@@ -182,6 +299,7 @@ func SetupV710Search() (*template.Template, error) {
 		"templates/search/v710/sortByReleaseDate.tmpl",
 		"templates/search/v710/sortByReleaseDateAsc.tmpl",
 		"templates/search/v710/sortByFirstLetter.tmpl",
+		"templates/search/v710/sortTiebreaker.tmpl",
 		"templates/search/v710/populationTypeFilters.tmpl",
 		"templates/search/v710/dimensionsFilters.tmpl",
 		"templates/search/v710/nlpCategory.tmpl",
@@ -189,6 +307,7 @@ func SetupV710Search() (*template.Template, error) {
 		"templates/search/v710/datasetFilters.tmpl",
 		"templates/search/v710/cdidFilters.tmpl",
 		"templates/search/v710/uriFilters.tmpl",
+		"templates/search/v710/languageFilter.tmpl",
 	)
 
 	return templates, err
@@ -202,22 +321,55 @@ func SetupV710Count() (*template.Template, error) {
 	templates, err := template.ParseFS(searchFS,
 		"templates/search/v710/distinctItemCountQuery.tmpl",
 		"templates/search/v710/coreQuery.tmpl",
+		"templates/search/v710/coreQueryWelsh.tmpl",
 		"templates/search/v710/matchAll.tmpl",
+		"templates/search/v710/languageFilter.tmpl",
 	)
 
 	return templates, err
 }
 
+// ParseHistogramInterval validates a release date histogram interval
+func ParseHistogramInterval(param string) (string, error) {
+	for _, interval := range histogramIntervals {
+		if strings.EqualFold(param, interval) {
+			return interval, nil
+		}
+	}
+	return "", fmt.Errorf("histogram interval must be one of: %s", strings.Join(histogramIntervals, ", "))
+}
+
+// ParseLanguage validates a language code
+func ParseLanguage(param string) (string, error) {
+	for _, language := range languages {
+		if strings.EqualFold(param, language) {
+			return language, nil
+		}
+	}
+	return "", fmt.Errorf("language must be one of: %s", strings.Join(languages, ", "))
+}
+
 // BuildSearchQuery creates an elastic search query from the provided search parameters
 func (sb *Builder) BuildSearchQuery(_ context.Context, reqParams *SearchRequest, esVersion710 bool) ([]byte, error) {
 	if esVersion710 {
 		reqParams.AggregationFields = es710AggregationField
+		if reqParams.Facets == nil {
+			reqParams.Facets = AllFacets()
+		}
+		if reqParams.BoostProfile == nil {
+			reqParams.BoostProfile = defaultBoostProfile()
+		}
 	} else {
 		reqParams.AggregationField = legacyAggregationField
 	}
 
+	templates, err := sb.getTemplateSet(reqParams.TemplateSet)
+	if err != nil {
+		return nil, err
+	}
+
 	var doc bytes.Buffer
-	err := sb.searchTemplates.Execute(&doc, reqParams)
+	err = templates.searchTemplates.Execute(&doc, reqParams)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "creation of search from template failed")
@@ -239,8 +391,13 @@ func (sb *Builder) BuildSearchQuery(_ context.Context, reqParams *SearchRequest,
 
 // BuildSearchQuery creates an elastic search query from the provided search parameters
 func (sb *Builder) BuildCountQuery(_ context.Context, reqParams *CountRequest) ([]byte, error) {
+	templates, err := sb.getTemplateSet(reqParams.TemplateSet)
+	if err != nil {
+		return nil, err
+	}
+
 	var doc bytes.Buffer
-	err := sb.countTemplates.Execute(&doc, reqParams)
+	err = templates.countTemplates.Execute(&doc, reqParams)
 	if err != nil {
 		return nil, errors.Wrap(err, "creation of search from template failed")
 	}
@@ -107,6 +107,51 @@ func TestBuildSearchQueryContent(t *testing.T) {
 	})
 }
 
+func TestBuildSearchQueryPointInTime(t *testing.T) {
+	c.Convey("Given a search request pinned to a point in time", t, func() {
+		qb, err := NewQueryBuilder()
+		c.So(err, c.ShouldBeNil)
+
+		reqParams := &SearchRequest{
+			Term:   "a",
+			Types:  []string{"ta"},
+			SortBy: "release_date",
+			Size:   2,
+			PointInTime: &PointInTime{
+				ID:          "pit-id",
+				KeepAlive:   "60s",
+				SearchAfter: `[1678450504000,1.5,"/a/b"]`,
+			},
+		}
+
+		c.Convey("When the search query is built", func() {
+			query, err := qb.BuildSearchQuery(context.Background(), reqParams, true)
+			c.So(err, c.ShouldBeNil)
+
+			var searches []client.Search
+			err = json.Unmarshal(query, &searches)
+			c.So(err, c.ShouldBeNil)
+
+			c.Convey("Then only the content query is built", func() {
+				c.So(searches, c.ShouldHaveLength, 1)
+
+				c.Convey("And it contains the point in time, the search_after values and a unique sort order", func() {
+					var content map[string]interface{}
+					c.So(json.Unmarshal(searches[0].Query, &content), c.ShouldBeNil)
+					c.So(content["pit"], c.ShouldResemble, map[string]interface{}{"id": "pit-id", "keep_alive": "60s"})
+					c.So(content["search_after"], c.ShouldResemble, []interface{}{1678450504000.0, 1.5, "/a/b"})
+					c.So(content["from"], c.ShouldBeNil)
+					c.So(content["sort"], c.ShouldResemble, []interface{}{
+						map[string]interface{}{"release_date": map[string]interface{}{"order": "desc"}},
+						map[string]interface{}{"_score": map[string]interface{}{"order": "desc"}},
+						map[string]interface{}{"uri.uri_raw": map[string]interface{}{"order": "asc", "unmapped_type": "keyword"}},
+					})
+				})
+			})
+		})
+	})
+}
+
 func TestBuildSearchQueryAggregates(t *testing.T) {
 	c.Convey("Given a Query builder", t, func() {
 		qb, err := NewQueryBuilder()
@@ -194,6 +239,156 @@ func TestBuildSearchQueryAggregates(t *testing.T) {
 	})
 }
 
+func TestBuildSearchQueryFacets(t *testing.T) {
+	c.Convey("Given a Query builder", t, func() {
+		qb, err := NewQueryBuilder()
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("Then only the requested aggregation (count) queries are generated, with the requested sizes", func() {
+			reqParams := &SearchRequest{
+				Facets: &Facets{
+					ContentTypes: &Facet{Size: 1000},
+					Dimensions:   &Facet{Size: 50},
+				},
+			}
+			query, err := qb.BuildSearchQuery(context.Background(), reqParams, true)
+			c.So(err, c.ShouldBeNil)
+
+			searches := unmarshal(query)
+			c.So(searches, c.ShouldHaveLength, 3)
+			c.So(string(searches[1].Query), c.ShouldEndWith, `"aggregations":{"content_types":{"terms":{"size":1000,"field":"type"}}}}`)
+			c.So(string(searches[2].Query), c.ShouldEndWith, `"aggregations":{"dimensions":{"terms":{"size":50,"field":"dimensions.agg_key"}}}}`)
+		})
+
+		c.Convey("Then only the content query is generated when no facets are requested", func() {
+			reqParams := &SearchRequest{Facets: &Facets{}}
+			query, err := qb.BuildSearchQuery(context.Background(), reqParams, true)
+			c.So(err, c.ShouldBeNil)
+
+			searches := unmarshal(query)
+			c.So(searches, c.ShouldHaveLength, 1)
+			c.So(string(searches[0].Query), c.ShouldNotContainSubstring, "aggregations")
+		})
+	})
+}
+
+func TestBuildSearchQueryHistogram(t *testing.T) {
+	c.Convey("Given a Query builder", t, func() {
+		qb, err := NewQueryBuilder()
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("Then the content query aggregates the results by release date when a histogram is requested", func() {
+			query, err := qb.BuildSearchQuery(context.Background(), &SearchRequest{Histogram: "quarter"}, true)
+			c.So(err, c.ShouldBeNil)
+
+			searches := unmarshal(query)
+			c.So(string(searches[0].Query), c.ShouldContainSubstring,
+				`"aggregations":{"release_date_histogram":{"date_histogram":{"field":"release_date","calendar_interval":"quarter","format":"yyyy-MM-dd"}}}`)
+		})
+
+		c.Convey("Then the content query has no aggregations when no histogram is requested", func() {
+			query, err := qb.BuildSearchQuery(context.Background(), &SearchRequest{}, true)
+			c.So(err, c.ShouldBeNil)
+
+			searches := unmarshal(query)
+			c.So(string(searches[0].Query), c.ShouldNotContainSubstring, "aggregations")
+		})
+	})
+}
+
+func TestBuildSearchQueryLanguage(t *testing.T) {
+	c.Convey("Given a Query builder", t, func() {
+		qb, err := NewQueryBuilder()
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("Then a Welsh search queries the Welsh-analysed fields and is filtered on language", func() {
+			query, err := qb.BuildSearchQuery(context.Background(), &SearchRequest{Term: "cyfrifiad", Language: "cy"}, true)
+			c.So(err, c.ShouldBeNil)
+
+			searches := unmarshal(query)
+			for _, search := range searches {
+				c.So(string(search.Query), c.ShouldContainSubstring, `{"match":{"language":"cy"}}`)
+			}
+			c.So(string(searches[0].Query), c.ShouldContainSubstring, `"title.title_cy"`)
+			c.So(string(searches[0].Query), c.ShouldNotContainSubstring, `"title.title_no_dates"`)
+		})
+
+		c.Convey("Then an English search also returns content that has no language", func() {
+			query, err := qb.BuildSearchQuery(context.Background(), &SearchRequest{Term: "census", Language: "en"}, true)
+			c.So(err, c.ShouldBeNil)
+
+			searches := unmarshal(query)
+			c.So(string(searches[0].Query), c.ShouldContainSubstring,
+				`{"match":{"language":"en"}},{"bool":{"must_not":{"exists":{"field":"language"}}}}`)
+			c.So(string(searches[0].Query), c.ShouldContainSubstring, `"title.title_no_dates"`)
+		})
+
+		c.Convey("Then a search without a language is not filtered on language", func() {
+			query, err := qb.BuildSearchQuery(context.Background(), &SearchRequest{Term: "census"}, true)
+			c.So(err, c.ShouldBeNil)
+
+			searches := unmarshal(query)
+			c.So(string(searches[0].Query), c.ShouldNotContainSubstring, `"language"`)
+		})
+
+		c.Convey("Then the distinct item count of a Welsh search is filtered on language", func() {
+			query, err := qb.BuildCountQuery(context.Background(), &CountRequest{Term: "cyfrifiad", CountEnable: true, Language: "cy"})
+			c.So(err, c.ShouldBeNil)
+			c.So(string(query), c.ShouldContainSubstring, `"title.title_cy"`)
+			c.So(string(query), c.ShouldContainSubstring, `"language": "cy"`)
+		})
+	})
+}
+
+func TestParseLanguage(t *testing.T) {
+	c.Convey("Given supported languages in any case", t, func() {
+		for param, expected := range map[string]string{"en": "en", "CY": "cy"} {
+			language, err := ParseLanguage(param)
+			c.So(err, c.ShouldBeNil)
+			c.So(language, c.ShouldEqual, expected)
+		}
+	})
+
+	c.Convey("Given an unsupported language", t, func() {
+		_, err := ParseLanguage("fr")
+		c.So(err, c.ShouldNotBeNil)
+		c.So(err.Error(), c.ShouldEqual, "language must be one of: en, cy")
+	})
+}
+
+func TestParseFacets(t *testing.T) {
+	c.Convey("Given a list of facets with and without sizes", t, func() {
+		facets, err := ParseFacets("topics,population_types,dimensions:50")
+
+		c.Convey("Then the facets are requested with the provided or maximum size", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(facets, c.ShouldResemble, &Facets{
+				Topics:          &Facet{Size: MaxFacetSize},
+				PopulationTypes: &Facet{Size: MaxFacetSize},
+				Dimensions:      &Facet{Size: 50},
+			})
+		})
+	})
+
+	c.Convey("Given none", t, func() {
+		facets, err := ParseFacets("none")
+
+		c.Convey("Then no facets are requested", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(facets, c.ShouldResemble, &Facets{})
+		})
+	})
+
+	c.Convey("Given invalid facets", t, func() {
+		for _, param := range []string{"colours", "topics:", "topics:0", "topics:1001", "topics:ten", "none,topics"} {
+			facets, err := ParseFacets(param)
+
+			c.So(err, c.ShouldNotBeNil)
+			c.So(facets, c.ShouldBeNil)
+		}
+	})
+}
+
 func TestBuildSearchQueryPopulationType(t *testing.T) {
 	c.Convey("Given a Query builder", t, func() {
 		qb, err := NewQueryBuilder()
@@ -438,3 +633,29 @@ func unmarshal(query []byte) []client.Search {
 	c.So(err, c.ShouldBeNil)
 	return searches
 }
+
+func TestSearches(t *testing.T) {
+	c.Convey("Given a search request for some of the facets", t, func() {
+		req := &SearchRequest{Facets: &Facets{Topics: &Facet{Size: 10}, Dimensions: &Facet{Size: 5}}}
+
+		c.Convey("Then the content search is followed by the count search of each facet, in template order", func() {
+			c.So(req.Searches(), c.ShouldResemble, []string{ContentSearch, "topics", "dimensions"})
+		})
+	})
+
+	c.Convey("Given a search request that does not select facets", t, func() {
+		req := &SearchRequest{}
+
+		c.Convey("Then every facet count search is included", func() {
+			c.So(req.Searches(), c.ShouldResemble, []string{ContentSearch, "topics", "content_types", "population_types", "dimensions"})
+		})
+	})
+
+	c.Convey("Given a search request for a page of a cursor", t, func() {
+		req := &SearchRequest{Facets: AllFacets(), PointInTime: &PointInTime{ID: "pit"}}
+
+		c.Convey("Then only the content search is included", func() {
+			c.So(req.Searches(), c.ShouldResemble, []string{ContentSearch})
+		})
+	})
+}
@@ -0,0 +1,69 @@
+package query
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	esClient "github.com/ONSdigital/dp-elasticsearch/v3/client"
+)
+
+//go:embed templates/suggest/*.tmpl
+var suggestFS embed.FS
+
+// SuggestRequest holds the values provided by a request against the suggest endpoint,
+// used to build the title completion query
+type SuggestRequest struct {
+	Term   string
+	Size   int
+	Types  []string
+	Topics []string
+}
+
+// SuggestBuilder builds the elasticsearch queries for title completions
+type SuggestBuilder struct {
+	searchTemplates *template.Template
+}
+
+// NewSuggestBuilder loads the suggest templates and returns a suggest query builder
+func NewSuggestBuilder() (*SuggestBuilder, error) {
+	searchTemplates, err := template.ParseFS(suggestFS,
+		"templates/suggest/search.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suggest template: %w", err)
+	}
+
+	return &SuggestBuilder{
+		searchTemplates: searchTemplates,
+	}, nil
+}
+
+// BuildSearchQuery builds an elastic search query from the provided suggest parameters
+func (sb *SuggestBuilder) BuildSearchQuery(_ context.Context, suggestRequest SuggestRequest) ([]esClient.Search, error) {
+	return buildSearches(sb.searchTemplates, suggestRequest)
+}
+
+// FilterClause returns the clauses restricting the completions to the requested content types and topics
+func (sr SuggestRequest) FilterClause() string {
+	var clauses []string
+	if len(sr.Types) > 0 {
+		clauses = append(clauses, termsClause("type", sr.Types))
+	}
+	if len(sr.Topics) > 0 {
+		clauses = append(clauses, termsClause("topics", sr.Topics))
+	}
+
+	return strings.Join(clauses, Separator)
+}
+
+func termsClause(field string, values []string) string {
+	clause, err := json.Marshal(map[string]map[string][]string{"terms": {field: values}})
+	if err != nil {
+		panic("couldn't marshal the terms clause: " + err.Error())
+	}
+
+	return string(clause)
+}
@@ -0,0 +1,43 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestBuildSuggestQuery(t *testing.T) {
+	c.Convey("Given a suggest builder", t, func() {
+		sb, err := NewSuggestBuilder()
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("When a suggest query is built with content type and topic filters", func() {
+			searches, err := sb.BuildSearchQuery(context.Background(), SuggestRequest{
+				Term:   "infl",
+				Size:   8,
+				Types:  []string{"bulletin", "article"},
+				Topics: []string{"1234"},
+			})
+
+			c.Convey("Then a single completion query on the title autocomplete field is returned", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(searches, c.ShouldHaveLength, 1)
+				c.So(searches[0].Header.Index, c.ShouldEqual, "ons")
+				c.So(string(searches[0].Query), c.ShouldEqual,
+					`{"size":8,"_source":{"includes":["title","uri","type"]},"query":{"bool":{"must":{"match":{"title.title_autocomplete":{"query":"infl","operator":"and"}}},`+
+						`"filter":[{"terms":{"type":["bulletin","article"]}},{"terms":{"topics":["1234"]}}]}},"collapse":{"field":"title.title_raw"}}`)
+			})
+		})
+
+		c.Convey("When a suggest query is built without filters", func() {
+			searches, err := sb.BuildSearchQuery(context.Background(), SuggestRequest{Term: "infl", Size: 10})
+
+			c.Convey("Then the filter is empty", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(searches, c.ShouldHaveLength, 1)
+				c.So(string(searches[0].Query), c.ShouldContainSubstring, `"filter":[]`)
+			})
+		})
+	})
+}
@@ -21,15 +21,22 @@ func (qpv ParamValidator) Validate(_ context.Context, name, value string) (inter
 type validator func(param string) (interface{}, error)
 type paramName string
 
+// MaxCalendarReleases is the most releases returned in an iCalendar, which is also the number returned by default,
+// so that a subscribed calendar holds every release in its date range rather than the first page of them
+const MaxCalendarReleases = 5000
+
 // NewReleaseQueryParamValidator creates a validator to validate
 // parameters for the Release endpoint
 func NewReleaseQueryParamValidator() ParamValidator {
 	return ParamValidator{
-		"limit":        validateLimit,
-		"offset":       validateOffset,
-		"date":         validateDate,
-		"sort":         validateSort,
-		"release-type": validateReleaseType,
+		"limit":          validateLimit,
+		"calendar-limit": validateCalendarLimit,
+		"offset":         validateOffset,
+		"date":           validateDate,
+		"sort":           validateSort,
+		"release-type":   validateReleaseType,
+		"lang":           validateLanguage,
+		"interval":       validateCalendarInterval,
 	}
 }
 
@@ -43,9 +50,19 @@ func NewSearchQueryParamValidator() ParamValidator {
 		"sort": func(param string) (interface{}, error) {
 			return param, nil
 		},
+		"facets":    validateFacets,
+		"histogram": validateHistogram,
+		"lang":      validateLanguage,
+		"profile":   validateBoostProfile(DefaultBoostProfiles()),
 	}
 }
 
+// WithBoostProfiles sets the boost profiles that can be selected with the profile parameter
+func (qpv ParamValidator) WithBoostProfiles(profiles BoostProfiles) ParamValidator {
+	qpv["profile"] = validateBoostProfile(profiles)
+	return qpv
+}
+
 var validateLimit validator = func(param string) (interface{}, error) {
 	value, err := strconv.Atoi(param)
 	if err != nil {
@@ -61,6 +78,21 @@ var validateLimit validator = func(param string) (interface{}, error) {
 	return value, nil
 }
 
+var validateCalendarLimit validator = func(param string) (interface{}, error) {
+	value, err := strconv.Atoi(param)
+	if err != nil {
+		return 0, errors.New("limit search parameter provided with non numeric characters")
+	}
+	if value < 0 {
+		return 0, errors.New("limit search parameter provided with negative value")
+	}
+	if value > MaxCalendarReleases {
+		return 0, errors.New("limit search parameter provided with a value that is too high")
+	}
+
+	return value, nil
+}
+
 var validateOffset validator = func(param string) (interface{}, error) {
 	value, err := strconv.Atoi(param)
 	if err != nil {
@@ -95,3 +127,45 @@ var validateReleaseType validator = func(param string) (interface{}, error) {
 	}
 	return value, nil
 }
+
+var validateFacets validator = func(param string) (interface{}, error) {
+	value, err := ParseFacets(param)
+	if err != nil {
+		return nil, fmt.Errorf("facets parameter provided is invalid: %w", err)
+	}
+	return value, nil
+}
+
+var validateHistogram validator = func(param string) (interface{}, error) {
+	value, err := ParseHistogramInterval(param)
+	if err != nil {
+		return nil, fmt.Errorf("histogram parameter provided is invalid: %w", err)
+	}
+	return value, nil
+}
+
+var validateCalendarInterval validator = func(param string) (interface{}, error) {
+	value, err := ParseCalendarInterval(param)
+	if err != nil {
+		return nil, fmt.Errorf("interval parameter provided is invalid: %w", err)
+	}
+	return value, nil
+}
+
+var validateLanguage validator = func(param string) (interface{}, error) {
+	value, err := ParseLanguage(param)
+	if err != nil {
+		return nil, fmt.Errorf("lang parameter provided is invalid: %w", err)
+	}
+	return value, nil
+}
+
+func validateBoostProfile(profiles BoostProfiles) validator {
+	return func(param string) (interface{}, error) {
+		value, err := profiles.Get(param)
+		if err != nil {
+			return nil, fmt.Errorf("profile parameter provided is invalid: %w", err)
+		}
+		return value, nil
+	}
+}
@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often full buckets are removed from a MemoryStore, as they hold nothing a new bucket would not
+const sweepInterval = time.Minute
+
+// Limit is a token bucket: clients can make up to Burst requests at once, and Rate requests per second on average
+type Limit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// Limits are the limits of each rate limited route, keyed by its path template (e.g. /search/releases)
+type Limits map[string]Limit
+
+// ParseLimits parses and validates limits defined as a JSON object, keyed by route path template
+func ParseLimits(data []byte) (Limits, error) {
+	if len(data) == 0 {
+		return Limits{}, nil
+	}
+
+	var limits Limits
+	if err := json.Unmarshal(data, &limits); err != nil {
+		return nil, fmt.Errorf("failed to parse rate limits: %w", err)
+	}
+
+	for route, limit := range limits {
+		if !strings.HasPrefix(route, "/") {
+			return nil, fmt.Errorf("rate limited route %q must be a path starting with /", route)
+		}
+		if limit.Rate <= 0 {
+			return nil, fmt.Errorf("rate limit of %s must be greater than 0", route)
+		}
+		if limit.Burst < 1 {
+			return nil, fmt.Errorf("rate limit burst of %s must be at least 1", route)
+		}
+	}
+
+	return limits, nil
+}
+
+// Result is the outcome of taking a token from a client's bucket
+type Result struct {
+	// Allowed is whether a token was taken, so the request can be made
+	Allowed bool
+	// Remaining is the number of whole tokens left in the bucket
+	Remaining int
+	// RetryAfter is how long until a token can be taken, if none could be
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Store holds the token bucket of every client. The limiter only talks to a store through this interface,
+// so that a store shared across instances can be used in place of a MemoryStore.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// MemoryStore holds token buckets in memory, limiting the requests made to a single instance
+type MemoryStore struct {
+	mutex     sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// Take takes a token from the bucket with the key, if it has one, after refilling it for the time since it was last used
+func (ms *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	now := ms.now()
+	ms.sweep(now)
+
+	b, ok := ms.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		ms.buckets[key] = b
+	}
+
+	burst := float64(limit.Burst)
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+
+	result := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = secondsToDuration((1 - b.tokens) / limit.Rate)
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = secondsToDuration((burst - b.tokens) / limit.Rate)
+	b.full = now.Add(result.Reset)
+
+	return result, nil
+}
+
+// sweep removes the buckets that have refilled since they were last used
+func (ms *MemoryStore) sweep(now time.Time) {
+	if now.Sub(ms.lastSweep) < sweepInterval {
+		return
+	}
+	ms.lastSweep = now
+
+	for key, b := range ms.buckets {
+		if !now.Before(b.full) {
+			delete(ms.buckets, key)
+		}
+	}
+}
+
+// Len returns the number of buckets held
+func (ms *MemoryStore) Len() int {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	return len(ms.buckets)
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
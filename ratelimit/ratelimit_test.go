@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseLimits(t *testing.T) {
+	c.Convey("Given rate limits defined as JSON", t, func() {
+		limits, err := ParseLimits([]byte(`{"/search": {"rate": 5, "burst": 20}, "/search/releases": {"rate": 0.5, "burst": 1}}`))
+
+		c.Convey("Then the limit of each route is parsed", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(limits, c.ShouldResemble, Limits{
+				"/search":          {Rate: 5, Burst: 20},
+				"/search/releases": {Rate: 0.5, Burst: 1},
+			})
+		})
+	})
+
+	c.Convey("Given no rate limits", t, func() {
+		limits, err := ParseLimits(nil)
+
+		c.Convey("Then no routes are limited", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(limits, c.ShouldBeEmpty)
+		})
+	})
+
+	c.Convey("Given invalid rate limits", t, func() {
+		for data, expectedErr := range map[string]string{
+			`not json`:                              "failed to parse rate limits",
+			`{"search": {"rate": 1, "burst": 1}}`:   `rate limited route "search" must be a path starting with /`,
+			`{"/search": {"rate": 0, "burst": 1}}`:  "rate limit of /search must be greater than 0",
+			`{"/search": {"rate": 1, "burst": 0}}`:  "rate limit burst of /search must be at least 1",
+			`{"/search": {"rate": -1, "burst": 1}}`: "rate limit of /search must be greater than 0",
+		} {
+			_, err := ParseLimits([]byte(data))
+			c.So(err, c.ShouldNotBeNil)
+			c.So(err.Error(), c.ShouldContainSubstring, expectedErr)
+		}
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	limit := Limit{Rate: 2, Burst: 3}
+
+	c.Convey("Given an empty memory store", t, func() {
+		now := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		store := NewMemoryStore()
+		store.now = func() time.Time { return now }
+
+		c.Convey("When a client makes a burst of requests", func() {
+			results := make([]Result, 4)
+			for i := range results {
+				results[i], _ = store.Take(ctx, "client", limit)
+			}
+
+			c.Convey("Then requests are allowed until the bucket is empty", func() {
+				c.So(results[0], c.ShouldResemble, Result{Allowed: true, Remaining: 2, Reset: 500 * time.Millisecond})
+				c.So(results[2], c.ShouldResemble, Result{Allowed: true, Remaining: 0, Reset: 1500 * time.Millisecond})
+				c.So(results[3], c.ShouldResemble, Result{Allowed: false, Remaining: 0, RetryAfter: 500 * time.Millisecond, Reset: 1500 * time.Millisecond})
+			})
+
+			c.Convey("And other clients have their own bucket", func() {
+				result, _ := store.Take(ctx, "other client", limit)
+				c.So(result.Allowed, c.ShouldBeTrue)
+			})
+
+			c.Convey("And the bucket refills at the rate", func() {
+				now = now.Add(500 * time.Millisecond)
+				result, _ := store.Take(ctx, "client", limit)
+				c.So(result.Allowed, c.ShouldBeTrue)
+
+				result, _ = store.Take(ctx, "client", limit)
+				c.So(result.Allowed, c.ShouldBeFalse)
+			})
+
+			c.Convey("And buckets that have refilled are removed", func() {
+				c.So(store.Len(), c.ShouldEqual, 1)
+				now = now.Add(sweepInterval)
+				_, _ = store.Take(ctx, "other client", limit)
+				c.So(store.Len(), c.ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ONSdigital/dp-search-api/sdk"
+	"github.com/ONSdigital/log.go/v2/log"
+)
+
+type runConfig struct {
+	APIURL         string `json:"apiurl"`
+	JudgementsFile string `json:"judgementsFile"`
+	K              int    `json:"k"`
+	Params         string `json:"params"`
+	CompareParams  string `json:"compareParams"`
+	OutputFile     string `json:"outputFile"`
+}
+
+// searchRun is the search parameters of a run and the metrics of each query searched with them
+type searchRun struct {
+	Name    string
+	Params  url.Values
+	Metrics []queryMetrics
+}
+
+// main reads a judgement list of queries and the graded relevance of URIs for each query, searches for every query
+// using the Search API and reports the precision@k, nDCG@k and reciprocal rank of each query's results, along with
+// their means over all queries.
+//
+// The queries can be searched with additional query parameters, such as nlp_weighting=true or profile=recent, and
+// compared against a second run of the queries with different parameters, reporting the change in each metric.
+func main() {
+	ctx := context.Background()
+	log.Info(ctx, "starting script to evaluate the relevance of Search API results")
+
+	config := runConfig{}
+	flag.StringVar(&config.APIURL, "api_url", "https://api.beta.ons.gov.uk/v1", "the base url for the search api")
+	flag.StringVar(&config.JudgementsFile, "judgements_file", "judgements.csv", "name of the judgements csv file, with query, uri and grade columns")
+	flag.IntVar(&config.K, "k", 10, "number of results of each query to evaluate")
+	flag.StringVar(&config.Params, "params", "", "url encoded query parameters to search with, e.g. nlp_weighting=false")
+	flag.StringVar(&config.CompareParams, "compare_params", "", "url encoded query parameters of a second run to compare against, e.g. nlp_weighting=true")
+	flag.StringVar(&config.OutputFile, "output_file", "relevance-evaluation.csv", "name of the output csv file")
+	flag.Parse()
+	log.Info(ctx, "parsed config", log.Data{"config": config})
+
+	if err := run(ctx, config); err != nil {
+		log.Fatal(ctx, "error running", err)
+		os.Exit(1)
+	}
+	log.Info(ctx, "end of script")
+}
+
+func run(ctx context.Context, config runConfig) error {
+	if config.K < 1 {
+		return errors.New("k must be 1 or more")
+	}
+
+	queries, grades, err := readJudgements(ctx, config.JudgementsFile)
+	if err != nil {
+		return err
+	}
+
+	baseline, err := newSearchRun(config.Params)
+	if err != nil {
+		return err
+	}
+	runs := []*searchRun{baseline}
+	if config.CompareParams != "" {
+		comparison, compareErr := newSearchRun(config.CompareParams)
+		if compareErr != nil {
+			return compareErr
+		}
+		runs = append(runs, comparison)
+	}
+
+	client := sdk.New(config.APIURL)
+	for _, r := range runs {
+		log.Info(ctx, "searching for each query", log.Data{"run": r.Name})
+		for _, query := range queries {
+			results, searchErr := search(ctx, client, query, r.Params, config.K)
+			if searchErr != nil {
+				log.Error(ctx, "error getting query results", searchErr, log.Data{"query": query, "run": r.Name})
+				return searchErr
+			}
+			r.Metrics = append(r.Metrics, evaluate(results, grades[query], config.K))
+		}
+	}
+
+	if err = writeReport(os.Stdout, queries, runs, config.K); err != nil {
+		return err
+	}
+
+	return writeCSVFile(ctx, config.OutputFile, queries, runs)
+}
+
+// newSearchRun returns a run of the queries with the provided url encoded parameters, named by its parameters
+func newSearchRun(params string) (*searchRun, error) {
+	values, err := url.ParseQuery(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid params %q: %w", params, err)
+	}
+
+	name := params
+	if name == "" {
+		name = "defaults"
+	}
+	return &searchRun{Name: name, Params: values}, nil
+}
+
+// search returns the URIs of the top k results of the query
+func search(ctx context.Context, client *sdk.Client, query string, params url.Values, k int) ([]string, error) {
+	queryVals := url.Values{}
+	for key, values := range params {
+		queryVals[key] = values
+	}
+	queryVals.Set("q", query)
+	queryVals.Set("limit", strconv.Itoa(k))
+
+	response, err := client.GetSearch(ctx, sdk.Options{Query: queryVals})
+	if err != nil {
+		return nil, err
+	}
+
+	uris := make([]string, 0, len(response.Items))
+	for i := range response.Items {
+		uris = append(uris, response.Items[i].URI)
+	}
+	return uris, nil
+}
+
+// readJudgements reads the judgements csv file, returning the queries in the order they first appear and the graded
+// URIs of each query. The file may start with a query,uri,grade header row.
+func readJudgements(ctx context.Context, fileName string) (queries []string, grades map[string]judgements, err error) {
+	logData := log.Data{"file_name": fileName}
+	file, err := os.Open(fileName)
+	if err != nil {
+		log.Error(ctx, "failed opening judgements file", err, logData)
+		return nil, nil, err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			log.Error(ctx, "failed closing judgements file", closeErr, logData)
+		}
+	}()
+
+	return parseJudgements(file)
+}
+
+// parseJudgements parses judgements in csv format
+func parseJudgements(r io.Reader) (queries []string, grades map[string]judgements, err error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	grades = map[string]judgements{}
+	for line := 1; ; line++ {
+		record, readErr := reader.Read()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, nil, fmt.Errorf("failed reading judgements: %w", readErr)
+		}
+		if line == 1 && strings.EqualFold(record[0], "query") {
+			continue
+		}
+
+		query := strings.TrimSpace(record[0])
+		grade, convErr := strconv.Atoi(record[2])
+		if convErr != nil || grade < 0 {
+			return nil, nil, fmt.Errorf("invalid grade %q on line %d, grades must be 0 or more", record[2], line)
+		}
+
+		if _, ok := grades[query]; !ok {
+			queries = append(queries, query)
+			grades[query] = judgements{}
+		}
+		grades[query][strings.TrimSpace(record[1])] = grade
+	}
+
+	if len(queries) == 0 {
+		return nil, nil, errors.New("no judgements found")
+	}
+	return queries, grades, nil
+}
+
+// writeReport writes the metrics of each query and their means as a table. If two runs are provided, the change
+// in nDCG between them is also written.
+func writeReport(w io.Writer, queries []string, runs []*searchRun, k int) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, r := range runs {
+		fmt.Fprintf(tw, "run %q\n", r.Name)
+	}
+	fmt.Fprintln(tw)
+
+	header := []string{"query"}
+	for i := range runs {
+		header = append(header, fmt.Sprintf("P@%d (%d)", k, i+1), fmt.Sprintf("nDCG@%d (%d)", k, i+1), fmt.Sprintf("RR (%d)", i+1))
+	}
+	if len(runs) == 2 {
+		header = append(header, fmt.Sprintf("ΔnDCG@%d", k))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for i, query := range queries {
+		fmt.Fprintln(tw, strings.Join(reportRow(query, runs, func(r *searchRun) queryMetrics { return r.Metrics[i] }), "\t"))
+	}
+	fmt.Fprintln(tw, strings.Join(reportRow("MEAN (MRR)", runs, func(r *searchRun) queryMetrics { return mean(r.Metrics) }), "\t"))
+
+	return tw.Flush()
+}
+
+// reportRow returns the metrics of each run, and the change in nDCG between two runs
+func reportRow(name string, runs []*searchRun, metrics func(r *searchRun) queryMetrics) []string {
+	row := []string{name}
+	for _, r := range runs {
+		m := metrics(r)
+		row = append(row, formatMetric(m.Precision), formatMetric(m.NDCG), formatMetric(m.ReciprocalRank))
+	}
+	if len(runs) == 2 {
+		row = append(row, fmt.Sprintf("%+.3f", metrics(runs[1]).NDCG-metrics(runs[0]).NDCG))
+	}
+	return row
+}
+
+func formatMetric(value float64) string {
+	return strconv.FormatFloat(value, 'f', 3, 64)
+}
+
+// writeCSVFile writes the metrics of every query of every run to the output csv file, including the means as
+// the MEAN query
+func writeCSVFile(ctx context.Context, fileName string, queries []string, runs []*searchRun) error {
+	logData := log.Data{"output_filename": fileName}
+	csvFile, err := os.Create(fileName)
+	if err != nil {
+		log.Error(ctx, "error creating csv file", err, logData)
+		return err
+	}
+	defer func() {
+		if closeErr := csvFile.Close(); closeErr != nil {
+			log.Error(ctx, "failed closing csv file", closeErr, logData)
+		}
+	}()
+
+	csvWriter := csv.NewWriter(csvFile)
+	if err = csvWriter.Write([]string{"run", "query", "precision", "ndcg", "reciprocal_rank"}); err != nil {
+		return err
+	}
+	for _, r := range runs {
+		for i, query := range queries {
+			if err = csvWriter.Write(csvRow(r.Name, query, r.Metrics[i])); err != nil {
+				return err
+			}
+		}
+		if err = csvWriter.Write(csvRow(r.Name, "MEAN", mean(r.Metrics))); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	if err = csvWriter.Error(); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "successfully wrote csv file", logData)
+	return nil
+}
+
+func csvRow(run, query string, m queryMetrics) []string {
+	return []string{run, query, formatMetric(m.Precision), formatMetric(m.NDCG), formatMetric(m.ReciprocalRank)}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math"
+	"sort"
+)
+
+// judgements are the graded relevance of URIs for a query, by URI. Grades are 0 (not relevant) or more.
+type judgements map[string]int
+
+// queryMetrics are the relevance metrics of the results of a query
+type queryMetrics struct {
+	Precision      float64
+	NDCG           float64
+	ReciprocalRank float64
+}
+
+// evaluate calculates precision@k, nDCG@k and the reciprocal rank of the top k results of a query
+func evaluate(results []string, grades judgements, k int) queryMetrics {
+	if len(results) > k {
+		results = results[:k]
+	}
+
+	var metrics queryMetrics
+	relevant := 0
+	dcg := 0.0
+	for i, uri := range results {
+		grade := grades[uri]
+		if grade <= 0 {
+			continue
+		}
+		relevant++
+		dcg += gain(grade, i)
+		if metrics.ReciprocalRank == 0 {
+			metrics.ReciprocalRank = 1 / float64(i+1)
+		}
+	}
+
+	if k > 0 {
+		metrics.Precision = float64(relevant) / float64(k)
+	}
+	if idcg := idealDCG(grades, k); idcg > 0 {
+		metrics.NDCG = dcg / idcg
+	}
+
+	return metrics
+}
+
+// gain is the discounted gain of a result with the provided grade at the provided (zero based) position
+func gain(grade, position int) float64 {
+	return (math.Pow(2, float64(grade)) - 1) / math.Log2(float64(position)+2)
+}
+
+// idealDCG is the DCG of the best possible ordering of the judged URIs
+func idealDCG(grades judgements, k int) float64 {
+	ideal := make([]int, 0, len(grades))
+	for _, grade := range grades {
+		if grade > 0 {
+			ideal = append(ideal, grade)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(ideal)))
+
+	idcg := 0.0
+	for i := 0; i < len(ideal) && i < k; i++ {
+		idcg += gain(ideal[i], i)
+	}
+	return idcg
+}
+
+// mean returns the mean of the metrics of every query, where MRR is the mean of the reciprocal ranks
+func mean(metrics []queryMetrics) queryMetrics {
+	var total queryMetrics
+	if len(metrics) == 0 {
+		return total
+	}
+
+	for _, m := range metrics {
+		total.Precision += m.Precision
+		total.NDCG += m.NDCG
+		total.ReciprocalRank += m.ReciprocalRank
+	}
+
+	n := float64(len(metrics))
+	return queryMetrics{
+		Precision:      total.Precision / n,
+		NDCG:           total.NDCG / n,
+		ReciprocalRank: total.ReciprocalRank / n,
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEvaluate(t *testing.T) {
+	c.Convey("Given graded judgements for a query", t, func() {
+		grades := judgements{"/a": 3, "/b": 1, "/c": 0, "/d": 2}
+
+		c.Convey("When the results are in the ideal order", func() {
+			metrics := evaluate([]string{"/a", "/d", "/b", "/x"}, grades, 4)
+
+			c.Convey("Then nDCG and the reciprocal rank are 1", func() {
+				c.So(metrics.Precision, c.ShouldEqual, 0.75)
+				c.So(metrics.NDCG, c.ShouldAlmostEqual, 1)
+				c.So(metrics.ReciprocalRank, c.ShouldEqual, 1)
+			})
+		})
+
+		c.Convey("When the first relevant result is third and only the top 3 results are evaluated", func() {
+			metrics := evaluate([]string{"/c", "/x", "/b", "/a"}, grades, 3)
+
+			c.Convey("Then the metrics only count the top 3 results", func() {
+				c.So(metrics.Precision, c.ShouldAlmostEqual, 1.0/3)
+				// DCG of grade 1 at position 3 over the ideal DCG of grades 3, 2 and 1
+				idcg := 7 + 3/math.Log2(3) + 1/math.Log2(4)
+				c.So(metrics.NDCG, c.ShouldAlmostEqual, (1/math.Log2(4))/idcg)
+				c.So(metrics.ReciprocalRank, c.ShouldAlmostEqual, 1.0/3)
+			})
+		})
+
+		c.Convey("When no results are relevant", func() {
+			metrics := evaluate([]string{"/c", "/x"}, grades, 10)
+
+			c.Convey("Then every metric is 0", func() {
+				c.So(metrics, c.ShouldResemble, queryMetrics{})
+			})
+		})
+	})
+
+	c.Convey("Given the metrics of several queries", t, func() {
+		metrics := []queryMetrics{{Precision: 0.5, NDCG: 0.8, ReciprocalRank: 1}, {Precision: 0.1, NDCG: 0.2, ReciprocalRank: 0.5}}
+
+		c.Convey("Then their mean is the mean of each metric", func() {
+			m := mean(metrics)
+			c.So(m.Precision, c.ShouldAlmostEqual, 0.3)
+			c.So(m.NDCG, c.ShouldAlmostEqual, 0.5)
+			c.So(m.ReciprocalRank, c.ShouldAlmostEqual, 0.75)
+		})
+	})
+}
+
+func TestParseJudgements(t *testing.T) {
+	c.Convey("Given a judgements file with a header row", t, func() {
+		queries, grades, err := parseJudgements(strings.NewReader("query,uri,grade\ncpi,/a,3\nrpi,/b,1\ncpi, /c, 0\n"))
+
+		c.Convey("Then the queries are returned in order with their graded URIs", func() {
+			c.So(err, c.ShouldBeNil)
+			c.So(queries, c.ShouldResemble, []string{"cpi", "rpi"})
+			c.So(grades["cpi"], c.ShouldResemble, judgements{"/a": 3, "/c": 0})
+		})
+	})
+
+	c.Convey("Given a judgements file with an invalid grade", t, func() {
+		_, _, err := parseJudgements(strings.NewReader("cpi,/a,high\n"))
+
+		c.Convey("Then an error is returned", func() {
+			c.So(err.Error(), c.ShouldEqual, `invalid grade "high" on line 1, grades must be 0 or more`)
+		})
+	})
+}
@@ -0,0 +1,118 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ONSdigital/dp-net/v3/request"
+	apiError "github.com/ONSdigital/dp-search-api/sdk/errors"
+)
+
+// maxCachedResponses is the number of response bodies kept for conditional requests, the oldest is evicted first
+const maxCachedResponses = 100
+
+// cachedResponse is a response body along with the entity tag it was returned with
+type cachedResponse struct {
+	etag string
+	body []byte
+}
+
+// responseCache keeps the bodies of responses that were returned with an entity tag, keyed by request URL and headers,
+// so that they can be reused when the search API responds 304 Not Modified
+type responseCache struct {
+	mutex     sync.Mutex
+	responses map[string]*cachedResponse
+	order     []string
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{
+		responses: map[string]*cachedResponse{},
+	}
+}
+
+// responseCacheKey returns the key of a request's cached response. Responses can vary by request header, e.g. by the
+// session header used to assign experiment variants, so every header is included other than the conditional and
+// request ID headers, which differ for every request.
+func responseCacheKey(path string, headers http.Header) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		switch http.CanonicalHeaderKey(name) {
+		case "If-None-Match", request.RequestHeaderKey:
+		default:
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var key strings.Builder
+	key.WriteString(path)
+	for _, name := range names {
+		key.WriteString("\n" + http.CanonicalHeaderKey(name) + ": " + strings.Join(headers[name], ","))
+	}
+	return key.String()
+}
+
+func (rc *responseCache) get(key string) (*cachedResponse, bool) {
+	if rc == nil {
+		return nil, false
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	response, ok := rc.responses[key]
+	return response, ok
+}
+
+func (rc *responseCache) set(key, etag string, body []byte) {
+	if rc == nil {
+		return
+	}
+
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	if _, ok := rc.responses[key]; !ok {
+		rc.order = append(rc.order, key)
+	}
+	rc.responses[key] = &cachedResponse{etag: etag, body: body}
+
+	for len(rc.order) > maxCachedResponses {
+		delete(rc.responses, rc.order[0])
+		rc.order = rc.order[1:]
+	}
+}
+
+// callSearchAPIConditional makes a GET request to the Search API, sending the entity tag of the response previously
+// returned for the path and headers. If the search API responds 304 Not Modified, the body of the previous response
+// is returned.
+func (cli *Client) callSearchAPIConditional(ctx context.Context, path string, headers http.Header) (*ResponseInfo, apiError.Error) {
+	key := responseCacheKey(path, headers)
+	cached, ok := cli.responses.get(key)
+	if ok {
+		headers = headers.Clone()
+		if headers == nil {
+			headers = http.Header{}
+		}
+		headers.Set("If-None-Match", cached.etag)
+	}
+
+	respInfo, apiErr := cli.callSearchAPI(ctx, path, http.MethodGet, headers, nil)
+	if apiErr != nil {
+		return respInfo, apiErr
+	}
+
+	if respInfo.Status == http.StatusNotModified && ok {
+		respInfo.Body = cached.body
+		return respInfo, nil
+	}
+
+	if etag := respInfo.Headers.Get("ETag"); etag != "" {
+		cli.responses.set(key, etag, respInfo.Body)
+	}
+	return respInfo, nil
+}
@@ -11,6 +11,7 @@ import (
 
 	healthcheck "github.com/ONSdigital/dp-api-clients-go/v2/health"
 	health "github.com/ONSdigital/dp-healthcheck/healthcheck"
+	"github.com/ONSdigital/dp-net/v3/request"
 	"github.com/ONSdigital/dp-search-api/api"
 	"github.com/ONSdigital/dp-search-api/models"
 	apiError "github.com/ONSdigital/dp-search-api/sdk/errors"
@@ -22,13 +23,15 @@ const (
 )
 
 type Client struct {
-	hcCli *healthcheck.Client
+	hcCli     *healthcheck.Client
+	responses *responseCache
 }
 
 // New creates a new instance of Client with a given search api url
 func New(searchAPIURL string) *Client {
 	return &Client{
-		hcCli: healthcheck.NewClient(service, searchAPIURL),
+		hcCli:     healthcheck.NewClient(service, searchAPIURL),
+		responses: newResponseCache(),
 	}
 }
 
@@ -36,7 +39,8 @@ func New(searchAPIURL string) *Client {
 // reusing the URL and Clienter from the provided healthcheck client
 func NewWithHealthClient(hcCli *healthcheck.Client) *Client {
 	return &Client{
-		hcCli: healthcheck.NewClientWithClienter(service, hcCli.URL, hcCli.Client),
+		hcCli:     healthcheck.NewClientWithClienter(service, hcCli.URL, hcCli.Client),
+		responses: newResponseCache(),
 	}
 }
 
@@ -55,14 +59,15 @@ func (cli *Client) Checker(ctx context.Context, check *health.CheckState) error
 	return cli.hcCli.Checker(ctx, check)
 }
 
-// GetReleaseCalendarEntries gets a list of release calendar entries based on the search request
+// GetReleaseCalendarEntries gets a list of release calendar entries based on the search request.
+// The previous response is reused if the release calendar entries have not been modified since.
 func (cli *Client) GetReleaseCalendarEntries(ctx context.Context, options Options) (*transformer.SearchReleaseResponse, apiError.Error) {
 	path := fmt.Sprintf("%s/search/releases", cli.hcCli.URL)
 	if options.Query != nil {
 		path = path + "?" + options.Query.Encode()
 	}
 
-	respInfo, apiErr := cli.callSearchAPI(ctx, path, http.MethodGet, options.Headers, nil)
+	respInfo, apiErr := cli.callSearchAPIConditional(ctx, path, options.Headers)
 	if apiErr != nil {
 		return nil, apiErr
 	}
@@ -78,14 +83,15 @@ func (cli *Client) GetReleaseCalendarEntries(ctx context.Context, options Option
 	return &searchResponse, nil
 }
 
-// GetSearch gets a list of search results based on the search request
+// GetSearch gets a list of search results based on the search request.
+// The previous response is reused if the search results have not been modified since.
 func (cli *Client) GetSearch(ctx context.Context, options Options) (*models.SearchResponse, apiError.Error) {
 	path := fmt.Sprintf("%s/search", cli.hcCli.URL)
 	if options.Query != nil {
 		path = path + "?" + options.Query.Encode()
 	}
 
-	respInfo, apiErr := cli.callSearchAPI(ctx, path, http.MethodGet, options.Headers, nil)
+	respInfo, apiErr := cli.callSearchAPIConditional(ctx, path, options.Headers)
 	if apiErr != nil {
 		return nil, apiErr
 	}
@@ -97,10 +103,38 @@ func (cli *Client) GetSearch(ctx context.Context, options Options) (*models.Sear
 			Err: fmt.Errorf("failed to unmarshal search response - error is: %v", err),
 		}
 	}
+	// a body reused for 304 Not Modified holds the ID of the previous request, so the ID of this one is taken from
+	// its header, so that clicks are joined to the impression recorded for it
+	if requestID := respInfo.Headers.Get(request.RequestHeaderKey); requestID != "" {
+		searchResponse.RequestID = requestID
+	}
 
 	return &searchResponse, nil
 }
 
+// GetSuggestions gets a list of title completions for the partial search term in the request
+func (cli *Client) GetSuggestions(ctx context.Context, options Options) (*models.SuggestResponse, apiError.Error) {
+	path := fmt.Sprintf("%s/search/suggest", cli.hcCli.URL)
+	if options.Query != nil {
+		path = path + "?" + options.Query.Encode()
+	}
+
+	respInfo, apiErr := cli.callSearchAPI(ctx, path, http.MethodGet, options.Headers, nil)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	var suggestResponse models.SuggestResponse
+
+	if err := json.Unmarshal(respInfo.Body, &suggestResponse); err != nil {
+		return nil, apiError.StatusError{
+			Err: fmt.Errorf("failed to unmarshal suggest response - error is: %v", err),
+		}
+	}
+
+	return &suggestResponse, nil
+}
+
 // PostSearch creates a new search index
 func (cli *Client) CreateIndex(ctx context.Context, options Options) (*models.CreateIndexResponse, apiError.Error) {
 	path := fmt.Sprintf("%s/search", cli.hcCli.URL)
@@ -121,12 +155,75 @@ func (cli *Client) CreateIndex(ctx context.Context, options Options) (*models.Cr
 	return &searchResponse, nil
 }
 
+// GetIndexes lists the search indexes and the aliases they hold
+func (cli *Client) GetIndexes(ctx context.Context, options Options) (*models.IndexesResponse, apiError.Error) {
+	path := fmt.Sprintf("%s/search/indexes", cli.hcCli.URL)
+
+	respInfo, apiErr := cli.callSearchAPI(ctx, path, http.MethodGet, options.Headers, nil)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	var indexesResponse models.IndexesResponse
+
+	if err := json.Unmarshal(respInfo.Body, &indexesResponse); err != nil {
+		return nil, apiError.StatusError{
+			Err: fmt.Errorf("failed to unmarshal indexes response - error is: %v", err),
+		}
+	}
+
+	return &indexesResponse, nil
+}
+
+// UpdateIndexAlias moves the search alias onto the given index
+func (cli *Client) UpdateIndexAlias(ctx context.Context, options Options, indexName string) (*models.UpdateAliasResponse, apiError.Error) {
+	path := fmt.Sprintf("%s/search/indexes/%s/alias", cli.hcCli.URL, url.PathEscape(indexName))
+
+	respInfo, apiErr := cli.callSearchAPI(ctx, path, http.MethodPut, options.Headers, nil)
+	if apiErr != nil {
+		return nil, apiErr
+	}
+
+	var aliasResponse models.UpdateAliasResponse
+
+	if err := json.Unmarshal(respInfo.Body, &aliasResponse); err != nil {
+		return nil, apiError.StatusError{
+			Err: fmt.Errorf("failed to unmarshal update alias response - error is: %v", err),
+		}
+	}
+
+	return &aliasResponse, nil
+}
+
+// DeleteIndex deletes the given search index
+func (cli *Client) DeleteIndex(ctx context.Context, options Options, indexName string) apiError.Error {
+	path := fmt.Sprintf("%s/search/indexes/%s", cli.hcCli.URL, url.PathEscape(indexName))
+
+	_, apiErr := cli.callSearchAPI(ctx, path, http.MethodDelete, options.Headers, nil)
+	return apiErr
+}
+
 type ResponseInfo struct {
 	Body    []byte
 	Headers http.Header
 	Status  int
 }
 
+// PostSearchFeedback sends a POST request to the /search/feedback endpoint, recording a click on a search result
+func (cli *Client) PostSearchFeedback(ctx context.Context, options Options, feedback models.Feedback) apiError.Error {
+	path := fmt.Sprintf("%s/search/feedback", cli.hcCli.URL)
+
+	body, err := json.Marshal(feedback)
+	if err != nil {
+		return apiError.StatusError{
+			Err: fmt.Errorf("failed to marshal request body - error is: %v", err),
+		}
+	}
+
+	_, apiErr := cli.callSearchAPI(ctx, path, http.MethodPost, options.Headers, body)
+	return apiErr
+}
+
 // PostSearchURIs sends a POST request to the /search/uris endpoint
 func (cli *Client) PostSearchURIs(ctx context.Context, options Options, urisRequest api.URIsRequest) (*models.SearchResponse, apiError.Error) {
 	path := fmt.Sprintf("%s/search/uris", cli.hcCli.URL)
@@ -206,10 +303,11 @@ func (cli *Client) callSearchAPI(ctx context.Context, path, method string, heade
 	}
 
 	if resp.StatusCode < http.StatusOK || resp.StatusCode >= 400 {
-		return respInfo, apiError.StatusError{
-			Err:  fmt.Errorf("failed as unexpected code from search api: %v", resp.StatusCode),
-			Code: resp.StatusCode,
+		var body []byte
+		if resp.Body != nil {
+			body, _ = io.ReadAll(resp.Body)
 		}
+		return respInfo, apiError.NewStatusError(resp.StatusCode, body)
 	}
 
 	if resp.Body == nil {
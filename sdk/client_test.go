@@ -16,6 +16,7 @@ import (
 	dphttp "github.com/ONSdigital/dp-net/v3/http"
 	"github.com/ONSdigital/dp-search-api/api"
 	"github.com/ONSdigital/dp-search-api/models"
+	apiError "github.com/ONSdigital/dp-search-api/sdk/errors"
 	"github.com/ONSdigital/dp-search-api/transformer"
 	c "github.com/smartystreets/goconvey/convey"
 )
@@ -53,6 +54,17 @@ var (
 		},
 	}
 
+	suggestResults = models.SuggestResponse{
+		Took: 2,
+		Suggestions: []models.Suggestion{
+			{
+				Title: "Inflation and price indices",
+				URI:   "/economy/inflationandpriceindices",
+				Type:  "product_page",
+			},
+		},
+	}
+
 	releaseCalendarResults = transformer.SearchReleaseResponse{
 		Took: 20,
 		Breakdown: transformer.Breakdown{
@@ -314,6 +326,79 @@ func TestGetSearch(t *testing.T) {
 			})
 		})
 	})
+
+	c.Convey("Given a request to find search results with invalid parameters", t, func() {
+		body := []byte(`{"errors":[{"code":"invalid_parameter","description":"invalid limit parameter","param":"limit","value":"ten"}]}`)
+
+		httpClient := newMockHTTPClient(
+			&http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(bytes.NewReader(body)),
+			},
+			nil)
+
+		searchAPIClient := newSearchAPIClient(t, httpClient)
+
+		c.Convey("When GetSearch is called", func() {
+			query := url.Values{}
+			query.Add("limit", "ten")
+			resp, err := searchAPIClient.GetSearch(ctx, Options{Query: query})
+
+			c.Convey("Then the errors reported by the search API are returned", func() {
+				c.So(resp, c.ShouldBeNil)
+				c.So(err.Status(), c.ShouldEqual, http.StatusBadRequest)
+				c.So(apiError.HasCode(err, "invalid_parameter"), c.ShouldBeTrue)
+				c.So(apiError.ErrorDetails(err), c.ShouldResemble, []apiError.ErrorDetail{
+					{Code: "invalid_parameter", Description: "invalid limit parameter", Param: "limit", Value: "ten"},
+				})
+			})
+		})
+	})
+}
+
+func TestGetSuggestions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c.Convey("Given request to find title completions", t, func() {
+		body, err := json.Marshal(suggestResults)
+		if err != nil {
+			t.Errorf("failed to setup test data, error: %v", err)
+		}
+
+		httpClient := newMockHTTPClient(
+			&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(body)),
+			},
+			nil)
+
+		searchAPIClient := newSearchAPIClient(t, httpClient)
+
+		c.Convey("When GetSuggestions is called", func() {
+			query := url.Values{}
+			query.Add("q", "infl")
+			query.Add("limit", "8")
+			resp, err := searchAPIClient.GetSuggestions(ctx, Options{Query: query})
+
+			c.Convey("Then the expected response body is returned", func() {
+				c.So(*resp, c.ShouldResemble, suggestResults)
+
+				c.Convey("And no error is returned", func() {
+					c.So(err, c.ShouldBeNil)
+
+					c.Convey("And client.Do should be called once with the expected parameters", func() {
+						doCalls := httpClient.DoCalls()
+						c.So(doCalls, c.ShouldHaveLength, 1)
+						c.So(doCalls[0].Req.Method, c.ShouldEqual, "GET")
+						c.So(doCalls[0].Req.URL.Path, c.ShouldEqual, "/search/suggest")
+						c.So(doCalls[0].Req.URL.Query().Get("q"), c.ShouldEqual, "infl")
+						c.So(doCalls[0].Req.URL.Query().Get("limit"), c.ShouldEqual, "8")
+					})
+				})
+			})
+		})
+	})
 }
 
 func TestGetReleaseCalendar(t *testing.T) {
@@ -360,6 +445,102 @@ func TestGetReleaseCalendar(t *testing.T) {
 	})
 }
 
+func TestGetReleaseCalendarNotModified(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c.Convey("Given release calendar results that have not been modified since they were last requested", t, func() {
+		body, err := json.Marshal(releaseCalendarResults)
+		if err != nil {
+			t.Errorf("failed to setup test data, error: %v", err)
+		}
+
+		httpClient := newMockHTTPClient(nil, nil)
+		httpClient.DoFunc = func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			if req.Header.Get("If-None-Match") == `"abc123"` {
+				return &http.Response{StatusCode: http.StatusNotModified, Body: http.NoBody}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Etag": {`"abc123"`}},
+				Body:       io.NopCloser(bytes.NewReader(body)),
+			}, nil
+		}
+
+		searchAPIClient := newSearchAPIClient(t, httpClient)
+		query := url.Values{"q": {"census"}}
+		_, err = searchAPIClient.GetReleaseCalendarEntries(ctx, Options{Query: query})
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("When GetReleaseCalendarEntries is called again", func() {
+			resp, err := searchAPIClient.GetReleaseCalendarEntries(ctx, Options{Query: query})
+
+			c.Convey("Then the entity tag of the previous response is sent", func() {
+				doCalls := httpClient.DoCalls()
+				c.So(doCalls, c.ShouldHaveLength, 2)
+				c.So(doCalls[0].Req.Header.Get("If-None-Match"), c.ShouldBeEmpty)
+				c.So(doCalls[1].Req.Header.Get("If-None-Match"), c.ShouldEqual, `"abc123"`)
+
+				c.Convey("And the previous response body is returned", func() {
+					c.So(err, c.ShouldBeNil)
+					c.So(*resp, c.ShouldResemble, releaseCalendarResults)
+				})
+			})
+		})
+	})
+}
+
+func TestGetSearchNotModified(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	c.Convey("Given search results that have not been modified since they were last requested", t, func() {
+		previous := searchResults
+		previous.RequestID = "r1"
+		body, err := json.Marshal(previous)
+		if err != nil {
+			t.Errorf("failed to setup test data, error: %v", err)
+		}
+
+		httpClient := newMockHTTPClient(nil, nil)
+		httpClient.DoFunc = func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			if req.Header.Get("If-None-Match") == `W/"abc123"` {
+				return &http.Response{StatusCode: http.StatusNotModified, Header: http.Header{"X-Request-Id": {"r2"}}, Body: http.NoBody}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Etag": {`W/"abc123"`}, "X-Request-Id": {"r1"}},
+				Body:       io.NopCloser(bytes.NewReader(body)),
+			}, nil
+		}
+
+		searchAPIClient := newSearchAPIClient(t, httpClient)
+		query := url.Values{"q": {"census"}}
+		_, err = searchAPIClient.GetSearch(ctx, Options{Query: query, Headers: http.Header{"X-Session-Id": {"s1"}}})
+		c.So(err, c.ShouldBeNil)
+
+		c.Convey("When GetSearch is called again with the same headers", func() {
+			resp, err := searchAPIClient.GetSearch(ctx, Options{Query: query, Headers: http.Header{"X-Session-Id": {"s1"}}})
+
+			c.Convey("Then the previous response body is returned with the ID of the new request", func() {
+				c.So(httpClient.DoCalls()[1].Req.Header.Get("If-None-Match"), c.ShouldEqual, `W/"abc123"`)
+				c.So(err, c.ShouldBeNil)
+				c.So(resp.Items, c.ShouldResemble, searchResults.Items)
+				c.So(resp.RequestID, c.ShouldEqual, "r2")
+			})
+		})
+
+		c.Convey("When GetSearch is called again with a different session", func() {
+			_, err := searchAPIClient.GetSearch(ctx, Options{Query: query, Headers: http.Header{"X-Session-Id": {"s2"}}})
+
+			c.Convey("Then the entity tag of the previous response is not sent", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(httpClient.DoCalls()[1].Req.Header.Get("If-None-Match"), c.ShouldBeEmpty)
+			})
+		})
+	})
+}
+
 func TestGetSearchURIs(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -464,6 +645,50 @@ func TestGetSearchURIs(t *testing.T) {
 	})
 }
 
+func TestPostSearchFeedback(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	feedback := models.Feedback{RequestID: "r1", Query: "cpi", URI: "/economy/cpi", Position: 2}
+
+	c.Convey("Given a request to record a click on a search result", t, func() {
+		httpClient := newMockHTTPClient(&http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil)
+		searchAPIClient := newSearchAPIClient(t, httpClient)
+
+		c.Convey("When PostSearchFeedback is called", func() {
+			err := searchAPIClient.PostSearchFeedback(ctx, Options{}, feedback)
+
+			c.Convey("Then no error is returned", func() {
+				c.So(err, c.ShouldBeNil)
+
+				c.Convey("And the click is posted to the feedback endpoint", func() {
+					doCalls := httpClient.DoCalls()
+					c.So(doCalls, c.ShouldHaveLength, 1)
+					c.So(doCalls[0].Req.Method, c.ShouldEqual, "POST")
+					c.So(doCalls[0].Req.URL.Path, c.ShouldEqual, "/search/feedback")
+					var sent models.Feedback
+					c.So(json.NewDecoder(doCalls[0].Req.Body).Decode(&sent), c.ShouldBeNil)
+					c.So(sent, c.ShouldResemble, feedback)
+				})
+			})
+		})
+	})
+
+	c.Convey("Given a 400 response from search API", t, func() {
+		httpClient := newMockHTTPClient(&http.Response{StatusCode: http.StatusBadRequest}, nil)
+		searchAPIClient := newSearchAPIClient(t, httpClient)
+
+		c.Convey("When PostSearchFeedback is called", func() {
+			err := searchAPIClient.PostSearchFeedback(ctx, Options{}, feedback)
+
+			c.Convey("Then an error should be returned", func() {
+				c.So(err, c.ShouldNotBeNil)
+				c.So(err.Status(), c.ShouldEqual, http.StatusBadRequest)
+			})
+		})
+	})
+}
+
 func newMockHTTPClient(r *http.Response, err error) *dphttp.ClienterMock {
 	return &dphttp.ClienterMock{
 		SetPathsWithNoRetriesFunc: func(paths []string) {
@@ -481,3 +706,113 @@ func newSearchAPIClient(_ *testing.T, httpClient *dphttp.ClienterMock) *Client {
 	healthClient := healthcheck.NewClientWithClienter(service, testHost, httpClient)
 	return NewWithHealthClient(healthClient)
 }
+
+func TestIndexes(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	headers := http.Header{
+		Authorization: {"Bearer authorised-user"},
+	}
+
+	c.Convey("Given request is authorised to list the search indexes", t, func() {
+		indexesResponse := models.IndexesResponse{
+			Alias:   "ons",
+			Indexes: []models.Index{{Name: "ons1700000000000000", Aliases: []string{"ons"}}},
+		}
+		body, err := json.Marshal(indexesResponse)
+		if err != nil {
+			t.Errorf("failed to setup test data, error: %v", err)
+		}
+
+		httpClient := newMockHTTPClient(
+			&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(body)),
+			},
+			nil)
+
+		searchAPIClient := newSearchAPIClient(t, httpClient)
+
+		c.Convey("When GetIndexes is called", func() {
+			resp, err := searchAPIClient.GetIndexes(ctx, Options{Headers: headers})
+
+			c.Convey("Then the expected response body is returned", func() {
+				c.So(*resp, c.ShouldResemble, indexesResponse)
+
+				c.Convey("And no error is returned", func() {
+					c.So(err, c.ShouldBeNil)
+
+					c.Convey("And client.Do should be called once with the expected parameters", func() {
+						doCalls := httpClient.DoCalls()
+						c.So(doCalls, c.ShouldHaveLength, 1)
+						c.So(doCalls[0].Req.Method, c.ShouldEqual, "GET")
+						c.So(doCalls[0].Req.URL.Path, c.ShouldEqual, "/search/indexes")
+						c.So(doCalls[0].Req.Header["Authorization"], c.ShouldResemble, []string{"Bearer authorised-user"})
+					})
+				})
+			})
+		})
+	})
+
+	c.Convey("Given request is authorised to move the search alias", t, func() {
+		aliasResponse := models.UpdateAliasResponse{
+			Alias:           "ons",
+			IndexName:       "ons1700000000000000",
+			PreviousIndexes: []string{"ons1600000000000000"},
+		}
+		body, err := json.Marshal(aliasResponse)
+		if err != nil {
+			t.Errorf("failed to setup test data, error: %v", err)
+		}
+
+		httpClient := newMockHTTPClient(
+			&http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(body)),
+			},
+			nil)
+
+		searchAPIClient := newSearchAPIClient(t, httpClient)
+
+		c.Convey("When UpdateIndexAlias is called", func() {
+			resp, err := searchAPIClient.UpdateIndexAlias(ctx, Options{Headers: headers}, "ons1700000000000000")
+
+			c.Convey("Then the expected response body is returned", func() {
+				c.So(*resp, c.ShouldResemble, aliasResponse)
+
+				c.Convey("And no error is returned", func() {
+					c.So(err, c.ShouldBeNil)
+
+					c.Convey("And client.Do should be called once with the expected parameters", func() {
+						doCalls := httpClient.DoCalls()
+						c.So(doCalls, c.ShouldHaveLength, 1)
+						c.So(doCalls[0].Req.Method, c.ShouldEqual, "PUT")
+						c.So(doCalls[0].Req.URL.Path, c.ShouldEqual, "/search/indexes/ons1700000000000000/alias")
+					})
+				})
+			})
+		})
+	})
+
+	c.Convey("Given a 409 response from search api when deleting an index", t, func() {
+		httpClient := newMockHTTPClient(&http.Response{StatusCode: http.StatusConflict}, nil)
+		searchAPIClient := newSearchAPIClient(t, httpClient)
+
+		c.Convey("When DeleteIndex is called", func() {
+			err := searchAPIClient.DeleteIndex(ctx, Options{Headers: headers}, "ons1700000000000000")
+
+			c.Convey("Then an error should be returned ", func() {
+				c.So(err, c.ShouldNotBeNil)
+				c.So(err.Status(), c.ShouldEqual, http.StatusConflict)
+
+				c.Convey("And client.Do should be called once with the expected parameters", func() {
+					doCalls := httpClient.DoCalls()
+					c.So(doCalls, c.ShouldHaveLength, 1)
+					c.So(doCalls[0].Req.Method, c.ShouldEqual, "DELETE")
+					c.So(doCalls[0].Req.URL.Path, c.ShouldEqual, "/search/indexes/ons1700000000000000")
+				})
+			})
+		})
+	})
+}
@@ -1,6 +1,11 @@
 package errors
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
 
 // Error represents a handler error. It provides methods for a HTTP status
 // code and embeds the built-in error interface.
@@ -9,10 +14,54 @@ type Error interface {
 	Status() int
 }
 
-// StatusError represents an error with an associated HTTP status code.
+// StatusError represents an error with an associated HTTP status code,
+// along with the errors reported in the search API's error response, if any.
 type StatusError struct {
-	Code int
-	Err  error
+	Code   int
+	Err    error
+	Errors []ErrorDetail
+}
+
+// ErrorDetail is one of the errors reported in a search API error response. The codes are listed in the
+// search API's apierrors package and do not change, and the param and value are those of the invalid query parameter.
+type ErrorDetail struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Param       string `json:"param,omitempty"`
+	Value       string `json:"value,omitempty"`
+}
+
+// Allows ErrorDetail to satisfy the error interface.
+func (ed ErrorDetail) Error() string {
+	if ed.Param == "" {
+		return ed.Description
+	}
+	return fmt.Sprintf("%s: %s", ed.Param, ed.Description)
+}
+
+// NewStatusError returns a StatusError for an unsuccessful search API response with the status code,
+// holding the errors in the response body if it is a search API error response
+func NewStatusError(code int, body []byte) StatusError {
+	var response struct {
+		Errors []ErrorDetail `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || len(response.Errors) == 0 {
+		return StatusError{
+			Err:  fmt.Errorf("failed as unexpected code from search api: %v", code),
+			Code: code,
+		}
+	}
+
+	descriptions := make([]string, len(response.Errors))
+	for i := range response.Errors {
+		descriptions[i] = response.Errors[i].Error()
+	}
+
+	return StatusError{
+		Err:    fmt.Errorf("failed as unexpected code from search api: %v: %s", code, strings.Join(descriptions, "; ")),
+		Code:   code,
+		Errors: response.Errors,
+	}
 }
 
 // Allows StatusError to satisfy the error interface.
@@ -48,3 +97,24 @@ func ErrorMessage(err error) string {
 
 	return err.Error()
 }
+
+// ErrorDetails returns the errors reported in the search API's error response, if any
+func ErrorDetails(err error) []ErrorDetail {
+	var serr StatusError
+	if errors.As(err, &serr) {
+		return serr.Errors
+	}
+
+	return nil
+}
+
+// HasCode returns whether the search API reported an error with the code
+func HasCode(err error, code string) bool {
+	for _, detail := range ErrorDetails(err) {
+		if detail.Code == code {
+			return true
+		}
+	}
+
+	return false
+}
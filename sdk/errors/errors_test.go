@@ -49,3 +49,42 @@ func Test(t *testing.T) {
 		})
 	})
 }
+
+func TestNewStatusError(t *testing.T) {
+	t.Parallel()
+
+	c.Convey("given a search api error response", t, func() {
+		body := []byte(`{"errors":[
+			{"code":"invalid_parameter","description":"invalid limit parameter","param":"limit","value":"x"},
+			{"code":"conflicting_parameters","description":"offset cannot be used with cursor","param":"offset","value":"10"}
+		]}`)
+
+		c.Convey("when a status error is created from it", func() {
+			sErr := NewStatusError(400, body)
+
+			c.Convey("then the errors are parsed", func() {
+				c.So(sErr.Status(), c.ShouldEqual, 400)
+				c.So(ErrorDetails(sErr), c.ShouldResemble, []ErrorDetail{
+					{Code: "invalid_parameter", Description: "invalid limit parameter", Param: "limit", Value: "x"},
+					{Code: "conflicting_parameters", Description: "offset cannot be used with cursor", Param: "offset", Value: "10"},
+				})
+				c.So(HasCode(sErr, "conflicting_parameters"), c.ShouldBeTrue)
+				c.So(HasCode(sErr, "not_found"), c.ShouldBeFalse)
+			})
+
+			c.Convey("and the error message holds every error", func() {
+				c.So(sErr.Error(), c.ShouldEqual, "failed as unexpected code from search api: 400: limit: invalid limit parameter; offset: offset cannot be used with cursor")
+			})
+		})
+	})
+
+	c.Convey("given an error response that is not a search api error response", t, func() {
+		sErr := NewStatusError(502, []byte("bad gateway"))
+
+		c.Convey("then only the status code is reported", func() {
+			c.So(sErr.Status(), c.ShouldEqual, 502)
+			c.So(sErr.Error(), c.ShouldEqual, "failed as unexpected code from search api: 502")
+			c.So(ErrorDetails(sErr), c.ShouldBeEmpty)
+		})
+	})
+}
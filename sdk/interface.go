@@ -16,9 +16,14 @@ import (
 type Clienter interface {
 	Checker(ctx context.Context, check *health.CheckState) error
 	CreateIndex(ctx context.Context, options Options) (*models.CreateIndexResponse, apiError.Error)
+	DeleteIndex(ctx context.Context, options Options, indexName string) apiError.Error
+	GetIndexes(ctx context.Context, options Options) (*models.IndexesResponse, apiError.Error)
 	GetReleaseCalendarEntries(ctx context.Context, options Options) (*transformer.SearchReleaseResponse, apiError.Error)
 	GetSearch(ctx context.Context, options Options) (*models.SearchResponse, apiError.Error)
+	GetSuggestions(ctx context.Context, options Options) (*models.SuggestResponse, apiError.Error)
+	PostSearchFeedback(ctx context.Context, options Options, feedback models.Feedback) apiError.Error
 	PostSearchURIs(ctx context.Context, options Options, urisRequest api.URIsRequest) (*models.SearchResponse, apiError.Error)
+	UpdateIndexAlias(ctx context.Context, options Options, indexName string) (*models.UpdateAliasResponse, apiError.Error)
 	Health() *healthcheck.Client
 	URL() string
 }
@@ -31,21 +31,36 @@ var _ sdk.Clienter = &ClienterMock{}
 //			CreateIndexFunc: func(ctx context.Context, options sdk.Options) (*models.CreateIndexResponse, apiError.Error) {
 //				panic("mock out the CreateIndex method")
 //			},
+//			DeleteIndexFunc: func(ctx context.Context, options sdk.Options, indexName string) apiError.Error {
+//				panic("mock out the DeleteIndex method")
+//			},
+//			GetIndexesFunc: func(ctx context.Context, options sdk.Options) (*models.IndexesResponse, apiError.Error) {
+//				panic("mock out the GetIndexes method")
+//			},
 //			GetReleaseCalendarEntriesFunc: func(ctx context.Context, options sdk.Options) (*transformer.SearchReleaseResponse, apiError.Error) {
 //				panic("mock out the GetReleaseCalendarEntries method")
 //			},
 //			GetSearchFunc: func(ctx context.Context, options sdk.Options) (*models.SearchResponse, apiError.Error) {
 //				panic("mock out the GetSearch method")
 //			},
+//			GetSuggestionsFunc: func(ctx context.Context, options sdk.Options) (*models.SuggestResponse, apiError.Error) {
+//				panic("mock out the GetSuggestions method")
+//			},
 //			HealthFunc: func() *healthcheck.Client {
 //				panic("mock out the Health method")
 //			},
+//			PostSearchFeedbackFunc: func(ctx context.Context, options sdk.Options, feedback models.Feedback) apiError.Error {
+//				panic("mock out the PostSearchFeedback method")
+//			},
 //			PostSearchURIsFunc: func(ctx context.Context, options sdk.Options, urisRequest api.URIsRequest) (*models.SearchResponse, apiError.Error) {
 //				panic("mock out the PostSearchURIs method")
 //			},
 //			URLFunc: func() string {
 //				panic("mock out the URL method")
 //			},
+//			UpdateIndexAliasFunc: func(ctx context.Context, options sdk.Options, indexName string) (*models.UpdateAliasResponse, apiError.Error) {
+//				panic("mock out the UpdateIndexAlias method")
+//			},
 //		}
 //
 //		// use mockedClienter in code that requires sdk.Clienter
@@ -59,21 +74,36 @@ type ClienterMock struct {
 	// CreateIndexFunc mocks the CreateIndex method.
 	CreateIndexFunc func(ctx context.Context, options sdk.Options) (*models.CreateIndexResponse, apiError.Error)
 
+	// DeleteIndexFunc mocks the DeleteIndex method.
+	DeleteIndexFunc func(ctx context.Context, options sdk.Options, indexName string) apiError.Error
+
+	// GetIndexesFunc mocks the GetIndexes method.
+	GetIndexesFunc func(ctx context.Context, options sdk.Options) (*models.IndexesResponse, apiError.Error)
+
 	// GetReleaseCalendarEntriesFunc mocks the GetReleaseCalendarEntries method.
 	GetReleaseCalendarEntriesFunc func(ctx context.Context, options sdk.Options) (*transformer.SearchReleaseResponse, apiError.Error)
 
 	// GetSearchFunc mocks the GetSearch method.
 	GetSearchFunc func(ctx context.Context, options sdk.Options) (*models.SearchResponse, apiError.Error)
 
+	// GetSuggestionsFunc mocks the GetSuggestions method.
+	GetSuggestionsFunc func(ctx context.Context, options sdk.Options) (*models.SuggestResponse, apiError.Error)
+
 	// HealthFunc mocks the Health method.
 	HealthFunc func() *healthcheck.Client
 
+	// PostSearchFeedbackFunc mocks the PostSearchFeedback method.
+	PostSearchFeedbackFunc func(ctx context.Context, options sdk.Options, feedback models.Feedback) apiError.Error
+
 	// PostSearchURIsFunc mocks the PostSearchURIs method.
 	PostSearchURIsFunc func(ctx context.Context, options sdk.Options, urisRequest api.URIsRequest) (*models.SearchResponse, apiError.Error)
 
 	// URLFunc mocks the URL method.
 	URLFunc func() string
 
+	// UpdateIndexAliasFunc mocks the UpdateIndexAlias method.
+	UpdateIndexAliasFunc func(ctx context.Context, options sdk.Options, indexName string) (*models.UpdateAliasResponse, apiError.Error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// Checker holds details about calls to the Checker method.
@@ -90,6 +120,22 @@ type ClienterMock struct {
 			// Options is the options argument value.
 			Options sdk.Options
 		}
+		// DeleteIndex holds details about calls to the DeleteIndex method.
+		DeleteIndex []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Options is the options argument value.
+			Options sdk.Options
+			// IndexName is the indexName argument value.
+			IndexName string
+		}
+		// GetIndexes holds details about calls to the GetIndexes method.
+		GetIndexes []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Options is the options argument value.
+			Options sdk.Options
+		}
 		// GetReleaseCalendarEntries holds details about calls to the GetReleaseCalendarEntries method.
 		GetReleaseCalendarEntries []struct {
 			// Ctx is the ctx argument value.
@@ -104,9 +150,25 @@ type ClienterMock struct {
 			// Options is the options argument value.
 			Options sdk.Options
 		}
+		// GetSuggestions holds details about calls to the GetSuggestions method.
+		GetSuggestions []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Options is the options argument value.
+			Options sdk.Options
+		}
 		// Health holds details about calls to the Health method.
 		Health []struct {
 		}
+		// PostSearchFeedback holds details about calls to the PostSearchFeedback method.
+		PostSearchFeedback []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Options is the options argument value.
+			Options sdk.Options
+			// Feedback is the feedback argument value.
+			Feedback models.Feedback
+		}
 		// PostSearchURIs holds details about calls to the PostSearchURIs method.
 		PostSearchURIs []struct {
 			// Ctx is the ctx argument value.
@@ -119,14 +181,28 @@ type ClienterMock struct {
 		// URL holds details about calls to the URL method.
 		URL []struct {
 		}
+		// UpdateIndexAlias holds details about calls to the UpdateIndexAlias method.
+		UpdateIndexAlias []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Options is the options argument value.
+			Options sdk.Options
+			// IndexName is the indexName argument value.
+			IndexName string
+		}
 	}
 	lockChecker                   sync.RWMutex
 	lockCreateIndex               sync.RWMutex
+	lockDeleteIndex               sync.RWMutex
+	lockGetIndexes                sync.RWMutex
 	lockGetReleaseCalendarEntries sync.RWMutex
 	lockGetSearch                 sync.RWMutex
+	lockGetSuggestions            sync.RWMutex
 	lockHealth                    sync.RWMutex
+	lockPostSearchFeedback        sync.RWMutex
 	lockPostSearchURIs            sync.RWMutex
 	lockURL                       sync.RWMutex
+	lockUpdateIndexAlias          sync.RWMutex
 }
 
 // Checker calls CheckerFunc.
@@ -201,6 +277,82 @@ func (mock *ClienterMock) CreateIndexCalls() []struct {
 	return calls
 }
 
+// DeleteIndex calls DeleteIndexFunc.
+func (mock *ClienterMock) DeleteIndex(ctx context.Context, options sdk.Options, indexName string) apiError.Error {
+	if mock.DeleteIndexFunc == nil {
+		panic("ClienterMock.DeleteIndexFunc: method is nil but Clienter.DeleteIndex was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Options   sdk.Options
+		IndexName string
+	}{
+		Ctx:       ctx,
+		Options:   options,
+		IndexName: indexName,
+	}
+	mock.lockDeleteIndex.Lock()
+	mock.calls.DeleteIndex = append(mock.calls.DeleteIndex, callInfo)
+	mock.lockDeleteIndex.Unlock()
+	return mock.DeleteIndexFunc(ctx, options, indexName)
+}
+
+// DeleteIndexCalls gets all the calls that were made to DeleteIndex.
+// Check the length with:
+//
+//	len(mockedClienter.DeleteIndexCalls())
+func (mock *ClienterMock) DeleteIndexCalls() []struct {
+	Ctx       context.Context
+	Options   sdk.Options
+	IndexName string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Options   sdk.Options
+		IndexName string
+	}
+	mock.lockDeleteIndex.RLock()
+	calls = mock.calls.DeleteIndex
+	mock.lockDeleteIndex.RUnlock()
+	return calls
+}
+
+// GetIndexes calls GetIndexesFunc.
+func (mock *ClienterMock) GetIndexes(ctx context.Context, options sdk.Options) (*models.IndexesResponse, apiError.Error) {
+	if mock.GetIndexesFunc == nil {
+		panic("ClienterMock.GetIndexesFunc: method is nil but Clienter.GetIndexes was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Options sdk.Options
+	}{
+		Ctx:     ctx,
+		Options: options,
+	}
+	mock.lockGetIndexes.Lock()
+	mock.calls.GetIndexes = append(mock.calls.GetIndexes, callInfo)
+	mock.lockGetIndexes.Unlock()
+	return mock.GetIndexesFunc(ctx, options)
+}
+
+// GetIndexesCalls gets all the calls that were made to GetIndexes.
+// Check the length with:
+//
+//	len(mockedClienter.GetIndexesCalls())
+func (mock *ClienterMock) GetIndexesCalls() []struct {
+	Ctx     context.Context
+	Options sdk.Options
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Options sdk.Options
+	}
+	mock.lockGetIndexes.RLock()
+	calls = mock.calls.GetIndexes
+	mock.lockGetIndexes.RUnlock()
+	return calls
+}
+
 // GetReleaseCalendarEntries calls GetReleaseCalendarEntriesFunc.
 func (mock *ClienterMock) GetReleaseCalendarEntries(ctx context.Context, options sdk.Options) (*transformer.SearchReleaseResponse, apiError.Error) {
 	if mock.GetReleaseCalendarEntriesFunc == nil {
@@ -273,6 +425,42 @@ func (mock *ClienterMock) GetSearchCalls() []struct {
 	return calls
 }
 
+// GetSuggestions calls GetSuggestionsFunc.
+func (mock *ClienterMock) GetSuggestions(ctx context.Context, options sdk.Options) (*models.SuggestResponse, apiError.Error) {
+	if mock.GetSuggestionsFunc == nil {
+		panic("ClienterMock.GetSuggestionsFunc: method is nil but Clienter.GetSuggestions was just called")
+	}
+	callInfo := struct {
+		Ctx     context.Context
+		Options sdk.Options
+	}{
+		Ctx:     ctx,
+		Options: options,
+	}
+	mock.lockGetSuggestions.Lock()
+	mock.calls.GetSuggestions = append(mock.calls.GetSuggestions, callInfo)
+	mock.lockGetSuggestions.Unlock()
+	return mock.GetSuggestionsFunc(ctx, options)
+}
+
+// GetSuggestionsCalls gets all the calls that were made to GetSuggestions.
+// Check the length with:
+//
+//	len(mockedClienter.GetSuggestionsCalls())
+func (mock *ClienterMock) GetSuggestionsCalls() []struct {
+	Ctx     context.Context
+	Options sdk.Options
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Options sdk.Options
+	}
+	mock.lockGetSuggestions.RLock()
+	calls = mock.calls.GetSuggestions
+	mock.lockGetSuggestions.RUnlock()
+	return calls
+}
+
 // Health calls HealthFunc.
 func (mock *ClienterMock) Health() *healthcheck.Client {
 	if mock.HealthFunc == nil {
@@ -300,6 +488,46 @@ func (mock *ClienterMock) HealthCalls() []struct {
 	return calls
 }
 
+// PostSearchFeedback calls PostSearchFeedbackFunc.
+func (mock *ClienterMock) PostSearchFeedback(ctx context.Context, options sdk.Options, feedback models.Feedback) apiError.Error {
+	if mock.PostSearchFeedbackFunc == nil {
+		panic("ClienterMock.PostSearchFeedbackFunc: method is nil but Clienter.PostSearchFeedback was just called")
+	}
+	callInfo := struct {
+		Ctx      context.Context
+		Options  sdk.Options
+		Feedback models.Feedback
+	}{
+		Ctx:      ctx,
+		Options:  options,
+		Feedback: feedback,
+	}
+	mock.lockPostSearchFeedback.Lock()
+	mock.calls.PostSearchFeedback = append(mock.calls.PostSearchFeedback, callInfo)
+	mock.lockPostSearchFeedback.Unlock()
+	return mock.PostSearchFeedbackFunc(ctx, options, feedback)
+}
+
+// PostSearchFeedbackCalls gets all the calls that were made to PostSearchFeedback.
+// Check the length with:
+//
+//	len(mockedClienter.PostSearchFeedbackCalls())
+func (mock *ClienterMock) PostSearchFeedbackCalls() []struct {
+	Ctx      context.Context
+	Options  sdk.Options
+	Feedback models.Feedback
+} {
+	var calls []struct {
+		Ctx      context.Context
+		Options  sdk.Options
+		Feedback models.Feedback
+	}
+	mock.lockPostSearchFeedback.RLock()
+	calls = mock.calls.PostSearchFeedback
+	mock.lockPostSearchFeedback.RUnlock()
+	return calls
+}
+
 // PostSearchURIs calls PostSearchURIsFunc.
 func (mock *ClienterMock) PostSearchURIs(ctx context.Context, options sdk.Options, urisRequest api.URIsRequest) (*models.SearchResponse, apiError.Error) {
 	if mock.PostSearchURIsFunc == nil {
@@ -366,3 +594,43 @@ func (mock *ClienterMock) URLCalls() []struct {
 	mock.lockURL.RUnlock()
 	return calls
 }
+
+// UpdateIndexAlias calls UpdateIndexAliasFunc.
+func (mock *ClienterMock) UpdateIndexAlias(ctx context.Context, options sdk.Options, indexName string) (*models.UpdateAliasResponse, apiError.Error) {
+	if mock.UpdateIndexAliasFunc == nil {
+		panic("ClienterMock.UpdateIndexAliasFunc: method is nil but Clienter.UpdateIndexAlias was just called")
+	}
+	callInfo := struct {
+		Ctx       context.Context
+		Options   sdk.Options
+		IndexName string
+	}{
+		Ctx:       ctx,
+		Options:   options,
+		IndexName: indexName,
+	}
+	mock.lockUpdateIndexAlias.Lock()
+	mock.calls.UpdateIndexAlias = append(mock.calls.UpdateIndexAlias, callInfo)
+	mock.lockUpdateIndexAlias.Unlock()
+	return mock.UpdateIndexAliasFunc(ctx, options, indexName)
+}
+
+// UpdateIndexAliasCalls gets all the calls that were made to UpdateIndexAlias.
+// Check the length with:
+//
+//	len(mockedClienter.UpdateIndexAliasCalls())
+func (mock *ClienterMock) UpdateIndexAliasCalls() []struct {
+	Ctx       context.Context
+	Options   sdk.Options
+	IndexName string
+} {
+	var calls []struct {
+		Ctx       context.Context
+		Options   sdk.Options
+		IndexName string
+	}
+	mock.lockUpdateIndexAlias.RLock()
+	calls = mock.calls.UpdateIndexAlias
+	mock.lockUpdateIndexAlias.RUnlock()
+	return calls
+}
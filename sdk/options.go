@@ -117,6 +117,13 @@ func (o *Options) Census(val string) *Options {
 	return o
 }
 
+// RescheduledSince sets the 'rescheduled_since' Query parameter to the request, to only return releases recently
+// rescheduled, i.e. moved from a release date on or after the given date
+func (o *Options) RescheduledSince(val string) *Options {
+	o.Query.Set(api.ParamRescheduledSince, val)
+	return o
+}
+
 // NLPWeighting sets the 'census' Query parameter to the request
 func (o *Options) NLPWeighting(val string) *Options {
 	o.Query.Set(api.ParamNLPWeighting, val)
@@ -12,7 +12,10 @@ import (
 	"github.com/ONSdigital/dp-search-api/api"
 	"github.com/ONSdigital/dp-search-api/config"
 	"github.com/ONSdigital/dp-search-api/elasticsearch"
+	"github.com/ONSdigital/dp-search-api/feedback"
+	"github.com/ONSdigital/dp-search-api/metrics"
 	"github.com/ONSdigital/dp-search-api/query"
+	"github.com/ONSdigital/dp-search-api/ratelimit"
 	"github.com/ONSdigital/dp-search-api/transformer"
 	scrubber "github.com/ONSdigital/dp-search-scrubber-api/sdk"
 	"github.com/ONSdigital/log.go/v2/log"
@@ -25,6 +28,7 @@ import (
 
 type Service struct {
 	api                 *api.SearchAPI
+	clList              *api.ClientList
 	berlinClient        *berlin.Client
 	categoryClient      *category.Client
 	config              *config.Config
@@ -83,6 +87,9 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 	// Initialise release transformer
 	releaseTransformer := transformer.NewReleaseTransformer()
 
+	// Initialise suggest transformer
+	suggestTransformer := transformer.NewSuggestTransformer()
+
 	esConfig := dpEsClient.Config{
 		ClientLib: dpEsClient.GoElasticV710,
 		Address:   cfg.ElasticSearchAPIURL,
@@ -108,6 +115,24 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 		return nil, err
 	}
 
+	// Extend the dp-elasticsearch client with the point in time APIs used for cursor pagination
+	searchClient, err := elasticsearch.NewSearchClient(esClient, esConfig.Address, esConfig.Transport)
+	if err != nil {
+		log.Error(ctx, "Failed to create elasticsearch search client", err)
+		return nil, err
+	}
+
+	// Time out, retry and fail fast the searches sent to elasticsearch
+	resilientClient := elasticsearch.NewResilientClient(searchClient, elasticsearch.ResilienceConfig{
+		Timeout:             cfg.ElasticSearchTimeout,
+		MaxRetries:          cfg.ElasticSearchMaxRetries,
+		RetryBackoff:        cfg.ElasticSearchRetryBackoff,
+		BreakerErrorRate:    cfg.ElasticSearchBreakerRate,
+		BreakerMinRequests:  cfg.ElasticSearchBreakerMin,
+		BreakerWindow:       cfg.ElasticSearchErrorWindow,
+		BreakerOpenDuration: cfg.ElasticSearchBreakerOpen,
+	})
+
 	// Initialise search query builder
 	queryBuilder, err := query.NewQueryBuilder()
 	if err != nil {
@@ -115,6 +140,39 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 		return nil, err
 	}
 
+	// Load the boost profiles that can be selected by search requests
+	boostProfiles, err := query.LoadBoostProfiles(cfg.BoostProfiles, cfg.BoostProfilesFile)
+	if err != nil {
+		log.Fatal(ctx, "error loading boost profiles", err)
+		return nil, err
+	}
+	searchValidator := query.NewSearchQueryParamValidator().WithBoostProfiles(boostProfiles)
+
+	// Load the search experiments and the query templates of their variants
+	experiments, err := query.ParseExperiments([]byte(cfg.Experiments), boostProfiles)
+	if err != nil {
+		log.Fatal(ctx, "error loading experiments", err)
+		return nil, err
+	}
+	for _, experiment := range experiments {
+		for _, variant := range experiment.Variants {
+			if variant.Templates == "" {
+				continue
+			}
+			if err = queryBuilder.AddTemplateSet(variant.Name, variant.Templates); err != nil {
+				log.Fatal(ctx, "error loading experiment variant templates", err, log.Data{"variant": variant.Name})
+				return nil, err
+			}
+		}
+	}
+
+	// Load the rate limits of the public routes
+	rateLimits, err := ratelimit.ParseLimits([]byte(cfg.RateLimits))
+	if err != nil {
+		log.Fatal(ctx, "error loading rate limits", err)
+		return nil, err
+	}
+
 	// Initialise release query builer
 	releaseBuilder, err := query.NewReleaseBuilder()
 	if err != nil {
@@ -122,6 +180,13 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 		return nil, err
 	}
 
+	// Initialise suggest query builder
+	suggestBuilder, err := query.NewSuggestBuilder()
+	if err != nil {
+		log.Fatal(ctx, "error initialising suggest query builder", err)
+		return nil, err
+	}
+
 	// Initialise authorisation handler
 	permissions := serviceList.GetAuthorisationHandlers(cfg)
 
@@ -134,7 +199,22 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 
 	// Create a ClientList to store all the required clients
 	// Remove deprecatedESClient once the legacy handler is removed
-	clList := api.NewClientList(berlinClient, categoryClient, esClient, scrubberClient, deprecatedESClient)
+	clList := api.NewClientList(berlinClient, categoryClient, resilientClient, scrubberClient, deprecatedESClient)
+
+	// Record search feedback if a feedback file is configured
+	if cfg.FeedbackFile != "" {
+		feedbackSink, sinkErr := feedback.NewFileSink(cfg.FeedbackFile)
+		if sinkErr != nil {
+			log.Fatal(ctx, "error opening feedback file", sinkErr)
+			return nil, sinkErr
+		}
+		clList.FeedbackSink = feedbackSink
+	}
+
+	// Cache search responses in memory if a cache size is configured
+	if cfg.ResponseCacheSize > 0 {
+		clList.ResponseCache = api.NewLRUCache(cfg.ResponseCacheSize, cfg.ResponseCacheTTL)
+	}
 
 	if regErr := registerCheckers(ctx, healthCheck, clList); regErr != nil {
 		return nil, errors.Wrap(regErr, "unable to register checkers")
@@ -151,14 +231,32 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 		server = serviceList.GetHTTPServer(cfg.BindAddr, router)
 	}
 	router.StrictSlash(true).Path("/health").HandlerFunc(healthCheck.Handler)
+	metricsRegistry := metrics.NewRegistry()
+	router.StrictSlash(true).Path("/metrics").Handler(metricsRegistry)
 	healthCheck.Start(ctx)
 
 	// Create Search API and register HTTP handlers
 	searchAPI := api.NewSearchAPI(router, clList, permissions).
-		RegisterGetSearch(query.NewSearchQueryParamValidator(), queryBuilder, cfg, searchTransformer).
+		WithMetrics(metricsRegistry).
+		WithExperiments(experiments, cfg.ExperimentHeader).
+		WithRateLimits(ratelimit.NewMemoryStore(), rateLimits, api.RateLimitClients{
+			KeyHeader:      cfg.RateLimitKeyHeader,
+			APIKeys:        cfg.RateLimitAPIKeys,
+			TrustedProxies: cfg.RateLimitTrustedProxies,
+		}, metricsRegistry).
+		RegisterGetSearch(searchValidator, queryBuilder, cfg, searchTransformer).
 		RegisterPostSearch().
-		RegisterPostSearchURIs(query.NewSearchQueryParamValidator(), queryBuilder, cfg, searchTransformer).
-		RegisterGetSearchReleases(query.NewReleaseQueryParamValidator(), releaseBuilder, releaseTransformer)
+		RegisterSearchIndexes().
+		RegisterSearchSynonyms().
+		RegisterPostSearchURIs(searchValidator, queryBuilder, cfg, searchTransformer).
+		RegisterGetSearchReleases(query.NewReleaseQueryParamValidator(), releaseBuilder, cfg, releaseTransformer).
+		RegisterGetSearchSuggest(query.NewSearchQueryParamValidator(), suggestBuilder, suggestTransformer).
+		RegisterGetSearchExport(searchValidator, queryBuilder, cfg, searchTransformer).
+		RegisterGetSearchFeed(searchValidator, queryBuilder, cfg, searchTransformer).
+		RegisterGetSearchReleasesFeed(query.NewReleaseQueryParamValidator(), releaseBuilder, cfg, releaseTransformer).
+		RegisterGetSearchReleasesCalendar(query.NewReleaseQueryParamValidator(), releaseBuilder, releaseTransformer).
+		RegisterPostSearchFeedback().
+		RegisterDeleteSearchCache()
 
 	go func() {
 		log.Info(ctx, "search api starting")
@@ -170,6 +268,7 @@ func Run(ctx context.Context, cfg *config.Config, serviceList *ExternalServiceLi
 
 	return &Service{
 		api:                 searchAPI,
+		clList:              clList,
 		berlinClient:        berlinClient,
 		categoryClient:      categoryClient,
 		config:              cfg,
@@ -206,6 +305,14 @@ func (svc *Service) Close(ctx context.Context) error {
 			log.Error(shutdownContext, "error closing API", err)
 			hasShutdownError = true
 		}
+
+		// close the feedback sink once no more feedback can be received
+		if svc.clList != nil && svc.clList.FeedbackSink != nil {
+			if err := svc.clList.FeedbackSink.Close(shutdownContext); err != nil {
+				log.Error(shutdownContext, "error closing feedback sink", err)
+				hasShutdownError = true
+			}
+		}
 	}()
 
 	// wait for shutdown success (via cancel) or failure (timeout)
@@ -236,5 +343,13 @@ func registerCheckers(ctx context.Context, hc HealthChecker, clList *api.ClientL
 		return err
 	}
 
+	if breaker, ok := clList.DpESClient.(api.CircuitBreaker); ok {
+		if err = hc.AddCheck("Elasticsearch circuit breaker", breaker.CircuitBreakerChecker); err != nil {
+			log.Error(ctx, "error creating elasticsearch circuit breaker health check", err)
+			err = errors.New("Error(s) registering checkers for health check")
+			return err
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,107 @@
+package transformer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ONSdigital/dp-search-api/icalendar"
+)
+
+const (
+	calendarProdID = "-//Office for National Statistics//dp-search-api//EN"
+	calendarName   = "ONS release calendar"
+)
+
+// TransformCalendarResponse transforms a serialised SearchReleaseResponse into an iCalendar with an event for each
+// release, linking to the release on the website. Releases without a valid release date are left out.
+func (t *ReleaseTransformer) TransformCalendarResponse(_ context.Context, responseData []byte, websiteURL string) ([]byte, error) {
+	var response SearchReleaseResponse
+	if err := json.Unmarshal(responseData, &response); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode release search response")
+	}
+
+	host := ""
+	if website, err := url.Parse(websiteURL); err == nil {
+		host = website.Host
+	}
+
+	calendar := &icalendar.Calendar{
+		ProdID: calendarProdID,
+		Name:   calendarName,
+		Events: make([]icalendar.Event, 0, len(response.Releases)),
+	}
+
+	now := t.now()
+	for i := range response.Releases {
+		release := &response.Releases[i]
+		start, err := time.Parse(time.RFC3339, release.Description.ReleaseDate)
+		if err != nil {
+			continue
+		}
+
+		calendar.Events = append(calendar.Events, icalendar.Event{
+			UID:         releaseUID(release.URI, host),
+			Stamp:       now,
+			Start:       start,
+			Summary:     release.Description.Title,
+			Description: releaseEventDescription(release),
+			URL:         strings.TrimSuffix(websiteURL, "/") + release.URI,
+			Status:      releaseEventStatus(release),
+			// every change of date is a new version of the event, so that calendar clients move it
+			Sequence: len(release.DateChanges),
+		})
+	}
+
+	return calendar.Marshal(), nil
+}
+
+// releaseUID returns the UID of the event of a release, which is the same whenever the release is rescheduled
+func releaseUID(uri, host string) string {
+	sum := sha256.Sum256([]byte(uri))
+	uid := hex.EncodeToString(sum[:16])
+	if host != "" {
+		uid += "@" + host
+	}
+	return uid
+}
+
+func releaseEventStatus(release *Release) string {
+	switch {
+	case release.Description.Cancelled:
+		return icalendar.StatusCancelled
+	case !release.Description.Finalised && !release.Description.Published:
+		return icalendar.StatusTentative
+	default:
+		return icalendar.StatusConfirmed
+	}
+}
+
+// releaseEventDescription returns the summary of a release, followed by its provisional date, if it has not been
+// confirmed, and the notice of every change to its date
+func releaseEventDescription(release *Release) string {
+	lines := []string{release.Description.Summary}
+
+	if release.Description.Cancelled {
+		lines = append(lines, "This release has been cancelled.")
+	}
+	if !release.Description.Finalised && release.Description.ProvisionalDate != "" {
+		lines = append(lines, fmt.Sprintf("Provisional release date: %s", release.Description.ProvisionalDate))
+	}
+	for _, change := range release.DateChanges {
+		previous := change.Date
+		if date, err := time.Parse(time.RFC3339, change.Date); err == nil {
+			previous = date.Format("2 January 2006")
+		}
+		lines = append(lines, fmt.Sprintf("Rescheduled from %s: %s", previous, change.ChangeNotice))
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,89 @@
+package transformer
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTransformCalendarResponse(t *testing.T) {
+	t.Parallel()
+	c.Convey("Given a release transformer", t, func() {
+		ctx := context.Background()
+		transformer := &ReleaseTransformer{now: func() time.Time { return time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC) }}
+
+		c.Convey("When a release search response is transformed into a calendar", func() {
+			response, err := json.Marshal(SearchReleaseResponse{
+				Releases: []Release{
+					{
+						URI: "/releases/labourmarketoverviewukjanuary2024",
+						DateChanges: []ReleaseDateChange{
+							{Date: "2024-01-09T07:00:00.000Z", ChangeNotice: "Delayed to include revised data"},
+						},
+						Description: ReleaseDescription{
+							Title:       "Labour market overview, UK: January 2024",
+							Summary:     "Estimates of employment",
+							ReleaseDate: "2024-01-16T07:00:00.000Z",
+							Finalised:   true,
+							Postponed:   true,
+						},
+					},
+					{
+						URI: "/releases/census2021",
+						Description: ReleaseDescription{
+							Title:           "Census 2021",
+							Summary:         "Population estimates",
+							ReleaseDate:     "2024-03-01T09:30:00.000Z",
+							ProvisionalDate: "March 2024",
+						},
+					},
+					{
+						URI:         "/releases/nodate",
+						Description: ReleaseDescription{Title: "No date"},
+					},
+				},
+			})
+			c.So(err, c.ShouldBeNil)
+
+			data, err := transformer.TransformCalendarResponse(ctx, response, "https://www.ons.gov.uk/")
+			calendar := string(data)
+
+			c.Convey("Then each release with a release date is an event", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(strings.Count(calendar, "BEGIN:VEVENT"), c.ShouldEqual, 2)
+				c.So(calendar, c.ShouldContainSubstring, "X-WR-CALNAME:ONS release calendar\r\n")
+				c.So(calendar, c.ShouldNotContainSubstring, "No date")
+			})
+
+			c.Convey("And a rescheduled release has a new sequence, its change notice and a confirmed status", func() {
+				c.So(calendar, c.ShouldContainSubstring, "DTSTAMP:20240102T090000Z\r\nDTSTART:20240116T070000Z\r\nSEQUENCE:1\r\n")
+				c.So(calendar, c.ShouldContainSubstring, `DESCRIPTION:Estimates of employment\nRescheduled from 9 January 2024: Delay`)
+				c.So(calendar, c.ShouldContainSubstring, "URL:https://www.ons.gov.uk/releases/labourmarketoverviewukjanuary2024\r\n")
+				c.So(calendar, c.ShouldContainSubstring, "STATUS:CONFIRMED\r\n")
+			})
+
+			c.Convey("And a provisional release is tentative, with its provisional date", func() {
+				c.So(calendar, c.ShouldContainSubstring, `DESCRIPTION:Population estimates\nProvisional release date: March 2024`)
+				c.So(calendar, c.ShouldContainSubstring, "STATUS:TENTATIVE\r\n")
+			})
+
+			c.Convey("And the UID of each event only depends on the URI of its release", func() {
+				c.So(calendar, c.ShouldContainSubstring, "UID:"+releaseUID("/releases/census2021", "www.ons.gov.uk")+"\r\n")
+				c.So(releaseUID("/releases/census2021", "www.ons.gov.uk"), c.ShouldEndWith, "@www.ons.gov.uk")
+				c.So(releaseUID("/releases/census2021", "www.ons.gov.uk"), c.ShouldNotEqual, releaseUID("/releases/census2011", "www.ons.gov.uk"))
+			})
+		})
+
+		c.Convey("When an invalid response is transformed into a calendar", func() {
+			_, err := transformer.TransformCalendarResponse(ctx, []byte(`{"releases":`), "https://www.ons.gov.uk")
+
+			c.Convey("Then an error is returned", func() {
+				c.So(err, c.ShouldNotBeNil)
+			})
+		})
+	})
+}
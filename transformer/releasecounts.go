@@ -0,0 +1,125 @@
+package transformer
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/ONSdigital/dp-search-api/query"
+)
+
+// The release types and subtypes that releases are counted by, as named by the release_types and breakdown
+// aggregations of the release calendar query
+var (
+	releaseTypeNames    = []bucketName{"upcoming", "outdated", "published", "cancelled"}
+	releaseSubtypeNames = []bucketName{"provisional", "confirmed", "postponed"}
+)
+
+// ReleaseCountsResponse is the number of releases of each type and subtype released in each interval of a calendar
+type ReleaseCountsResponse struct {
+	Took     int                  `json:"took"`
+	Interval string               `json:"interval"`
+	Buckets  []ReleaseCountBucket `json:"buckets"`
+}
+
+// ReleaseCountBucket is the number of releases of each type and subtype released in the interval starting on Date
+type ReleaseCountBucket struct {
+	Date      string            `json:"date"`
+	Total     int               `json:"total"`
+	Upcoming  ReleaseTypeCounts `json:"upcoming"`
+	Outdated  ReleaseTypeCounts `json:"outdated"`
+	Published ReleaseTypeCounts `json:"published"`
+	Cancelled ReleaseTypeCounts `json:"cancelled"`
+}
+
+// ReleaseTypeCounts is the number of releases of a type, in total and of each subtype
+type ReleaseTypeCounts struct {
+	Total       int `json:"total"`
+	Provisional int `json:"provisional"`
+	Confirmed   int `json:"confirmed"`
+	Postponed   int `json:"postponed"`
+}
+
+// dateHistogram is a date histogram aggregation, with a bucket for each interval keyed by the date it starts on
+type dateHistogram struct {
+	Buckets []struct {
+		Date  string `json:"key_as_string"`
+		Count int    `json:"doc_count"`
+	} `json:"buckets"`
+}
+
+// TransformCalendarCountsResponse transforms an elastic search response to a release calendar query into a serialised
+// ReleaseCountsResponse, with a bucket for each interval of the calendar in date order
+func (t *ReleaseTransformer) TransformCalendarCountsResponse(_ context.Context, responseData []byte, req query.ReleaseCalendarRequest) ([]byte, error) {
+	var source ESReleaseResponse
+	if err := json.Unmarshal(responseData, &source); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode elastic search response")
+	}
+
+	if len(source.Responses) != 1 {
+		return nil, errors.New("invalid number of responses from ElasticSearch query")
+	}
+
+	buckets := make(map[string]*ReleaseCountBucket)
+	releaseTypes := source.Responses[0].Aggregations["release_types"].Buckets
+	for _, typeName := range releaseTypeNames {
+		subtypes := releaseTypes[typeName].Breakdown.Buckets
+		for _, subtypeName := range releaseSubtypeNames {
+			for _, histogramBucket := range subtypes[subtypeName].ReleaseDates.Buckets {
+				bucket, ok := buckets[histogramBucket.Date]
+				if !ok {
+					bucket = &ReleaseCountBucket{Date: histogramBucket.Date}
+					buckets[histogramBucket.Date] = bucket
+				}
+				bucket.Total += histogramBucket.Count
+				bucket.typeCounts(typeName).add(subtypeName, histogramBucket.Count)
+			}
+		}
+	}
+
+	response := ReleaseCountsResponse{
+		Took:     source.Responses[0].Took,
+		Interval: req.Interval,
+		Buckets:  make([]ReleaseCountBucket, 0, len(buckets)),
+	}
+	for _, bucket := range buckets {
+		response.Buckets = append(response.Buckets, *bucket)
+	}
+	sort.Slice(response.Buckets, func(i, j int) bool {
+		return response.Buckets[i].Date < response.Buckets[j].Date
+	})
+
+	transformedData, err := json.Marshal(response)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode transformed response")
+	}
+
+	return transformedData, nil
+}
+
+func (b *ReleaseCountBucket) typeCounts(releaseType bucketName) *ReleaseTypeCounts {
+	switch releaseType {
+	case "upcoming":
+		return &b.Upcoming
+	case "outdated":
+		return &b.Outdated
+	case "published":
+		return &b.Published
+	default:
+		return &b.Cancelled
+	}
+}
+
+func (c *ReleaseTypeCounts) add(subtype bucketName, count int) {
+	c.Total += count
+	switch subtype {
+	case "provisional":
+		c.Provisional += count
+	case "confirmed":
+		c.Confirmed += count
+	case "postponed":
+		c.Postponed += count
+	}
+}
@@ -0,0 +1,78 @@
+package transformer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	c "github.com/smartystreets/goconvey/convey"
+
+	"github.com/ONSdigital/dp-search-api/query"
+)
+
+func TestTransformCalendarCountsResponse(t *testing.T) {
+	t.Parallel()
+	c.Convey("Given a release transformer", t, func() {
+		ctx := context.Background()
+		transformer := &ReleaseTransformer{}
+		req := query.ReleaseCalendarRequest{Interval: "day"}
+
+		c.Convey("When a release calendar query response is transformed", func() {
+			esResponse := []byte(`{"responses":[{"took":4,"aggregations":{"release_types":{"buckets":{
+				"upcoming":{"doc_count":3,"breakdown":{"buckets":{
+					"provisional":{"doc_count":1,"release_dates":{"buckets":[
+						{"key_as_string":"2024-01-02","key":1704153600000,"doc_count":0},
+						{"key_as_string":"2024-01-01","key":1704067200000,"doc_count":1}]}},
+					"confirmed":{"doc_count":1,"release_dates":{"buckets":[
+						{"key_as_string":"2024-01-01","key":1704067200000,"doc_count":0},
+						{"key_as_string":"2024-01-02","key":1704153600000,"doc_count":1}]}},
+					"postponed":{"doc_count":1,"release_dates":{"buckets":[
+						{"key_as_string":"2024-01-01","key":1704067200000,"doc_count":0},
+						{"key_as_string":"2024-01-02","key":1704153600000,"doc_count":1}]}}}}},
+				"outdated":{"doc_count":0,"breakdown":{"buckets":{}}},
+				"published":{"doc_count":2,"breakdown":{"buckets":{
+					"confirmed":{"doc_count":2,"release_dates":{"buckets":[
+						{"key_as_string":"2024-01-01","key":1704067200000,"doc_count":2},
+						{"key_as_string":"2024-01-02","key":1704153600000,"doc_count":0}]}}}}},
+				"cancelled":{"doc_count":1,"breakdown":{"buckets":{
+					"provisional":{"doc_count":1,"release_dates":{"buckets":[
+						{"key_as_string":"2024-01-01","key":1704067200000,"doc_count":0},
+						{"key_as_string":"2024-01-02","key":1704153600000,"doc_count":1}]}}}}}
+			}}}}]}`)
+
+			transformed, err := transformer.TransformCalendarCountsResponse(ctx, esResponse, req)
+			c.So(err, c.ShouldBeNil)
+
+			var response ReleaseCountsResponse
+			c.So(json.Unmarshal(transformed, &response), c.ShouldBeNil)
+
+			c.Convey("Then there is a bucket for each day, in date order", func() {
+				c.So(response.Took, c.ShouldEqual, 4)
+				c.So(response.Interval, c.ShouldEqual, "day")
+				c.So(response.Buckets, c.ShouldHaveLength, 2)
+				c.So(response.Buckets[0].Date, c.ShouldEqual, "2024-01-01")
+				c.So(response.Buckets[1].Date, c.ShouldEqual, "2024-01-02")
+			})
+
+			c.Convey("Then each bucket counts the releases of each type and subtype released that day", func() {
+				c.So(response.Buckets[0].Total, c.ShouldEqual, 3)
+				c.So(response.Buckets[0].Upcoming, c.ShouldResemble, ReleaseTypeCounts{Total: 1, Provisional: 1})
+				c.So(response.Buckets[0].Published, c.ShouldResemble, ReleaseTypeCounts{Total: 2, Confirmed: 2})
+				c.So(response.Buckets[0].Cancelled, c.ShouldResemble, ReleaseTypeCounts{})
+
+				c.So(response.Buckets[1].Total, c.ShouldEqual, 3)
+				c.So(response.Buckets[1].Upcoming, c.ShouldResemble, ReleaseTypeCounts{Total: 2, Confirmed: 1, Postponed: 1})
+				c.So(response.Buckets[1].Outdated, c.ShouldResemble, ReleaseTypeCounts{})
+				c.So(response.Buckets[1].Cancelled, c.ShouldResemble, ReleaseTypeCounts{Total: 1, Provisional: 1})
+			})
+		})
+
+		c.Convey("When a response without a single query response is transformed", func() {
+			_, err := transformer.TransformCalendarCountsResponse(ctx, []byte(`{"responses":[]}`), req)
+
+			c.Convey("Then an error is returned", func() {
+				c.So(err, c.ShouldNotBeNil)
+			})
+		})
+	})
+}
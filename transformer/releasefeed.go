@@ -0,0 +1,70 @@
+package transformer
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ONSdigital/dp-search-api/feed"
+)
+
+// The categories that releases are filed under in feeds, by status
+const (
+	feedCategoryCensus      = "census"
+	feedCategoryCancelled   = "cancelled"
+	feedCategoryPublished   = "published"
+	feedCategoryConfirmed   = "confirmed"
+	feedCategoryProvisional = "provisional"
+)
+
+// TransformFeedEntries transforms a serialised SearchReleaseResponse into a feed entry for each release, linking to the
+// release on the website. Releases without a valid release date are left out.
+func (t *ReleaseTransformer) TransformFeedEntries(_ context.Context, responseData []byte, websiteURL string) ([]feed.Entry, error) {
+	var response SearchReleaseResponse
+	if err := json.Unmarshal(responseData, &response); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode release search response")
+	}
+
+	entries := make([]feed.Entry, 0, len(response.Releases))
+	for i := range response.Releases {
+		release := &response.Releases[i]
+		releaseDate, err := time.Parse(time.RFC3339, release.Description.ReleaseDate)
+		if err != nil {
+			continue
+		}
+
+		entries = append(entries, feed.Entry{
+			ID:         strings.TrimSuffix(websiteURL, "/") + release.URI,
+			Title:      release.Description.Title,
+			Summary:    release.Description.Summary,
+			Published:  releaseDate,
+			Updated:    releaseDate,
+			Categories: releaseFeedCategories(release),
+		})
+	}
+
+	return entries, nil
+}
+
+// releaseFeedCategories returns the status of a release, along with whether it is a census release
+func releaseFeedCategories(release *Release) []string {
+	var categories []string
+	if release.Description.Census {
+		categories = append(categories, feedCategoryCensus)
+	}
+
+	switch {
+	case release.Description.Cancelled:
+		categories = append(categories, feedCategoryCancelled)
+	case release.Description.Published:
+		categories = append(categories, feedCategoryPublished)
+	case release.Description.Finalised:
+		categories = append(categories, feedCategoryConfirmed)
+	default:
+		categories = append(categories, feedCategoryProvisional)
+	}
+	return categories
+}
@@ -0,0 +1,73 @@
+package transformer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTransformFeedEntries(t *testing.T) {
+	t.Parallel()
+	c.Convey("Given a release transformer", t, func() {
+		ctx := context.Background()
+		transformer := &ReleaseTransformer{}
+
+		c.Convey("When a release search response is transformed into feed entries", func() {
+			response, err := json.Marshal(SearchReleaseResponse{
+				Releases: []Release{
+					{
+						URI: "/releases/labourmarketoverviewukjanuary2024",
+						Description: ReleaseDescription{
+							Title:       "Labour market overview, UK: January 2024",
+							Summary:     "Estimates of employment",
+							ReleaseDate: "2024-01-16T07:00:00.000Z",
+							Finalised:   true,
+							Published:   true,
+						},
+					},
+					{
+						URI: "/releases/census2021",
+						Description: ReleaseDescription{
+							Title:       "Census 2021",
+							ReleaseDate: "2024-03-01T09:30:00.000Z",
+							Census:      true,
+						},
+					},
+					{
+						URI:         "/releases/nodate",
+						Description: ReleaseDescription{Title: "No date"},
+					},
+				},
+			})
+			c.So(err, c.ShouldBeNil)
+
+			entries, err := transformer.TransformFeedEntries(ctx, response, "https://www.ons.gov.uk/")
+
+			c.Convey("Then each release with a release date is an entry linking to the release, updated at its release date", func() {
+				c.So(err, c.ShouldBeNil)
+				c.So(entries, c.ShouldHaveLength, 2)
+				c.So(entries[0].ID, c.ShouldEqual, "https://www.ons.gov.uk/releases/labourmarketoverviewukjanuary2024")
+				c.So(entries[0].Title, c.ShouldEqual, "Labour market overview, UK: January 2024")
+				c.So(entries[0].Summary, c.ShouldEqual, "Estimates of employment")
+				c.So(entries[0].Updated, c.ShouldEqual, time.Date(2024, 1, 16, 7, 0, 0, 0, time.UTC))
+				c.So(entries[0].Published, c.ShouldEqual, entries[0].Updated)
+			})
+
+			c.Convey("Then entries are filed under the status of the release, and census releases under census", func() {
+				c.So(entries[0].Categories, c.ShouldResemble, []string{"published"})
+				c.So(entries[1].Categories, c.ShouldResemble, []string{"census", "provisional"})
+			})
+		})
+
+		c.Convey("When an invalid response is transformed into feed entries", func() {
+			_, err := transformer.TransformFeedEntries(ctx, []byte("{"), "https://www.ons.gov.uk")
+
+			c.Convey("Then an error is returned", func() {
+				c.So(err, c.ShouldNotBeNil)
+			})
+		})
+	})
+}
@@ -1,18 +1,22 @@
 package transformer
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/ONSdigital/dp-search-api/api"
+	"github.com/ONSdigital/dp-search-api/models"
 	"github.com/ONSdigital/dp-search-api/query"
 )
 
 type ReleaseTransformer struct {
 	higlightReplacer *strings.Replacer
+	now              func() time.Time
 }
 
 type SearchReleaseResponse struct {
@@ -29,13 +33,19 @@ type Breakdown struct {
 	Published   int `json:"published,omitempty"`
 	Cancelled   int `json:"cancelled,omitempty"`
 	Census      int `json:"census,omitempty"`
+	// Topics are the number of releases with each topic
+	Topics []models.FilterCount `json:"topics,omitempty"`
 }
 
 type Release struct {
 	URI         string              `json:"uri"`
 	DateChanges []ReleaseDateChange `json:"date_changes"`
-	Description ReleaseDescription  `json:"description"`
-	Highlight   *highlight          `json:"highlight,omitempty"`
+	// DateChangeCount is the number of times the release has been rescheduled
+	DateChangeCount int `json:"date_change_count"`
+	// LatestChangeNotice is the notice of the change from the latest previous date, i.e. of the latest rescheduling
+	LatestChangeNotice string             `json:"latest_change_notice,omitempty"`
+	Description        ReleaseDescription `json:"description"`
+	Highlight          *highlight         `json:"highlight,omitempty"`
 }
 
 type ReleaseDateChange struct {
@@ -100,6 +110,7 @@ type ESReleaseSourceDocument struct {
 	Survey          string       `json:"survey"`
 	Keywords        []string     `json:"keywords,omitempty"`
 	Language        string       `json:"language,omitempty"`
+	CanonicalTopic  string       `json:"canonical_topic,omitempty"`
 	DateChanges     []dateChange `json:"date_changes,omitempty"`
 	ProvisionalDate string       `json:"provisional_date,omitempty"`
 }
@@ -121,17 +132,46 @@ type ESReleaseResponseAggregations map[aggName]aggregation
 type bucketName string
 type aggregation struct {
 	Buckets map[bucketName]bucketContents `json:"buckets"`
+	// Terms are the buckets of a terms aggregation, which are listed rather than keyed by name
+	Terms []termBucket `json:"-"`
+}
+
+type termBucket struct {
+	Key   string `json:"key"`
+	Count int    `json:"doc_count"`
+}
+
+// UnmarshalJSON decodes the buckets of an aggregation, which are keyed by name for filters aggregations, or else
+// listed for terms aggregations
+func (a *aggregation) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Buckets json.RawMessage `json:"buckets"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	buckets := bytes.TrimSpace(raw.Buckets)
+	if len(buckets) == 0 || string(buckets) == "null" {
+		return nil
+	}
+	if buckets[0] == '[' {
+		return json.Unmarshal(buckets, &a.Terms)
+	}
+	return json.Unmarshal(buckets, &a.Buckets)
 }
 
 type bucketContents struct {
-	Count     int         `json:"doc_count"`
-	Breakdown aggregation `json:"breakdown"`
+	Count        int           `json:"doc_count"`
+	Breakdown    aggregation   `json:"breakdown"`
+	ReleaseDates dateHistogram `json:"release_dates"`
 }
 
 func NewReleaseTransformer() api.ReleaseResponseTransformer {
 	highlightReplacer := strings.NewReplacer("<em class=\"highlight\">", "", "</em>", "")
 	return &ReleaseTransformer{
 		higlightReplacer: highlightReplacer,
+		now:              time.Now,
 	}
 }
 
@@ -202,6 +242,10 @@ func breakdown(source ESReleaseResponse, req query.ReleaseSearchRequest) Breakdo
 
 	b.Census = source.Responses[0].Aggregations["census"].Buckets["census"].Count
 
+	for _, bucket := range source.Responses[0].Aggregations["topics"].Terms {
+		b.Topics = append(b.Topics, models.FilterCount{Type: bucket.Key, Count: bucket.Count})
+	}
+
 	return b
 }
 
@@ -223,12 +267,17 @@ func buildRelease(hit ESReleaseResponseHit, highlighter *strings.Replacer) Relea
 			Keywords:        sd.Keywords,
 			Language:        sd.Language,
 			ProvisionalDate: sd.ProvisionalDate,
+			CanonicalTopic:  sd.CanonicalTopic,
 		},
 	}
 
 	for _, dc := range hit.Source.DateChanges {
 		r.DateChanges = append(r.DateChanges, ReleaseDateChange{Date: dc.PreviousDate, ChangeNotice: dc.ChangeNotice})
 	}
+	r.DateChangeCount = len(r.DateChanges)
+	if latest := latestDateChange(hit.Source.DateChanges); latest != nil {
+		r.LatestChangeNotice = latest.ChangeNotice
+	}
 
 	if highlighter != nil {
 		r.Highlight = &highlight{
@@ -241,6 +290,19 @@ func buildRelease(hit ESReleaseResponseHit, highlighter *strings.Replacer) Relea
 	return r
 }
 
+// latestDateChange returns the change from the latest previous date, which is the latest change as a release is only
+// ever rescheduled from its current date. Previous dates are ISO 8601 dates, so are compared as strings, and of
+// changes from the same date the last one listed is the latest.
+func latestDateChange(changes []dateChange) *dateChange {
+	var latest *dateChange
+	for i := range changes {
+		if latest == nil || changes[i].PreviousDate >= latest.PreviousDate {
+			latest = &changes[i]
+		}
+	}
+	return latest
+}
+
 func isPostponed(release ESReleaseSourceDocument) bool {
 	return release.Finalised && len(release.DateChanges) > 0
 }
@@ -57,3 +57,23 @@ func TestTransformSearchReleaseResponse(t *testing.T) {
 		})
 	})
 }
+
+func TestLatestDateChange(t *testing.T) {
+	t.Parallel()
+	c.Convey("Given the date changes of a release that has been rescheduled twice", t, func() {
+		changes := []dateChange{
+			{PreviousDate: "2024-02-01T07:00:00.000Z", ChangeNotice: "Delayed again"},
+			{PreviousDate: "2024-01-16T07:00:00.000Z", ChangeNotice: "Delayed to include revised data"},
+		}
+
+		c.Convey("Then the latest change is the one from the latest previous date, whatever order they are listed in", func() {
+			c.So(latestDateChange(changes).ChangeNotice, c.ShouldEqual, "Delayed again")
+		})
+	})
+
+	c.Convey("Given a release that has not been rescheduled", t, func() {
+		c.Convey("Then there is no latest change", func() {
+			c.So(latestDateChange(nil), c.ShouldBeNil)
+		})
+	})
+}
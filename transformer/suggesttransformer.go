@@ -0,0 +1,73 @@
+package transformer
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+
+	"github.com/ONSdigital/dp-search-api/api"
+	"github.com/ONSdigital/dp-search-api/models"
+)
+
+// SuggestTransformer represents an instance of the SuggestResponseTransformer interface
+type SuggestTransformer struct{}
+
+// Structs representing the raw elastic search response to a suggest query
+
+type ESSuggestResponse struct {
+	Responses []ESSuggestResponseItem `json:"responses"`
+}
+
+type ESSuggestResponseItem struct {
+	Took int `json:"took"`
+	Hits struct {
+		Hits []ESSuggestResponseHit `json:"hits"`
+	} `json:"hits"`
+}
+
+type ESSuggestResponseHit struct {
+	Source struct {
+		Title string `json:"title"`
+		URI   string `json:"uri"`
+		Type  string `json:"type"`
+	} `json:"_source"`
+}
+
+// NewSuggestTransformer returns a new instance of SuggestTransformer
+func NewSuggestTransformer() api.SuggestResponseTransformer {
+	return &SuggestTransformer{}
+}
+
+// TransformSuggestResponse transforms an elastic search response to a suggest query into a serialised SuggestResponse
+func (t *SuggestTransformer) TransformSuggestResponse(_ context.Context, responseData []byte) ([]byte, error) {
+	var source ESSuggestResponse
+
+	err := json.Unmarshal(responseData, &source)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode elastic search response")
+	}
+
+	if len(source.Responses) != 1 {
+		return nil, errors.New("invalid number of responses from ElasticSearch query")
+	}
+
+	response := source.Responses[0]
+	sr := models.SuggestResponse{
+		Took:        response.Took,
+		Suggestions: make([]models.Suggestion, len(response.Hits.Hits)),
+	}
+	for i, hit := range response.Hits.Hits {
+		sr.Suggestions[i] = models.Suggestion{
+			Title: hit.Source.Title,
+			URI:   hit.Source.URI,
+			Type:  hit.Source.Type,
+		}
+	}
+
+	transformedData, err := json.Marshal(sr)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode transformed response")
+	}
+	return transformedData, nil
+}
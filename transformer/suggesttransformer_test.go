@@ -0,0 +1,50 @@
+package transformer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ONSdigital/dp-search-api/models"
+	c "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTransformSuggestResponse(t *testing.T) {
+	t.Parallel()
+	c.Convey("With a suggest transformer initialised", t, func() {
+		ctx := context.Background()
+		transformer := NewSuggestTransformer()
+
+		c.Convey("Throws error on invalid JSON", func() {
+			_, err := transformer.TransformSuggestResponse(ctx, []byte(`{"invalid":"json"`))
+			c.So(err, c.ShouldNotBeNil)
+			c.So(err.Error(), c.ShouldResemble, "Failed to decode elastic search response: unexpected end of JSON input")
+		})
+
+		c.Convey("Throws error when there is not exactly one response", func() {
+			_, err := transformer.TransformSuggestResponse(ctx, []byte(`{"responses":[]}`))
+			c.So(err, c.ShouldNotBeNil)
+			c.So(err.Error(), c.ShouldResemble, "invalid number of responses from ElasticSearch query")
+		})
+
+		c.Convey("Converts the hits into suggestions", func() {
+			sampleResponse := []byte(`{"responses":[{"took":3,"hits":{"total":{"value":2},"hits":[
+				{"_source":{"title":"Inflation and price indices","uri":"/economy/inflationandpriceindices","type":"product_page"}},
+				{"_source":{"title":"Influenza deaths","uri":"/peoplepopulationandcommunity/influenza","type":"article"}}
+			]}}]}`)
+
+			actual, err := transformer.TransformSuggestResponse(ctx, sampleResponse)
+			c.So(err, c.ShouldBeNil)
+
+			var act models.SuggestResponse
+			c.So(json.Unmarshal(actual, &act), c.ShouldBeNil)
+			c.So(act, c.ShouldResemble, models.SuggestResponse{
+				Took: 3,
+				Suggestions: []models.Suggestion{
+					{Title: "Inflation and price indices", URI: "/economy/inflationandpriceindices", Type: "product_page"},
+					{Title: "Influenza deaths", URI: "/peoplepopulationandcommunity/influenza", Type: "article"},
+				},
+			})
+		})
+	})
+}
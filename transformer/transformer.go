@@ -292,6 +292,11 @@ func (t *Transformer) transform(esresponses *models.EsResponses, highlight bool)
 			transformCounts(response.Aggregations.Dimensions)...,
 		)
 
+		search7xResponse.ReleaseDateHistogram = append(
+			search7xResponse.ReleaseDateHistogram,
+			transformHistogram(response.Aggregations.ReleaseDateHistogram)...,
+		)
+
 		for _, suggestion := range response.Suggest.SearchSuggest {
 			for _, option := range suggestion.Options {
 				search7xResponse.Suggestions = append(search7xResponse.Suggestions, option.Text)
@@ -329,6 +334,22 @@ func transformCounts(counts models.ESDocCounts) []models.FilterCount {
 	return ret
 }
 
+// transformHistogram converts the buckets of a date histogram, keyed by the date each period starts on
+func transformHistogram(histogram models.ESDateHistogram) []models.HistogramCount {
+	if len(histogram.Buckets) == 0 {
+		return nil
+	}
+
+	ret := make([]models.HistogramCount, len(histogram.Buckets))
+	for i, bucket := range histogram.Buckets {
+		ret[i] = models.HistogramCount{
+			Key:   bucket.Key,
+			Count: bucket.Count,
+		}
+	}
+	return ret
+}
+
 func (t *Transformer) buildContentItem(doc models.ESResponseHit, highlight bool) models.Item {
 	esDoc := models.Item{
 		CDID:            doc.Source.CDID,
@@ -104,6 +104,30 @@ func TestTransformCounts(t *testing.T) {
 	})
 }
 
+func TestTransformHistogram(t *testing.T) {
+	c.Convey("Given a date histogram aggregation with buckets", t, func() {
+		histogram := models.ESDateHistogram{
+			Buckets: []models.ESDateBucket{
+				{Key: "2023-01-01", Count: 3},
+				{Key: "2023-04-01", Count: 0},
+			},
+		}
+
+		c.Convey("Then transformHistogram returns a count per period, keyed by its start date", func() {
+			c.So(transformHistogram(histogram), c.ShouldResemble, []models.HistogramCount{
+				{Key: "2023-01-01", Count: 3},
+				{Key: "2023-04-01", Count: 0},
+			})
+		})
+	})
+
+	c.Convey("Given a response without a date histogram aggregation", t, func() {
+		c.Convey("Then transformHistogram returns nothing", func() {
+			c.So(transformHistogram(models.ESDateHistogram{}), c.ShouldBeNil)
+		})
+	})
+}
+
 // Prepare mock ES response
 func prepareESMockResponse() models.EsResponses {
 	esDocument1 := models.ESSourceDocument{